@@ -0,0 +1,322 @@
+// Package app wires the sync engine together so it can run inside another
+// service as well as from cmd/syncer.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"news_fetcher/internal/admin"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/scheduler"
+	"news_fetcher/internal/service"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// Aliases let code outside this module name the types App works with.
+type (
+	Config        = config.Config
+	SyncStats     = domain.SyncStats
+	SyncOptions   = service.SyncOptions
+	SyncResult    = domain.SyncResult
+	ArticleChange = domain.ArticleChange
+	Summary       = scheduler.Summary
+)
+
+// ErrSyncInProgress is returned by Sync while another sync is running.
+var ErrSyncInProgress = service.ErrSyncInProgress
+
+// ErrSourcePaused is returned by Sync while the source is paused through the
+// admin API.
+var ErrSourcePaused = service.ErrSourcePaused
+
+// LoadConfig reads config files, each merged over the ones before it,
+// expanding environment variables and applying defaults, and validates the
+// result.
+func LoadConfig(paths ...string) (*Config, error) {
+	cfg, err := config.Load(paths...)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	return cfg, nil
+}
+
+// App is a running syncer: the ECB source, stores, publisher, scheduler and
+// admin server built from a Config.
+type App struct {
+	logger      *slog.Logger
+	db          *sqlx.DB
+	pub         service.Publisher
+	syncService *service.SyncService
+	sched       *scheduler.Scheduler
+	adminServer *admin.Server
+	// jobs are the background jobs enabled in the config, such as tag
+	// reconciliation and retention.
+	jobs []*scheduler.Job
+
+	mu        sync.Mutex
+	cfg       *Config
+	startedAt time.Time
+	cancel    context.CancelFunc
+	done      chan error // receives the scheduler's result once Start was called
+	jobsDone  sync.WaitGroup
+}
+
+// New connects to the database and builds every component from a validated
+// config, as LoadConfig returns. Nothing runs until Start; Stop releases the
+// resources either way.
+func New(cfg *Config, logger *slog.Logger) (_ *App, err error) {
+	logger.Debug("config", "config", cfg.Redacted())
+
+	db, err := ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			db.Close()
+		}
+	}()
+
+	logger.Info("connected to database")
+
+	// The broker may be down at boot; articles are still stored and publishing
+	// resumes once it reconnects.
+	pub, err := NewPublisher(cfg, logger, true)
+	if err != nil {
+		return nil, fmt.Errorf("create publisher: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			pub.Close()
+		}
+	}()
+
+	ecbSource, err := NewECBSource(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create ecb source: %w", err)
+	}
+
+	articles, err := NewArticleStore(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	schemaCtx, cancelSchema := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelSchema()
+	if err := articles.CheckSchema(schemaCtx); err != nil {
+		return nil, err
+	}
+
+	tags, err := NewTagStore(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retention deletes through the cache too, so deleted articles aren't
+	// still cached as stored.
+	var syncArticles service.ArticleStore = articles
+	var retentionArticles service.RetentionStore = articles
+	if cfg.Sync.ExistingCacheSize > 0 {
+		cached := service.NewCachedArticleStore(articles, cfg.Sync.ExistingCacheSize, cfg.Sync.ExistingCacheTTL)
+		syncArticles, retentionArticles = cached, cached
+	}
+
+	syncService := service.NewSyncService(
+		ecbSource,
+		syncArticles,
+		tags,
+		postgres.NewSyncStateStore(db),
+		postgres.NewRawPayloadStore(db),
+		postgres.NewTransactionManager(db),
+		pub,
+		logger,
+		cfg.SyncFor(ecbSource.ID()),
+	)
+
+	enrichers, err := newEnrichers(cfg.Enrichment)
+	if err != nil {
+		return nil, fmt.Errorf("create enrichers: %w", err)
+	}
+	syncService.SetEnrichers(enrichers, cfg.Enrichment.OnError == config.EnrichOnErrorDrop)
+	syncService.SetPublishFilter(service.SuppressTags(cfg.Publisher.SuppressTags))
+	syncService.SetFailureStore(postgres.NewFailedArticleStore(db))
+	syncService.SetPauseStore(postgres.NewPausedSourceStore(db))
+	syncService.SetSequenceStore(NewSequenceStore(cfg, db))
+
+	health := service.NewHealthTracker(postgres.NewSourceHealthStore(db), logger)
+	syncService.SetHealthTracker(health)
+
+	restoreCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := syncService.RestorePaused(restoreCtx); err != nil {
+		logger.Warn("failed to restore pause state, source is not paused", "error", err)
+	}
+	if err := health.Restore(restoreCtx); err != nil {
+		logger.Warn("failed to restore source health", "error", err)
+	}
+
+	adminServer := admin.NewServer(cfg.Admin.Addr, logger)
+	adminServer.HandleSync(map[string]admin.Syncer{ecbSource.ID(): syncService}, cfg.Sync.Timeout)
+	adminServer.HandlePause(map[string]admin.Pauser{ecbSource.ID(): syncService})
+	adminServer.HandleHealth(health)
+	checks := map[string]admin.Checker{"database": admin.CheckFunc(db.PingContext)}
+	if c, ok := pub.(admin.Checker); ok {
+		checks["publisher"] = c
+	}
+	checks["source_"+ecbSource.ID()] = admin.CheckFunc(syncService.Healthcheck)
+	adminServer.HandleReady(checks)
+
+	scheduled := scheduler.NewWeighted(scheduler.WeightedSource{
+		ID:     ecbSource.ID(),
+		Syncer: syncService,
+		Every:  cfg.Source(ecbSource.ID()).SyncEvery,
+	})
+
+	a := &App{
+		logger:      logger,
+		db:          db,
+		pub:         pub,
+		syncService: syncService,
+		sched:       scheduler.NewScheduler(scheduled, cfg.Sync, scheduler.RealClock{}, logger),
+		adminServer: adminServer,
+		cfg:         cfg,
+	}
+
+	if cfg.Sync.ReconcileTagsInterval > 0 {
+		reconciler, err := NewTagReconciler(cfg, db, logger)
+		if err != nil {
+			return nil, err
+		}
+		a.jobs = append(a.jobs, scheduler.NewJob("reconcile_tags", cfg.Sync.ReconcileTagsInterval, func(ctx context.Context) error {
+			_, err := reconciler.Reconcile(ctx)
+			return err
+		}, scheduler.RealClock{}, logger))
+	}
+
+	if retention := cfg.SyncFor(ecbSource.ID()).Retention; retention > 0 {
+		pruner := service.NewRetentionPruner(retentionArticles, map[string]time.Duration{
+			ecbSource.ID(): retention,
+		}, logger)
+		pruner.SetMaintenance(cfg.Sync.RetentionMaintenance, cfg.Sync.RetentionMaintenanceAfter)
+		a.jobs = append(a.jobs, scheduler.NewJob("retention", cfg.Sync.RetentionInterval, func(ctx context.Context) error {
+			_, err := pruner.Prune(ctx)
+			return err
+		}, scheduler.RealClock{}, logger))
+	}
+
+	return a, nil
+}
+
+// Start starts the admin server and the scheduler in the background. They run
+// until ctx is cancelled or Stop is called.
+func (a *App) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.done != nil {
+		return errors.New("app already started")
+	}
+
+	ctx, a.cancel = context.WithCancel(ctx)
+	a.done = make(chan error, 1)
+	a.startedAt = time.Now()
+
+	a.adminServer.Start()
+	go func() { a.done <- a.sched.Start(ctx) }()
+	for _, job := range a.jobs {
+		a.jobsDone.Add(1)
+		go func() {
+			defer a.jobsDone.Done()
+			job.Start(ctx)
+		}()
+	}
+
+	a.logger.Info("starting news syncer",
+		"source", a.syncService.SourceID(),
+		"interval", a.cfg.Sync.Interval,
+		"max_pages", a.cfg.SyncFor(a.syncService.SourceID()).MaxPagesPerSync,
+	)
+	return nil
+}
+
+// Stop shuts the admin server down, waiting for its syncs, then stops the
+// scheduler and background jobs, waits for the running sync, logs a summary
+// and closes the publisher and the database, in that order.
+func (a *App) Stop() error {
+	a.mu.Lock()
+	cancel, done, startedAt := a.cancel, a.done, a.startedAt
+	a.mu.Unlock()
+
+	var schedErr error
+	if done != nil {
+		// First, so no admin sync starts or keeps running once the publisher and
+		// database close; those still running after the timeout are cancelled.
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := a.adminServer.Shutdown(shutdownCtx); err != nil {
+			a.logger.Warn("failed to shut down admin server", "error", err)
+		}
+
+		cancel()
+		if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+			schedErr = fmt.Errorf("scheduler: %w", err)
+		}
+		a.jobsDone.Wait()
+		a.logShutdownSummary(time.Since(startedAt))
+	}
+
+	if err := a.pub.Close(); err != nil {
+		a.logger.Warn("failed to close publisher", "error", err)
+	}
+	a.logger.Info("publisher closed")
+
+	if err := a.db.Close(); err != nil {
+		a.logger.Warn("failed to close database", "error", err)
+	}
+	a.logger.Info("database closed")
+
+	return schedErr
+}
+
+// Sync runs a sync now, outside the schedule. It fails with
+// ErrSyncInProgress if one is already running.
+func (a *App) Sync(ctx context.Context) (*SyncStats, error) {
+	return a.syncService.Sync(ctx)
+}
+
+// SyncWithOptions is Sync with options, e.g. to list the articles it saved.
+func (a *App) SyncWithOptions(ctx context.Context, opts SyncOptions) (*SyncResult, error) {
+	return a.syncService.SyncWithOptions(ctx, opts)
+}
+
+// Summary returns a summary of the scheduled syncs completed so far.
+func (a *App) Summary() Summary {
+	return a.sched.Summary()
+}
+
+// logShutdownSummary logs what the scheduler accomplished before shutting down.
+func (a *App) logShutdownSummary(uptime time.Duration) {
+	summary := a.sched.Summary()
+	attrs := []any{
+		"uptime", uptime.Round(time.Second),
+		"runs", summary.Runs,
+		"failures", summary.Failures,
+	}
+	if summary.LastStats != nil {
+		attrs = append(attrs, "last_stats", summary.LastStats)
+	}
+	if summary.LastErr != nil {
+		attrs = append(attrs, "last_error", summary.LastErr.Error())
+	}
+	a.logger.Info("shutting down", attrs...)
+}
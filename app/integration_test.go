@@ -0,0 +1,158 @@
+//go:build integration
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"news_fetcher/internal/source/ecb"
+)
+
+type AppIntegrationSuite struct {
+	suite.Suite
+	ctx       context.Context
+	container *postgres.PostgresContainer
+	api       *httptest.Server
+	logger    *slog.Logger
+}
+
+func (s *AppIntegrationSuite) SetupSuite() {
+	s.ctx = context.Background()
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	migrations, err := filepath.Glob("../migrations/*.up.sql")
+	s.Require().NoError(err)
+
+	container, err := postgres.Run(s.ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		postgres.WithInitScripts(migrations...),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	s.Require().NoError(err)
+	s.container = container
+
+	date := time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)
+	s.api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := ecb.APIResponse{PageInfo: ecb.PageInfo{NumPages: 1, PageSize: 20, NumEntries: 2}}
+		for id := int64(1); id <= 2; id++ {
+			resp.Content = append(resp.Content, ecb.Content{ID: id, Title: fmt.Sprintf("Article %d", id), Date: date})
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func (s *AppIntegrationSuite) TearDownSuite() {
+	if s.api != nil {
+		s.api.Close()
+	}
+	if s.container != nil {
+		_ = s.container.Terminate(s.ctx)
+	}
+}
+
+func TestAppIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(AppIntegrationSuite))
+}
+
+// loadConfig writes a config file pointing at the test database and API and
+// loads it, so defaults apply as they would for the binary.
+func (s *AppIntegrationSuite) loadConfig() *Config {
+	host, err := s.container.Host(s.ctx)
+	s.Require().NoError(err)
+	port, err := s.container.MappedPort(s.ctx, "5432/tcp")
+	s.Require().NoError(err)
+
+	path := filepath.Join(s.T().TempDir(), "config.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte(fmt.Sprintf(`
+database:
+  host: %s
+  port: %d
+  user: test
+  password: test
+  dbname: test_db
+  sslmode: disable
+publisher:
+  type: none
+api:
+  base_url: %s
+sync:
+  interval: 1h
+  run_on_start: false
+admin:
+  addr: 127.0.0.1:0
+`, host, port.Int(), s.api.URL)), 0o600))
+
+	cfg, err := LoadConfig(path)
+	s.Require().NoError(err)
+	return cfg
+}
+
+func (s *AppIntegrationSuite) TestSyncOnDemand() {
+	a, err := New(s.loadConfig(), s.logger)
+	s.Require().NoError(err)
+	defer a.Stop()
+
+	stats, err := a.Sync(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(2, stats.Fetched)
+
+	// Nothing changed upstream, so the second run has nothing to do.
+	stats, err = a.Sync(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(0, stats.New+stats.Updated)
+}
+
+func (s *AppIntegrationSuite) TestStartStop() {
+	a, err := New(s.loadConfig(), s.logger)
+	s.Require().NoError(err)
+
+	s.Require().NoError(a.Start(s.ctx))
+	s.Error(a.Start(s.ctx))
+
+	s.NoError(a.Stop())
+	s.Equal(0, a.Summary().Runs)
+}
+
+func (s *AppIntegrationSuite) TestNew_DumpDirRequiresDebug() {
+	cfg := s.loadConfig()
+	cfg.API.DumpDir = s.T().TempDir()
+	cfg.LogLevel = "info"
+
+	_, err := New(cfg, s.logger)
+	s.ErrorContains(err, "api.dump_dir requires log_level debug")
+
+	cfg.LogLevel = "debug"
+	a, err := New(cfg, s.logger)
+	s.Require().NoError(err)
+	s.NoError(a.Stop())
+}
+
+func (s *AppIntegrationSuite) TestNew_RetentionWithinHistoricalWindow() {
+	cfg := s.loadConfig()
+	cfg.Sync.MaxHistoricalDays = 30
+	cfg.Sync.Retention = 24 * time.Hour
+
+	_, err := New(cfg, s.logger)
+	s.ErrorContains(err, "retention must be longer than max_historical_days (30d)")
+}
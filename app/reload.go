@@ -0,0 +1,172 @@
+package app
+
+import (
+	"reflect"
+	"slices"
+
+	"news_fetcher/internal/config"
+)
+
+// Reload applies the fields of next that can change at runtime: sync
+// interval, max pages, historical days (global and per source), incremental
+// mode, order, article cap, quarantine threshold, tag error tolerance, quiet
+// period and log level, which stays debug while responses are dumped. Changes
+// to anything else are only reported, and the current values stay in effect
+// until a restart. It returns the config now in effect; the caller applies its
+// log level to its logger.
+func (a *App) Reload(next *Config) *Config {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	current := a.cfg
+	logger := a.logger
+
+	if next.Database != current.Database {
+		logger.Warn("database config changed, requires restart")
+	}
+	if !reflect.DeepEqual(next.RabbitMQ, current.RabbitMQ) {
+		logger.Warn("rabbitmq config changed, requires restart")
+	}
+	if next.Webhook != current.Webhook {
+		logger.Warn("webhook config changed, requires restart")
+	}
+	if !reflect.DeepEqual(next.API, current.API) {
+		logger.Warn("api config changed, requires restart")
+	}
+	if next.Sync.Timeout != current.Sync.Timeout {
+		logger.Warn("sync timeout changed, requires restart")
+	}
+	sourceID := a.syncService.SourceID()
+	if next.SyncFor(sourceID).Retention != current.SyncFor(sourceID).Retention ||
+		next.Sync.RetentionInterval != current.Sync.RetentionInterval ||
+		next.Sync.RetentionMaintenance != current.Sync.RetentionMaintenance ||
+		next.Sync.RetentionMaintenanceAfter != current.Sync.RetentionMaintenanceAfter {
+		logger.Warn("retention config changed, requires restart")
+	}
+	nextSource, currentSource := next.Source(sourceID), current.Source(sourceID)
+	if nextSource.SyncEvery != currentSource.SyncEvery {
+		logger.Warn("source sync_every changed, requires restart")
+	}
+	if nextSource.Timezone != currentSource.Timezone {
+		logger.Warn("source timezone changed, requires restart")
+	}
+	if nextSource.AcceptLanguage != currentSource.AcceptLanguage {
+		logger.Warn("source accept_language changed, requires restart")
+	}
+	if nextSource.FetchConcurrency != currentSource.FetchConcurrency {
+		logger.Warn("source fetch_concurrency changed, requires restart")
+	}
+	if next.Sync.RunOnStart != current.Sync.RunOnStart {
+		logger.Warn("run on start changed, requires restart")
+	}
+	if !slices.Equal(next.Sync.ProtectedColumns, current.Sync.ProtectedColumns) {
+		logger.Warn("protected columns changed, requires restart")
+	}
+	if !slices.Equal(next.Sync.UpdatedColumns, current.Sync.UpdatedColumns) {
+		logger.Warn("updated columns changed, requires restart")
+	}
+	if next.Sync.TagIdentity != current.Sync.TagIdentity {
+		logger.Warn("tag identity changed, requires restart")
+	}
+	if next.Sync.NormalizeTagLabels != current.Sync.NormalizeTagLabels {
+		logger.Warn("tag label normalization changed, requires restart")
+	}
+	if next.Sync.KeepRevisions != current.Sync.KeepRevisions {
+		logger.Warn("keep revisions changed, requires restart")
+	}
+	if next.Sync.ExistingCacheSize != current.Sync.ExistingCacheSize || next.Sync.ExistingCacheTTL != current.Sync.ExistingCacheTTL {
+		logger.Warn("existing articles cache changed, requires restart")
+	}
+	if next.Publisher.Type != current.Publisher.Type {
+		logger.Warn("publisher type changed, requires restart")
+	}
+	if next.Publisher.SequenceNumbers != current.Publisher.SequenceNumbers {
+		logger.Warn("sequence numbers changed, requires restart")
+	}
+	if next.Sync.MinInterval != current.Sync.MinInterval || next.Sync.MaxFailureBackoff != current.Sync.MaxFailureBackoff {
+		logger.Warn("scheduler min interval or failure backoff changed, requires restart")
+	}
+	if next.Sync.ReconcileTagsInterval != current.Sync.ReconcileTagsInterval {
+		logger.Warn("tag reconciliation interval changed, requires restart")
+	}
+	if !slices.Equal(next.Publisher.SuppressTags, current.Publisher.SuppressTags) {
+		logger.Warn("suppressed tags changed, requires restart")
+	}
+	if !reflect.DeepEqual(next.Enrichment, current.Enrichment) {
+		logger.Warn("enrichment config changed, requires restart")
+	}
+	if next.Admin != current.Admin {
+		logger.Warn("admin config changed, requires restart")
+	}
+
+	applied := *current
+	// The source keeps dumping responses until a restart, and dumps are only
+	// allowed at debug level, so the level can't be lowered while it does.
+	if current.API.DumpDir != "" && next.LogLevel != current.LogLevel {
+		logger.Warn("log level can't change while api.dump_dir is in effect, requires restart",
+			"log_level", current.LogLevel)
+	} else {
+		applied.LogLevel = next.LogLevel
+	}
+	applied.Sync.Interval = next.Sync.Interval
+	applied.Sync.MaxPagesPerSync = next.Sync.MaxPagesPerSync
+	applied.Sync.MaxHistoricalDays = next.Sync.MaxHistoricalDays
+	applied.Sync.DisableDateFilter = next.Sync.DisableDateFilter
+	applied.Sync.Incremental = next.Sync.Incremental
+	applied.Sync.Order = next.Sync.Order
+	applied.Sync.MaxArticlesPerSync = next.Sync.MaxArticlesPerSync
+	applied.Sync.QuarantineAfter = next.Sync.QuarantineAfter
+	applied.Sync.TolerateTagErrors = next.Sync.TolerateTagErrors
+	applied.Sync.QuietPeriod = next.Sync.QuietPeriod
+	applied.Sources = reloadSources(current.Sources, next.Sources)
+
+	sourceCfg := applied.SyncFor(sourceID)
+
+	a.syncService.SetConfig(sourceCfg)
+	if applied.Sync.Interval != current.Sync.Interval {
+		a.sched.SetInterval(applied.Sync.Interval)
+	}
+
+	logger.Info("config reloaded",
+		"interval", applied.Sync.Interval,
+		"max_pages", sourceCfg.MaxPagesPerSync,
+		"max_historical_days", sourceCfg.MaxHistoricalDays,
+		"log_level", applied.LogLevel,
+	)
+
+	a.cfg = &applied
+	return &applied
+}
+
+// reloadSources returns next's source overrides with the fields that require
+// a restart (timezone, accept_language, fetch_concurrency, retention and
+// sync_every) kept as they are in current, including for sources next drops.
+func reloadSources(current, next []config.SourceConfig) []config.SourceConfig {
+	restartOnly := func(src config.SourceConfig) config.SourceConfig {
+		return config.SourceConfig{
+			ID:               src.ID,
+			Timezone:         src.Timezone,
+			AcceptLanguage:   src.AcceptLanguage,
+			FetchConcurrency: src.FetchConcurrency,
+			Retention:        src.Retention,
+			SyncEvery:        src.SyncEvery,
+		}
+	}
+
+	sources := make([]config.SourceConfig, 0, len(next))
+	for _, src := range next {
+		kept := config.SourceConfig{ID: src.ID}
+		if i := slices.IndexFunc(current, func(c config.SourceConfig) bool { return c.ID == src.ID }); i >= 0 {
+			kept = restartOnly(current[i])
+		}
+		kept.MaxPagesPerSync = src.MaxPagesPerSync
+		kept.MaxHistoricalDays = src.MaxHistoricalDays
+		sources = append(sources, kept)
+	}
+	for _, src := range current {
+		if !slices.ContainsFunc(next, func(n config.SourceConfig) bool { return n.ID == src.ID }) {
+			sources = append(sources, restartOnly(src))
+		}
+	}
+	return sources
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/enrich"
+	"news_fetcher/internal/publisher"
+	"news_fetcher/internal/publisher/webhook"
+	"news_fetcher/internal/service"
+	"news_fetcher/internal/source/ecb"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// NewECBSource creates the ECB source from the api and per-source settings.
+func NewECBSource(cfg *config.Config, logger *slog.Logger) (*ecb.Source, error) {
+	sourceCfg := cfg.Source(ecb.SourceID)
+	location, err := sourceCfg.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.API.DumpDir != "" {
+		logger.Warn("dumping fetched responses, not for production", "dir", cfg.API.DumpDir)
+	}
+
+	return ecb.New(ecb.Config{
+		BaseURL:            cfg.API.BaseURL,
+		PageSize:           cfg.API.PageSize,
+		PageDelay:          cfg.API.PageDelay,
+		Pagination:         cfg.API.Pagination,
+		StartPage:          cfg.API.StartPage,
+		PageStep:           cfg.API.PageStep,
+		CursorParam:        cfg.API.CursorParam,
+		MaxZeroIDRatio:     cfg.API.Validation.MaxZeroIDRatio,
+		MaxEmptyTitleRatio: cfg.API.Validation.MaxEmptyTitleRatio,
+		Timeout:            cfg.API.Timeout,
+		MaxAttempts:        cfg.API.Retry.MaxAttempts,
+		InitialBackoff:     cfg.API.Retry.InitialBackoff,
+		MaxBackoff:         cfg.API.Retry.MaxBackoff,
+		CategoryTags:       cfg.API.CategoryTags,
+		Location:           location,
+		AcceptLanguage:     sourceCfg.AcceptLanguage,
+		FetchConcurrency:   sourceCfg.FetchConcurrency,
+		ZeroIDTags:         cfg.API.ZeroIDTags,
+		MaxResponseBytes:   cfg.API.MaxResponseBytes,
+		DumpDir:            cfg.API.DumpDir,
+	}, logger), nil
+}
+
+// NewSequenceStore returns the store numbering published messages, or nil if
+// publisher.sequence_numbers is off.
+func NewSequenceStore(cfg *config.Config, db *sqlx.DB) service.SequenceStore {
+	if !cfg.Publisher.SequenceNumbers {
+		return nil
+	}
+	return postgres.NewSequenceStore(db)
+}
+
+// NewArticleStore creates the article store with the configured protected and
+// updated columns, tag identity and revisions.
+func NewArticleStore(cfg *config.Config, db *sqlx.DB) (*postgres.ArticleStore, error) {
+	store := postgres.NewArticleStore(db)
+	if err := store.Protect(cfg.Sync.ProtectedColumns...); err != nil {
+		return nil, fmt.Errorf("sync.protected_columns: %w", err)
+	}
+	if err := store.UpdateOnly(cfg.Sync.UpdatedColumns...); err != nil {
+		return nil, fmt.Errorf("sync.updated_columns: %w", err)
+	}
+	store.SetTagIdentity(cfg.Sync.TagIdentity)
+	store.NormalizeTagLabels(cfg.Sync.NormalizeTagLabels)
+	store.KeepRevisions(cfg.Sync.KeepRevisions)
+	return store, nil
+}
+
+// NewTagStore creates the tag store with the configured tag identity and
+// label normalization.
+func NewTagStore(cfg *config.Config, db *sqlx.DB) (*postgres.TagStore, error) {
+	store := postgres.NewTagStore(db)
+	if err := store.SetIdentity(cfg.Sync.TagIdentity); err != nil {
+		return nil, fmt.Errorf("sync.tag_identity: %w", err)
+	}
+	store.NormalizeLabels(cfg.Sync.NormalizeTagLabels)
+	return store, nil
+}
+
+// NewTagReconciler creates the reconciler that re-links articles whose tag
+// links differ from their recorded tags.
+func NewTagReconciler(cfg *config.Config, db *sqlx.DB, logger *slog.Logger) (*service.TagReconciler, error) {
+	articles, err := NewArticleStore(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := NewTagStore(cfg, db)
+	if err != nil {
+		return nil, err
+	}
+	return service.NewTagReconciler(articles, tags, postgres.NewTransactionManager(db), logger), nil
+}
+
+// newEnrichers creates the configured enrichers, in order.
+func newEnrichers(cfg config.EnrichmentConfig) ([]service.Enricher, error) {
+	switch cfg.OnError {
+	case config.EnrichOnErrorLog, config.EnrichOnErrorDrop:
+	default:
+		return nil, fmt.Errorf("unknown enrichment on_error %q", cfg.OnError)
+	}
+
+	enrichers := make([]service.Enricher, 0, len(cfg.Enrichers))
+	for _, name := range cfg.Enrichers {
+		switch name {
+		case enrich.ReadingTimeName:
+			enrichers = append(enrichers, enrich.NewReadingTime(cfg.WordsPerMinute))
+		default:
+			return nil, fmt.Errorf("unknown enricher %q", name)
+		}
+	}
+	return enrichers, nil
+}
+
+// ConnectDB opens the database and applies the pool settings.
+func ConnectDB(cfg config.DatabaseConfig, logger *slog.Logger) (*sqlx.DB, error) {
+	logger.Debug("connecting to database", "dsn", config.RedactDSN(cfg.DSN()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	db, err := sqlx.ConnectContext(ctx, "postgres", cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	return db, nil
+}
+
+// NewPublisher creates the configured publisher. A lazy RabbitMQ publisher
+// doesn't fail if the broker is unreachable; it connects on first use instead.
+func NewPublisher(cfg *config.Config, logger *slog.Logger, lazy bool) (service.Publisher, error) {
+	switch cfg.Publisher.Type {
+	case "none":
+		logger.Warn("publisher disabled, articles will not be published")
+		return publisher.NewNull(logger), nil
+	case "rabbitmq":
+		logger.Debug("rabbitmq publisher", "url", config.RedactURL(cfg.RabbitMQ.URL))
+		rabbitCfg := rabbitMQConfig(cfg.RabbitMQ)
+		if lazy {
+			return publisher.NewLazyRabbitMQ(rabbitCfg, logger)
+		}
+		return publisher.NewRabbitMQ(rabbitCfg, logger)
+	case "webhook":
+		if cfg.Webhook.URL == "" {
+			return nil, fmt.Errorf("webhook publisher requires webhook.url")
+		}
+		logger.Debug("webhook publisher", "url", config.RedactURL(cfg.Webhook.URL))
+		return webhook.New(webhook.Config{
+			URL:            cfg.Webhook.URL,
+			Secret:         cfg.Webhook.Secret,
+			Timeout:        cfg.Webhook.Timeout,
+			MaxAttempts:    cfg.Webhook.Retry.MaxAttempts,
+			InitialBackoff: cfg.Webhook.Retry.InitialBackoff,
+			MaxBackoff:     cfg.Webhook.Retry.MaxBackoff,
+		}, logger), nil
+	default:
+		return nil, fmt.Errorf("unknown publisher type %q", cfg.Publisher.Type)
+	}
+}
+
+func rabbitMQConfig(cfg config.RabbitMQConfig) publisher.Config {
+	bindings := make([]publisher.Binding, len(cfg.Bindings))
+	for i, b := range cfg.Bindings {
+		bindings[i] = publisher.Binding{RoutingKey: b.RoutingKey, QueueName: b.QueueName}
+	}
+
+	return publisher.Config{
+		URL:               cfg.URL,
+		Exchange:          cfg.Exchange,
+		RoutingKey:        cfg.RoutingKey,
+		QueueName:         cfg.QueueName,
+		Bindings:          bindings,
+		Fields:            cfg.Fields,
+		ExcludeFields:     cfg.ExcludeFields,
+		Format:            cfg.Format,
+		CompressThreshold: cfg.CompressThreshold,
+		SigningSecret:     cfg.SigningSecret,
+
+		SyncCompletedRoutingKey: cfg.SyncCompletedRoutingKey,
+		Passive:                 cfg.Passive,
+		MessageTTL:              cfg.MessageTTL,
+		Priority:                cfg.Priority,
+	}
+}
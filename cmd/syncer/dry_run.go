@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/signal"
+	"syscall"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+)
+
+// runDryRun fetches and compares the articles like a sync, without saving,
+// publishing or recording anything, and returns what the sync would do.
+func runDryRun(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) (*app.SyncResult, error) {
+	fs := flag.NewFlagSet("dry-run", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	a, err := app.New(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Stop()
+
+	return a.SyncWithOptions(ctx, app.SyncOptions{DryRun: true})
+}
+
+// printDryRun writes the counts of a dry run, then each article it would
+// create or update, with the fields an update would change.
+func printDryRun(w io.Writer, result *app.SyncResult) error {
+	if _, err := fmt.Fprintf(w, "source %s: %d new, %d updated, %d skipped, %d errors, %d deferred (dry run, nothing saved)\n",
+		result.SourceID, result.New, result.Updated, result.Skipped, result.Errors, result.Deferred); err != nil {
+		return err
+	}
+
+	for _, change := range result.Changes {
+		var err error
+		if change.Action == domain.ActionCreated {
+			_, err = fmt.Fprintf(w, "created %d\n", change.ExternalID)
+		} else {
+			_, err = fmt.Fprintf(w, "updated %d (id %d)\n", change.ExternalID, change.ID)
+		}
+		if err != nil {
+			return err
+		}
+		for _, field := range change.Diff {
+			if _, err := fmt.Fprintf(w, "  %s: %s -> %s\n", field.Field, field.Old, field.New); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/domain"
+)
+
+type DryRunTestSuite struct {
+	suite.Suite
+}
+
+func TestDryRunTestSuite(t *testing.T) {
+	suite.Run(t, new(DryRunTestSuite))
+}
+
+func (s *DryRunTestSuite) TestPrintDryRun() {
+	var buf bytes.Buffer
+	result := &app.SyncResult{
+		SyncStats: domain.SyncStats{SourceID: "ecb", New: 1, Updated: 1, Skipped: 3},
+		Changes: []domain.ArticleChange{
+			{ExternalID: 1, Action: domain.ActionCreated},
+			{ID: 200, ExternalID: 2, Action: domain.ActionUpdated, Diff: []domain.FieldChange{
+				{Field: "title", Old: []byte(`"Old title"`), New: []byte(`"New title"`)},
+			}},
+		},
+	}
+
+	s.Require().NoError(printDryRun(&buf, result))
+
+	s.Equal(`source ecb: 1 new, 1 updated, 3 skipped, 0 errors, 0 deferred (dry run, nothing saved)
+created 1
+updated 2 (id 200)
+  title: "Old title" -> "New title"
+`, buf.String())
+}
@@ -3,141 +3,246 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 
-	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
-
+	"news_fetcher/app"
 	"news_fetcher/internal/config"
-	"news_fetcher/internal/publisher"
-	"news_fetcher/internal/scheduler"
-	"news_fetcher/internal/service"
-	"news_fetcher/internal/source/ecb"
-	"news_fetcher/internal/storage/postgres"
 )
 
 func main() {
-	configPath := flag.String("config", "config.yaml", "path to config file")
+	var configPaths pathList
+	flag.Var(&configPaths, "config", "path to config file; repeat it or list several, comma-separated, to merge them in order (default config.yaml)")
+	validateConfig := flag.Bool("validate-config", false, "check the config file and exit without connecting to anything")
+	output := flag.String("output", outputText, "text, or json to print the status and any error as a single JSON object, with logs on stderr")
 	flag.Parse()
+	if len(configPaths) == 0 {
+		configPaths = pathList{"config.yaml"}
+	}
+
+	switch *output {
+	case outputText:
+	case outputJSON:
+		logOutput = os.Stderr
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output %q\n", *output)
+		os.Exit(2)
+	}
 
 	// Setup logger
 	logger := setupLogger("info")
 
 	// Load configuration
-	cfg, err := config.Load(*configPath)
+	cfg, err := config.Load(configPaths...)
 	if err != nil {
+		if *output == outputJSON {
+			exitWith(logger, *output, commandName(flag.Arg(0)), fmt.Errorf("load config: %w", err))
+		}
 		logger.Error("failed to load config", "error", err)
 		os.Exit(1)
 	}
 
-	logger = setupLogger(cfg.LogLevel)
-
-	logger.Debug("database config",
-		"host", cfg.Database.Host,
-		"port", cfg.Database.Port,
-		"dbname", cfg.Database.DBName,
-	)
-
-	dbCtx, dbCancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout)
-	defer dbCancel()
-
-	db, err := sqlx.ConnectContext(dbCtx, "postgres", cfg.Database.DSN())
-	if err != nil {
-		logger.Error("failed to connect to database", "error", err)
+	if err := cfg.Validate(); err != nil {
+		if *validateConfig {
+			fmt.Fprintf(os.Stderr, "%s is invalid:\n%v\n", configPaths.String(), err)
+			os.Exit(1)
+		}
+		if *output == outputJSON {
+			exitWith(logger, *output, commandName(flag.Arg(0)), fmt.Errorf("invalid config: %w", err))
+		}
+		logger.Error("invalid config", "error", err)
 		os.Exit(1)
 	}
-	defer db.Close()
+	if *validateConfig {
+		fmt.Printf("%s is valid\n", configPaths.String())
+		return
+	}
 
-	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	logger = setupLogger(cfg.LogLevel)
 
-	logger.Info("connected to database")
+	switch cmd := flag.Arg(0); cmd {
+	case "":
+		if err := runSyncer(configPaths, cfg, logger); err != nil {
+			exitWith(logger, *output, "syncer", err)
+		}
+	case "status":
+		statuses, err := loadStatus(context.Background(), cfg, logger)
+		if err != nil {
+			exitWith(logger, *output, "status", err)
+		}
+		if *output == outputJSON {
+			err = writeResult(os.Stdout, "status", statuses, nil)
+		} else {
+			err = printStatus(os.Stdout, statuses)
+		}
+		if err != nil {
+			exitWith(logger, *output, "status", err)
+		}
+	case "republish":
+		if err := runRepublish(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "republish", err)
+		}
+	case "reprocess":
+		if err := runReprocess(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "reprocess", err)
+		}
+	case "quarantine":
+		if err := runQuarantine(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "quarantine", err)
+		}
+	case "reconcile-tags":
+		if err := runReconcileTags(context.Background(), cfg, logger); err != nil {
+			exitWith(logger, *output, "reconcile-tags", err)
+		}
+	case "reset":
+		if err := runReset(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "reset", err)
+		}
+	case "migrate":
+		if err := runMigrate(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "migrate", err)
+		}
+	case "verify":
+		// The counts are printed even if they drifted too far apart.
+		result, err := runVerify(context.Background(), cfg, logger, flag.Args()[1:])
+		if result != nil && *output == outputText {
+			_ = printVerify(os.Stdout, result)
+		}
+		if err != nil {
+			exitWith(logger, *output, "verify", err)
+		}
+		if *output == outputJSON {
+			if err := writeResult(os.Stdout, "verify", result, nil); err != nil {
+				exitWith(logger, *output, "verify", err)
+			}
+		}
+	case "set-status":
+		if err := runSetStatus(context.Background(), cfg, logger, flag.Args()[1:]); err != nil {
+			exitWith(logger, *output, "set-status", err)
+		}
+	case "dry-run":
+		result, err := runDryRun(context.Background(), cfg, logger, flag.Args()[1:])
+		if err != nil {
+			exitWith(logger, *output, "dry-run", err)
+		}
+		if *output == outputJSON {
+			err = writeResult(os.Stdout, "dry-run", result, nil)
+		} else {
+			err = printDryRun(os.Stdout, result)
+		}
+		if err != nil {
+			exitWith(logger, *output, "dry-run", err)
+		}
+	default:
+		logger.Error("unknown command", "command", cmd)
+		os.Exit(2)
+	}
+}
 
-	// Initialize RabbitMQ publisher
-	rabbitMQ, err := publisher.NewRabbitMQ(publisher.Config{
-		URL:        cfg.RabbitMQ.URL,
-		Exchange:   cfg.RabbitMQ.Exchange,
-		RoutingKey: cfg.RabbitMQ.RoutingKey,
-		QueueName:  cfg.RabbitMQ.QueueName,
-	}, logger)
+func runSyncer(configPaths []string, cfg *config.Config, logger *slog.Logger) error {
+	a, err := app.New(cfg, logger)
 	if err != nil {
-		logger.Error("failed to connect to rabbitmq", "error", err)
-		os.Exit(1)
+		return err
 	}
-	defer rabbitMQ.Close()
-
-	// Initialize stores
-	articleStore := postgres.NewArticleStore(db)
-	tagStore := postgres.NewTagStore(db)
-	syncStateStore := postgres.NewSyncStateStore(db)
-	txManager := postgres.NewTransactionManager(db)
-
-	// Initialize ECB source
-	ecbSource := ecb.New(ecb.Config{
-		BaseURL:        cfg.API.BaseURL,
-		PageSize:       cfg.API.PageSize,
-		PageDelay:      cfg.API.PageDelay,
-		Timeout:        cfg.API.Timeout,
-		MaxAttempts:    cfg.API.Retry.MaxAttempts,
-		InitialBackoff: cfg.API.Retry.InitialBackoff,
-		MaxBackoff:     cfg.API.Retry.MaxBackoff,
-	}, logger)
-
-	// Create sync service for ECB source
-	syncService := service.NewSyncService(
-		ecbSource,
-		articleStore,
-		tagStore,
-		syncStateStore,
-		txManager,
-		rabbitMQ,
-		logger,
-		cfg.Sync,
-	)
-
-	sched := scheduler.NewScheduler(syncService, cfg.Sync, logger)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	go func() {
-		sigCh := make(chan os.Signal, 1)
-		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	if err := a.Start(ctx); err != nil {
+		a.Stop()
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for {
 		sig := <-sigCh
-		logger.Info("received shutdown signal", "signal", sig)
-		cancel()
-	}()
-
-	logger.Info("starting news syncer",
-		"source", ecbSource.Name(),
-		"interval", cfg.Sync.Interval,
-		"max_pages", cfg.Sync.MaxPagesPerSync,
-	)
-
-	if err := sched.Start(ctx); err != nil && err != context.Canceled {
-		logger.Error("scheduler error", "error", err)
-		os.Exit(1)
+		if sig != syscall.SIGHUP {
+			logger.Info("received shutdown signal", "signal", sig)
+			return a.Stop()
+		}
+
+		logger.Info("received reload signal")
+		next, err := config.Load(configPaths...)
+		if err != nil {
+			logger.Error("failed to reload config", "error", err)
+			continue
+		}
+		// An invalid config is rejected as a whole; the current one stays.
+		if err := next.Validate(); err != nil {
+			logger.Error("reloaded config is invalid, keeping the current one", "error", err)
+			continue
+		}
+		applied := a.Reload(next)
+		logLevel.Set(parseLogLevel(applied.LogLevel))
 	}
 }
 
+// pathList collects the -config flag, which may be repeated or a
+// comma-separated list.
+type pathList []string
+
+func (p *pathList) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *pathList) Set(value string) error {
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			*p = append(*p, path)
+		}
+	}
+	return nil
+}
+
+// exitWith reports that command failed with err, as a log line or, with
+// -output json, as a JSON object on stdout, and exits with status 1.
+func exitWith(logger *slog.Logger, output, command string, err error) {
+	if output == outputJSON {
+		_ = writeResult(os.Stdout, command, nil, err)
+	} else {
+		logger.Error(command+" failed", "error", err)
+	}
+	os.Exit(1)
+}
+
+// commandName returns the name a command is reported under.
+func commandName(arg string) string {
+	if arg == "" {
+		return "syncer"
+	}
+	return arg
+}
+
+// logOutput is where logs are written: stdout, or stderr with -output json so
+// stdout only carries the result.
+var logOutput io.Writer = os.Stdout
+
+// logLevel is shared by every logger so a config reload can change the level in place.
+var logLevel = new(slog.LevelVar)
+
 func setupLogger(level string) *slog.Logger {
-	var logLevel slog.Level
+	logLevel.Set(parseLogLevel(level))
+
+	opts := &slog.HandlerOptions{Level: logLevel}
+	handler := slog.NewJSONHandler(logOutput, opts)
+	return slog.New(handler)
+}
+
+func parseLogLevel(level string) slog.Level {
 	switch level {
 	case "debug":
-		logLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case "warn":
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	}
-
-	opts := &slog.HandlerOptions{Level: logLevel}
-	handler := slog.NewJSONHandler(os.Stdout, opts)
-	return slog.New(handler)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// runMigrate lists the applied and pending migrations or applies the pending
+// ones.
+func runMigrate(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: migrate status|up [flags]")
+	}
+
+	var run func(context.Context, *postgres.Migrator) error
+	switch args[0] {
+	case "status":
+		run = printMigrationStatus
+	case "up":
+		run = func(ctx context.Context, m *postgres.Migrator) error {
+			applied, err := m.Up(ctx)
+			fmt.Printf("applied %d migrations\n", applied)
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ContinueOnError)
+	path := fs.String("path", "migrations", "directory of the migration files")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	migrations, err := postgres.LoadMigrations(os.DirFS(*path))
+	if err != nil {
+		return fmt.Errorf("load migrations: %w", err)
+	}
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	return run(ctx, postgres.NewMigrator(db, migrations))
+}
+
+func printMigrationStatus(ctx context.Context, m *postgres.Migrator) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS")
+	for _, st := range statuses {
+		status := "pending"
+		switch {
+		case st.Dirty:
+			status = "dirty"
+		case st.Applied:
+			status = "applied"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\n", st.Version, st.Name, status)
+	}
+	return w.Flush()
+}
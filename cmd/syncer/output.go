@@ -0,0 +1,33 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Output formats selected with -output.
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+// commandResult is what -output json prints for a command: a single object
+// with the command's result if it succeeded or its error if it failed.
+type commandResult struct {
+	Command string `json:"command"`
+	OK      bool   `json:"ok"`
+	Result  any    `json:"result,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// writeResult writes the result of command, or err if it failed, to w as a
+// single JSON object on one line.
+func writeResult(w io.Writer, command string, result any, err error) error {
+	out := commandResult{Command: command, OK: err == nil}
+	if err != nil {
+		out.Error = err.Error()
+	} else {
+		out.Result = result
+	}
+	return json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+)
+
+type OutputTestSuite struct {
+	suite.Suite
+}
+
+func TestOutputTestSuite(t *testing.T) {
+	suite.Run(t, new(OutputTestSuite))
+}
+
+func (s *OutputTestSuite) TestWriteResult_Success() {
+	lastSynced := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	statuses := toSourceStatus([]domain.SyncState{
+		{SourceID: "ecb", LastSyncedAt: lastSynced, TotalSynced: 42, LastArticleID: 7, LastPublishedAt: &lastSynced},
+		{SourceID: "other"},
+	})
+
+	var buf bytes.Buffer
+	s.Require().NoError(writeResult(&buf, "status", statuses, nil))
+
+	s.JSONEq(`{
+		"command": "status",
+		"ok": true,
+		"result": [
+			{"source_id": "ecb", "last_synced_at": "2025-01-15T10:00:00Z", "total_synced": 42, "last_article_id": 7, "last_published_at": "2025-01-15T10:00:00Z"},
+			{"source_id": "other", "last_synced_at": null, "total_synced": 0, "last_article_id": 0, "last_published_at": null}
+		]
+	}`, buf.String())
+	s.Equal(1, bytes.Count(buf.Bytes(), []byte("\n")))
+}
+
+func (s *OutputTestSuite) TestWriteResult_Error() {
+	var buf bytes.Buffer
+	s.Require().NoError(writeResult(&buf, "status", []sourceStatus{}, errors.New("connect to database: connection refused")))
+
+	s.JSONEq(`{
+		"command": "status",
+		"ok": false,
+		"error": "connect to database: connection refused"
+	}`, buf.String())
+}
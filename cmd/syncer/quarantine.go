@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// runQuarantine lists or clears the articles quarantined after repeatedly
+// failing to save.
+func runQuarantine(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: quarantine list|clear [flags]")
+	}
+
+	switch args[0] {
+	case "list":
+		return runQuarantineList(ctx, cfg, logger, args[1:])
+	case "clear":
+		return runQuarantineClear(ctx, cfg, logger, args[1:])
+	default:
+		return fmt.Errorf("unknown quarantine command %q", args[0])
+	}
+}
+
+func runQuarantineList(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("quarantine list", flag.ContinueOnError)
+	sourceID := fs.String("source", "", "only list this source's articles")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	failed, err := postgres.NewFailedArticleStore(db).ListQuarantined(ctx, *sourceID)
+	if err != nil {
+		return fmt.Errorf("list quarantined articles: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tKEY\tATTEMPTS\tQUARANTINED AT\tLAST ERROR")
+	for _, f := range failed {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+			f.SourceID, f.ExternalKey, f.Attempts, f.QuarantinedAt.Format(time.RFC3339), f.LastError)
+	}
+	return w.Flush()
+}
+
+func runQuarantineClear(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("quarantine clear", flag.ContinueOnError)
+	sourceID := fs.String("source", "", "source whose articles to release")
+	keyList := fs.String("ids", "", "comma-separated keys (external IDs) to release (default all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *sourceID == "" {
+		return errors.New("--source is required")
+	}
+
+	keys := parseKeys(*keyList)
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	released, err := postgres.NewFailedArticleStore(db).ClearQuarantine(ctx, *sourceID, keys)
+	if err != nil {
+		return fmt.Errorf("clear quarantine: %w", err)
+	}
+
+	logger.Info("quarantine cleared", "source", *sourceID, "released", released)
+	return nil
+}
+
+// parseKeys parses a comma-separated list of article keys.
+func parseKeys(list string) []string {
+	var keys []string
+	for _, field := range strings.Split(list, ",") {
+		if key := strings.TrimSpace(field); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+)
+
+// runReconcileTags re-links every article whose tag links differ from the tags
+// recorded with it.
+func runReconcileTags(ctx context.Context, cfg *config.Config, logger *slog.Logger) error {
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	reconciler, err := app.NewTagReconciler(cfg, db, logger)
+	if err != nil {
+		return err
+	}
+
+	// The reconciler logs how many articles it re-linked.
+	if _, err := reconciler.Reconcile(ctx); err != nil {
+		return fmt.Errorf("reconcile tags: %w", err)
+	}
+	return nil
+}
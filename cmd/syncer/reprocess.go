@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"syscall"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// rawTransformer maps a stored raw payload to an article.
+type rawTransformer interface {
+	TransformRaw(raw []byte) (domain.Article, error)
+}
+
+// runReprocess re-runs the source mapping over stored raw payloads and
+// overwrites the stored articles, without calling the upstream API.
+func runReprocess(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("reprocess", flag.ContinueOnError)
+	sourceID := fs.String("source", "", "source whose raw payloads are reprocessed (required)")
+	republish := fs.Bool("republish", false, "publish an update message for every reprocessed article")
+	batchSize := fs.Int("batch-size", 100, "payloads loaded per query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *sourceID == "" {
+		return errors.New("--source is required")
+	}
+
+	ecbSource, err := app.NewECBSource(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("create ecb source: %w", err)
+	}
+	transformers := map[string]rawTransformer{ecbSource.ID(): ecbSource}
+	transformer, ok := transformers[*sourceID]
+	if !ok {
+		return fmt.Errorf("unknown source %q", *sourceID)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	var pub service.Publisher
+	if *republish {
+		pub, err = app.NewPublisher(cfg, logger, false)
+		if err != nil {
+			return fmt.Errorf("create publisher: %w", err)
+		}
+		defer pub.Close()
+	}
+
+	articles, err := app.NewArticleStore(cfg, db)
+	if err != nil {
+		return err
+	}
+	raws := postgres.NewRawPayloadStore(db)
+	tags, err := app.NewTagStore(cfg, db)
+	if err != nil {
+		return err
+	}
+	txManager := postgres.NewTransactionManager(db)
+	publishFilter := service.SuppressTags(cfg.Publisher.SuppressTags)
+	sequences := app.NewSequenceStore(cfg, db)
+
+	var afterID int64
+	reprocessed, failed := 0, 0
+
+	for {
+		batch, err := raws.List(ctx, *sourceID, afterID, *batchSize)
+		if err != nil {
+			return fmt.Errorf("list raw payloads: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, raw := range batch {
+			article, err := transformer.TransformRaw(raw.Payload)
+			if err != nil {
+				logger.Warn("failed to transform raw payload",
+					"external_id", raw.ExternalID,
+					"error", err,
+				)
+				failed++
+				continue
+			}
+
+			if err := replaceArticle(ctx, txManager, articles, tags, &article); err != nil {
+				return fmt.Errorf("replace article %d: %w", article.ExternalID, err)
+			}
+
+			if pub != nil && (publishFilter == nil || publishFilter(&article)) {
+				if err := service.PublishNumbered(ctx, txManager, sequences, pub, &article, false); err != nil {
+					return fmt.Errorf("publish article %d: %w", article.ExternalID, err)
+				}
+			}
+			reprocessed++
+		}
+
+		logger.Info("reprocessed batch", "reprocessed", reprocessed, "failed", failed)
+		afterID = batch[len(batch)-1].ID
+	}
+
+	logger.Info("reprocess completed", "reprocessed", reprocessed, "failed", failed)
+	return nil
+}
+
+func replaceArticle(
+	ctx context.Context,
+	txManager *postgres.TransactionManager,
+	articles *postgres.ArticleStore,
+	tags *postgres.TagStore,
+	article *domain.Article,
+) error {
+	ctx = domain.WithSourceID(ctx, article.SourceID)
+	return txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		articleID, err := articles.Replace(txCtx, article)
+		if err != nil {
+			return fmt.Errorf("replace article: %w", err)
+		}
+
+		if err := tags.UpsertBatch(txCtx, article.Tags); err != nil {
+			return fmt.Errorf("upsert tags: %w", err)
+		}
+
+		if err := tags.LinkToArticle(txCtx, articleID, article.TagIDs()); err != nil {
+			return fmt.Errorf("link tags: %w", err)
+		}
+
+		return nil
+	})
+}
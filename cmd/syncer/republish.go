@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// runRepublish replays stored articles to the broker without re-fetching them upstream.
+func runRepublish(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("republish", flag.ContinueOnError)
+	sourceID := fs.String("source", "", "only republish articles of this source")
+	from := fs.String("from", "", "only republish articles published at or after this date (RFC3339 or YYYY-MM-DD)")
+	to := fs.String("to", "", "only republish articles published before this date (RFC3339 or YYYY-MM-DD)")
+	action := fs.String("action", "create", "message action: create or update")
+	rate := fs.Float64("rate", 50, "max messages per second, 0 for unlimited")
+	batchSize := fs.Int("batch-size", 100, "articles loaded per query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *action != "create" && *action != "update" {
+		return fmt.Errorf("invalid action %q", *action)
+	}
+	isNew := *action == "create"
+
+	filter := domain.ArticleFilter{SourceID: *sourceID, Limit: *batchSize}
+	var err error
+	if filter.From, err = parseDate(*from); err != nil {
+		return fmt.Errorf("parse --from: %w", err)
+	}
+	if filter.To, err = parseDate(*to); err != nil {
+		return fmt.Errorf("parse --to: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	pub, err := app.NewPublisher(cfg, logger, false)
+	if err != nil {
+		return fmt.Errorf("create publisher: %w", err)
+	}
+	defer pub.Close()
+
+	var throttle <-chan time.Time
+	if *rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	articles := postgres.NewArticleStore(db)
+	publishFilter := service.SuppressTags(cfg.Publisher.SuppressTags)
+	sequences := app.NewSequenceStore(cfg, db)
+	txManager := postgres.NewTransactionManager(db)
+	published, suppressed := 0, 0
+
+	for {
+		batch, err := articles.List(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("list articles: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			if publishFilter != nil && !publishFilter(&batch[i]) {
+				suppressed++
+				continue
+			}
+
+			if throttle != nil {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-throttle:
+				}
+			}
+
+			if err := service.PublishNumbered(ctx, txManager, sequences, pub, &batch[i], isNew); err != nil {
+				return fmt.Errorf("publish article %d: %w", batch[i].ID, err)
+			}
+			published++
+		}
+
+		logger.Info("republished batch", "published", published)
+		filter.After = batch[len(batch)-1].Cursor()
+	}
+
+	logger.Info("republish completed", "published", published, "suppressed", suppressed)
+	return nil
+}
+
+// parseDate accepts RFC3339 timestamps and YYYY-MM-DD dates. Empty input yields the zero time.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// runReset clears the sync state of a single source.
+func runReset(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("reset", flag.ContinueOnError)
+	sourceID := fs.String("source", "", "source to reset")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *sourceID == "" {
+		return errors.New("--source is required")
+	}
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := postgres.NewSyncStateStore(db).Reset(ctx, *sourceID); err != nil {
+		return fmt.Errorf("reset sync state: %w", err)
+	}
+
+	logger.Info("sync state reset", "source", *sourceID)
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// runSetStatus changes the status of a stored article, e.g. to archive it.
+func runSetStatus(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("set-status", flag.ContinueOnError)
+	id := fs.Int64("id", 0, "article id")
+	status := fs.String("status", "", "new status: draft, published or archived")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return errors.New("--id is required")
+	}
+	if !domain.ValidStatus(*status) {
+		return fmt.Errorf("--status must be %s, %s or %s", domain.StatusDraft, domain.StatusPublished, domain.StatusArchived)
+	}
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	if err := postgres.NewArticleStore(db).SetStatus(ctx, *id, *status); err != nil {
+		return fmt.Errorf("set status of article %d: %w", *id, err)
+	}
+
+	logger.Info("article status set", "id", *id, "status", *status)
+	return nil
+}
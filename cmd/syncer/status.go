@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"text/tabwriter"
+	"time"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// sourceStatus is the sync state of a source as -output json prints it.
+type sourceStatus struct {
+	SourceID      string     `json:"source_id"`
+	LastSyncedAt  *time.Time `json:"last_synced_at"` // null if never synced
+	TotalSynced   int64      `json:"total_synced"`
+	LastArticleID int64      `json:"last_article_id"`
+	// LastPublishedAt is when the newest synced article was published, null
+	// if none was.
+	LastPublishedAt *time.Time `json:"last_published_at"`
+}
+
+// loadStatus returns the sync state of every tracked source.
+func loadStatus(ctx context.Context, cfg *config.Config, logger *slog.Logger) ([]sourceStatus, error) {
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	states, err := postgres.NewSyncStateStore(db).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list sync state: %w", err)
+	}
+	return toSourceStatus(states), nil
+}
+
+func toSourceStatus(states []domain.SyncState) []sourceStatus {
+	statuses := make([]sourceStatus, len(states))
+	for i, st := range states {
+		statuses[i] = sourceStatus{
+			SourceID:        st.SourceID,
+			TotalSynced:     st.TotalSynced,
+			LastArticleID:   st.LastArticleID,
+			LastPublishedAt: st.LastPublishedAt,
+		}
+		if !st.LastSyncedAt.IsZero() {
+			lastSynced := st.LastSyncedAt
+			statuses[i].LastSyncedAt = &lastSynced
+		}
+	}
+	return statuses
+}
+
+// formatTime formats t for the status table, "never" if it is nil.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// printStatus prints the sync state of every tracked source as a table.
+func printStatus(w io.Writer, statuses []sourceStatus) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SOURCE\tLAST SYNCED AT\tTOTAL SYNCED\tLAST ARTICLE ID\tLAST PUBLISHED AT")
+	for _, st := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n",
+			st.SourceID, formatTime(st.LastSyncedAt), st.TotalSynced, st.LastArticleID, formatTime(st.LastPublishedAt))
+	}
+	return tw.Flush()
+}
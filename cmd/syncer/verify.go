@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"news_fetcher/app"
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/source/ecb"
+	"news_fetcher/internal/storage/postgres"
+)
+
+// totalCounter reports how many records a source has upstream.
+type totalCounter interface {
+	TotalEntries(ctx context.Context) (int, error)
+}
+
+// storedCounter reports how many articles of a source are stored.
+type storedCounter interface {
+	CountBySource(ctx context.Context, sourceID string) (int64, error)
+}
+
+// verifyResult compares a source's upstream total with the stored count.
+// Delta is upstream minus stored: positive if articles are missing.
+type verifyResult struct {
+	Source   string `json:"source"`
+	Upstream int64  `json:"upstream"`
+	Stored   int64  `json:"stored"`
+	Delta    int64  `json:"delta"`
+}
+
+// drift returns how far the stored count is off, in either direction.
+func (r verifyResult) drift() int64 {
+	if r.Delta < 0 {
+		return -r.Delta
+	}
+	return r.Delta
+}
+
+// runVerify cross-checks the number of stored articles of a source against the
+// total the API reports. It returns the counts, and an error as well if they
+// drift further apart than --max-drift.
+func runVerify(ctx context.Context, cfg *config.Config, logger *slog.Logger, args []string) (*verifyResult, error) {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	sourceID := fs.String("source", ecb.SourceID, "source to verify")
+	maxDrift := fs.Int64("max-drift", 0, "largest difference between the upstream and stored counts that still passes")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *sourceID != ecb.SourceID {
+		return nil, fmt.Errorf("unknown source %q", *sourceID)
+	}
+	if *maxDrift < 0 {
+		return nil, fmt.Errorf("--max-drift must not be negative")
+	}
+
+	source, err := app.NewECBSource(cfg, logger)
+	if err != nil {
+		return nil, fmt.Errorf("create source: %w", err)
+	}
+
+	db, err := app.ConnectDB(cfg.Database, logger)
+	if err != nil {
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	defer db.Close()
+
+	return verifyCounts(ctx, *sourceID, source, postgres.NewArticleStore(db), *maxDrift)
+}
+
+// verifyCounts fetches both counts of sourceID and compares them.
+func verifyCounts(ctx context.Context, sourceID string, upstream totalCounter, stored storedCounter, maxDrift int64) (*verifyResult, error) {
+	total, err := upstream.TotalEntries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	count, err := stored.CountBySource(ctx, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("count stored articles: %w", err)
+	}
+
+	result := &verifyResult{
+		Source:   sourceID,
+		Upstream: int64(total),
+		Stored:   count,
+		Delta:    int64(total) - count,
+	}
+	if result.drift() > maxDrift {
+		return result, fmt.Errorf("source %s: %d articles upstream, %d stored: drift %d exceeds %d",
+			sourceID, result.Upstream, result.Stored, result.drift(), maxDrift)
+	}
+	return result, nil
+}
+
+// printVerify writes the counts of a verify run as a line of text.
+func printVerify(w io.Writer, result *verifyResult) error {
+	_, err := fmt.Fprintf(w, "source %s: upstream %d, stored %d, delta %+d\n",
+		result.Source, result.Upstream, result.Stored, result.Delta)
+	return err
+}
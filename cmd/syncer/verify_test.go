@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeTotal struct {
+	total int
+	err   error
+}
+
+func (f fakeTotal) TotalEntries(context.Context) (int, error) {
+	return f.total, f.err
+}
+
+type fakeStored map[string]int64
+
+func (f fakeStored) CountBySource(_ context.Context, sourceID string) (int64, error) {
+	return f[sourceID], nil
+}
+
+type VerifyTestSuite struct {
+	suite.Suite
+}
+
+func TestVerifyTestSuite(t *testing.T) {
+	suite.Run(t, new(VerifyTestSuite))
+}
+
+func (s *VerifyTestSuite) TestVerifyCounts_InSync() {
+	result, err := verifyCounts(context.Background(), "ecb", fakeTotal{total: 42}, fakeStored{"ecb": 42, "other": 7}, 0)
+
+	s.Require().NoError(err)
+	s.Equal(&verifyResult{Source: "ecb", Upstream: 42, Stored: 42, Delta: 0}, result)
+}
+
+func (s *VerifyTestSuite) TestVerifyCounts_DriftWithinThreshold() {
+	result, err := verifyCounts(context.Background(), "ecb", fakeTotal{total: 42}, fakeStored{"ecb": 40}, 2)
+
+	s.Require().NoError(err)
+	s.Equal(int64(2), result.Delta)
+}
+
+func (s *VerifyTestSuite) TestVerifyCounts_MissingArticles() {
+	result, err := verifyCounts(context.Background(), "ecb", fakeTotal{total: 42}, fakeStored{"ecb": 39}, 2)
+
+	s.ErrorContains(err, "drift 3 exceeds 2")
+	s.Require().NotNil(result)
+	s.Equal(int64(3), result.Delta)
+}
+
+func (s *VerifyTestSuite) TestVerifyCounts_ExtraArticles() {
+	// More stored than upstream, e.g. articles the API has since dropped.
+	result, err := verifyCounts(context.Background(), "ecb", fakeTotal{total: 40}, fakeStored{"ecb": 45}, 2)
+
+	s.ErrorContains(err, "drift 5 exceeds 2")
+	s.Require().NotNil(result)
+	s.Equal(int64(-5), result.Delta)
+}
+
+func (s *VerifyTestSuite) TestVerifyCounts_UpstreamError() {
+	result, err := verifyCounts(context.Background(), "ecb", fakeTotal{err: errors.New("unexpected status: 502")}, fakeStored{}, 0)
+
+	s.ErrorContains(err, "unexpected status: 502")
+	s.Nil(result)
+}
+
+func (s *VerifyTestSuite) TestPrintVerify() {
+	var buf bytes.Buffer
+	s.Require().NoError(printVerify(&buf, &verifyResult{Source: "ecb", Upstream: 40, Stored: 45, Delta: -5}))
+
+	s.Equal("source ecb: upstream 40, stored 45, delta -5\n", buf.String())
+}
@@ -0,0 +1,14 @@
+package admin
+
+import "net/http"
+
+// HandleHealth registers GET /sources/health, which reports each source's last
+// success and failure, consecutive failures and average sync duration.
+func (s *Server) HandleHealth(health HealthReporter) {
+	s.health = health
+	s.mux.HandleFunc("GET /sources/health", s.handleHealth)
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.health.All())
+}
@@ -0,0 +1,32 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"news_fetcher/internal/admin/mocks"
+	"news_fetcher/internal/domain"
+)
+
+func (s *ServerTestSuite) TestHealth() {
+	health := mocks.NewMockHealthReporter(s.ctrl)
+	s.server.HandleHealth(health)
+
+	lastSuccess := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	health.EXPECT().All().Return([]domain.SourceHealth{
+		{SourceID: "ecb", LastSuccessAt: &lastSuccess, ConsecutiveFailures: 2, LastError: "timeout", Runs: 5, AverageDuration: time.Second},
+	})
+
+	rec := s.do(http.MethodGet, "/sources/health")
+
+	s.Equal(http.StatusOK, rec.Code)
+	var resp []domain.SourceHealth
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Require().Len(resp, 1)
+	s.Equal("ecb", resp[0].SourceID)
+	s.Equal(2, resp[0].ConsecutiveFailures)
+	s.Equal("timeout", resp[0].LastError)
+	s.True(lastSuccess.Equal(*resp[0].LastSuccessAt))
+	s.Nil(resp[0].LastFailureAt)
+}
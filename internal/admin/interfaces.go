@@ -0,0 +1,38 @@
+package admin
+
+//go:generate mockgen -source=interfaces.go -destination=mocks/mocks.go -package=mocks
+
+import (
+	"context"
+
+	"news_fetcher/internal/domain"
+)
+
+// Syncer runs a sync pass on demand.
+type Syncer interface {
+	Sync(ctx context.Context) (*domain.SyncStats, error)
+}
+
+// Pauser pauses and resumes syncing a source.
+type Pauser interface {
+	Pause(ctx context.Context) error
+	Resume(ctx context.Context) error
+	Paused() bool
+}
+
+// HealthReporter reports the health of every source.
+type HealthReporter interface {
+	All() []domain.SourceHealth
+}
+
+// Checker reports whether a dependency is ready to serve traffic.
+type Checker interface {
+	Ready(ctx context.Context) error
+}
+
+// CheckFunc adapts a function to the Checker interface.
+type CheckFunc func(ctx context.Context) error
+
+func (f CheckFunc) Ready(ctx context.Context) error {
+	return f(ctx)
+}
@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"net/http"
+	"sort"
+)
+
+type pauseResponse struct {
+	Source string `json:"source"`
+	Paused bool   `json:"paused"`
+}
+
+// HandlePause registers POST /sources/{source}/pause and
+// POST /sources/{source}/resume, which stop and restart the scheduled and
+// on-demand syncs of a source, and GET /sources, which lists whether each
+// source is paused.
+func (s *Server) HandlePause(pausers map[string]Pauser) {
+	s.pausers = pausers
+	s.mux.HandleFunc("GET /sources", s.handleListSources)
+	s.mux.HandleFunc("POST /sources/{source}/pause", func(w http.ResponseWriter, r *http.Request) {
+		s.handleSetPaused(w, r, true)
+	})
+	s.mux.HandleFunc("POST /sources/{source}/resume", func(w http.ResponseWriter, r *http.Request) {
+		s.handleSetPaused(w, r, false)
+	})
+}
+
+func (s *Server) handleListSources(w http.ResponseWriter, r *http.Request) {
+	ids := make([]string, 0, len(s.pausers))
+	for id := range s.pausers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resp := make([]pauseResponse, 0, len(ids))
+	for _, id := range ids {
+		resp = append(resp, pauseResponse{Source: id, Paused: s.pausers[id].Paused()})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleSetPaused(w http.ResponseWriter, r *http.Request, pause bool) {
+	sourceID := r.PathValue("source")
+	pauser, ok := s.pausers[sourceID]
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown source")
+		return
+	}
+
+	// The pause applies in memory even if persisting it fails, so report the
+	// error but keep the new state.
+	var err error
+	if pause {
+		s.logger.Warn("source paused via admin api", "source", sourceID)
+		err = pauser.Pause(r.Context())
+	} else {
+		s.logger.Info("source resumed via admin api", "source", sourceID)
+		err = pauser.Resume(r.Context())
+	}
+	if err != nil {
+		s.logger.Error("failed to persist pause state", "source", sourceID, "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, pauseResponse{Source: sourceID, Paused: pauser.Paused()})
+}
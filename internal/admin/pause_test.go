@@ -0,0 +1,97 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/admin/mocks"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+)
+
+func (s *ServerTestSuite) TestPause() {
+	pauser := mocks.NewMockPauser(s.ctrl)
+	s.server.HandlePause(map[string]Pauser{"ecb": pauser})
+
+	pauser.EXPECT().Pause(gomock.Any()).Return(nil)
+	pauser.EXPECT().Paused().Return(true)
+
+	rec := s.do(http.MethodPost, "/sources/ecb/pause")
+
+	s.Equal(http.StatusOK, rec.Code)
+	var resp pauseResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal(pauseResponse{Source: "ecb", Paused: true}, resp)
+}
+
+func (s *ServerTestSuite) TestResume() {
+	pauser := mocks.NewMockPauser(s.ctrl)
+	s.server.HandlePause(map[string]Pauser{"ecb": pauser})
+
+	pauser.EXPECT().Resume(gomock.Any()).Return(nil)
+	pauser.EXPECT().Paused().Return(false)
+
+	rec := s.do(http.MethodPost, "/sources/ecb/resume")
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), `"paused":false`)
+}
+
+func (s *ServerTestSuite) TestPause_PersistError() {
+	pauser := mocks.NewMockPauser(s.ctrl)
+	s.server.HandlePause(map[string]Pauser{"ecb": pauser})
+
+	pauser.EXPECT().Pause(gomock.Any()).Return(errors.New("db down"))
+
+	rec := s.do(http.MethodPost, "/sources/ecb/pause")
+
+	s.Equal(http.StatusInternalServerError, rec.Code)
+	s.Contains(rec.Body.String(), "db down")
+}
+
+func (s *ServerTestSuite) TestPause_UnknownSource() {
+	s.server.HandlePause(map[string]Pauser{})
+
+	rec := s.do(http.MethodPost, "/sources/unknown/pause")
+
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *ServerTestSuite) TestListSources() {
+	ecb := mocks.NewMockPauser(s.ctrl)
+	other := mocks.NewMockPauser(s.ctrl)
+	s.server.HandlePause(map[string]Pauser{"ecb": ecb, "other": other})
+
+	ecb.EXPECT().Paused().Return(true)
+	other.EXPECT().Paused().Return(false)
+
+	rec := s.do(http.MethodGet, "/sources")
+
+	s.Equal(http.StatusOK, rec.Code)
+	var resp []pauseResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal([]pauseResponse{{Source: "ecb", Paused: true}, {Source: "other", Paused: false}}, resp)
+}
+
+func (s *ServerTestSuite) TestSync_AllSourcesSkipsPaused() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(nil, service.ErrSourcePaused)
+	s.other.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "other"}, nil)
+
+	rec := s.do(http.MethodPost, "/sync")
+
+	s.Equal(http.StatusOK, rec.Code)
+	var results []sourceSyncResult
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &results))
+	s.Equal([]sourceSyncResult{{Source: "other", Stats: &domain.SyncStats{SourceID: "other"}}}, results)
+}
+
+func (s *ServerTestSuite) TestSync_PausedSource() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(nil, service.ErrSourcePaused)
+
+	rec := s.do(http.MethodPost, "/sync/ecb")
+
+	s.Equal(http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,49 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// readyTimeout bounds the whole readiness probe.
+const readyTimeout = 10 * time.Second
+
+type readyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// HandleReady registers GET /readyz. It responds 200 when every check passes
+// and 503 otherwise, reporting each check's result by name.
+func (s *Server) HandleReady(checks map[string]Checker) {
+	s.checks = checks
+	s.mux.HandleFunc("GET /readyz", s.handleReady)
+}
+
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+	defer cancel()
+
+	names := make([]string, 0, len(s.checks))
+	for name := range s.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	resp := readyResponse{Status: "ok", Checks: make(map[string]string, len(names))}
+	code := http.StatusOK
+	for _, name := range names {
+		if err := s.checks[name].Ready(ctx); err != nil {
+			s.logger.Warn("readiness check failed", "check", name, "error", err)
+			resp.Checks[name] = err.Error()
+			resp.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+			continue
+		}
+		resp.Checks[name] = "ok"
+	}
+
+	writeJSON(w, code, resp)
+}
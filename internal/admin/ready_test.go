@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/mock/gomock"
+)
+
+func (s *ServerTestSuite) TestReady_AllChecksPass() {
+	s.db.EXPECT().Ready(gomock.Any()).Return(nil)
+	s.broker.EXPECT().Ready(gomock.Any()).Return(nil)
+
+	rec := s.do(http.MethodGet, "/readyz")
+
+	s.Equal(http.StatusOK, rec.Code)
+
+	var resp readyResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("ok", resp.Status)
+	s.Equal(map[string]string{"database": "ok", "publisher": "ok"}, resp.Checks)
+}
+
+func (s *ServerTestSuite) TestReady_BrokerDown() {
+	s.db.EXPECT().Ready(gomock.Any()).Return(nil)
+	s.broker.EXPECT().Ready(gomock.Any()).Return(errors.New("connect to rabbitmq: connection refused"))
+
+	rec := s.do(http.MethodGet, "/readyz")
+
+	s.Equal(http.StatusServiceUnavailable, rec.Code)
+
+	var resp readyResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &resp))
+	s.Equal("unavailable", resp.Status)
+	s.Equal("ok", resp.Checks["database"])
+	s.Equal("connect to rabbitmq: connection refused", resp.Checks["publisher"])
+}
+
+func (s *ServerTestSuite) TestCheckFunc() {
+	called := false
+	var c Checker = CheckFunc(func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	s.NoError(c.Ready(context.Background()))
+	s.True(called)
+}
@@ -0,0 +1,94 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Server is the HTTP server for operational endpoints.
+type Server struct {
+	srv    *http.Server
+	mux    *http.ServeMux
+	logger *slog.Logger
+
+	syncers     map[string]Syncer
+	syncTimeout time.Duration
+
+	// Admin syncs run detached from their request, under syncCtx, so Shutdown
+	// can cancel them and wait for them through syncs.
+	syncMu      sync.Mutex
+	syncCtx     context.Context
+	cancelSyncs context.CancelFunc
+	syncs       sync.WaitGroup
+
+	pausers map[string]Pauser
+	health  HealthReporter
+
+	checks map[string]Checker
+}
+
+func NewServer(addr string, logger *slog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", promhttp.Handler())
+	syncCtx, cancelSyncs := context.WithCancel(context.Background())
+
+	return &Server{
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		mux:         mux,
+		logger:      logger.With("component", "admin"),
+		syncCtx:     syncCtx,
+		cancelSyncs: cancelSyncs,
+	}
+}
+
+// Handler returns the server's request handler.
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// Start serves requests in the background until Shutdown is called.
+func (s *Server) Start() {
+	go func() {
+		s.logger.Info("admin server listening", "addr", s.srv.Addr)
+		if err := s.srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("admin server failed", "error", err)
+		}
+	}()
+}
+
+// Shutdown stops accepting requests and waits for the running ones until ctx
+// is done. Admin syncs still running then are cancelled, and Shutdown returns
+// once they have stopped, so the stores they use can be closed after it.
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.srv.Shutdown(ctx)
+
+	s.syncMu.Lock()
+	s.cancelSyncs()
+	s.syncMu.Unlock()
+	s.syncs.Wait()
+
+	return err
+}
+
+// startSync registers an admin sync with Shutdown and returns its context. It
+// returns false once Shutdown has started.
+func (s *Server) startSync() (context.Context, bool) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	if s.syncCtx.Err() != nil {
+		return nil, false
+	}
+	s.syncs.Add(1)
+	return s.syncCtx, true
+}
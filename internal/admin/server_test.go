@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/admin/mocks"
+	"news_fetcher/internal/metrics"
+)
+
+type ServerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	ecb   *mocks.MockSyncer
+	other *mocks.MockSyncer
+
+	db     *mocks.MockChecker
+	broker *mocks.MockChecker
+
+	server *Server
+}
+
+func (s *ServerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.ecb = mocks.NewMockSyncer(s.ctrl)
+	s.other = mocks.NewMockSyncer(s.ctrl)
+	s.db = mocks.NewMockChecker(s.ctrl)
+	s.broker = mocks.NewMockChecker(s.ctrl)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.server = NewServer(":0", logger)
+	s.server.HandleSync(map[string]Syncer{"ecb": s.ecb, "other": s.other}, time.Minute)
+	s.server.HandleReady(map[string]Checker{"database": s.db, "publisher": s.broker})
+}
+
+func (s *ServerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
+
+func (s *ServerTestSuite) do(method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.server.Handler().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func (s *ServerTestSuite) TestMetrics() {
+	metrics.LastSuccessTimestamp.WithLabelValues("ecb").Set(1700000000)
+
+	rec := s.do(http.MethodGet, "/metrics")
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Contains(rec.Body.String(), `news_fetcher_last_success_timestamp_seconds{source="ecb"} 1.7e+09`)
+}
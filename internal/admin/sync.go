@@ -0,0 +1,116 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+)
+
+// HandleSync registers POST /sync, which syncs every source that isn't paused,
+// and POST /sync/{source}, which syncs one. Each sync runs with the given timeout,
+// detached from the request so a disconnecting client doesn't abort it; only
+// Shutdown cancels it.
+func (s *Server) HandleSync(syncers map[string]Syncer, timeout time.Duration) {
+	s.syncers = syncers
+	s.syncTimeout = timeout
+	s.mux.HandleFunc("POST /sync", s.handleSync)
+	s.mux.HandleFunc("POST /sync/{source}", s.handleSync)
+}
+
+func (s *Server) handleSync(w http.ResponseWriter, r *http.Request) {
+	sourceID := r.PathValue("source")
+
+	if _, ok := s.syncers[sourceID]; sourceID != "" && !ok {
+		writeError(w, http.StatusNotFound, "unknown source")
+		return
+	}
+
+	syncCtx, ok := s.startSync()
+	if !ok {
+		writeError(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+	defer s.syncs.Done()
+
+	ctx, cancel := context.WithTimeout(syncCtx, s.syncTimeout)
+	defer cancel()
+
+	if sourceID != "" {
+		s.syncOne(ctx, w, sourceID)
+		return
+	}
+
+	ids := make([]string, 0, len(s.syncers))
+	for id := range s.syncers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	// Every source is synced even if one fails, and the response reports each
+	// of them, with the status of the worst outcome.
+	status := http.StatusOK
+	results := make([]sourceSyncResult, 0, len(ids))
+	for _, id := range ids {
+		s.logger.Info("sync triggered via admin api", "source", id)
+
+		stats, err := s.syncers[id].Sync(ctx)
+		if errors.Is(err, service.ErrSourcePaused) {
+			continue
+		}
+		result := sourceSyncResult{Source: id, Stats: stats}
+		switch {
+		case errors.Is(err, service.ErrSyncInProgress):
+			result.Error = err.Error()
+			if status == http.StatusOK {
+				status = http.StatusConflict
+			}
+		case err != nil:
+			s.logger.Error("admin sync failed", "source", id, "error", err)
+			result.Error = err.Error()
+			status = http.StatusInternalServerError
+		}
+		results = append(results, result)
+	}
+	writeJSON(w, status, results)
+}
+
+// sourceSyncResult is one source's outcome of POST /sync. Stats may be set
+// alongside Error when the sync failed partway.
+type sourceSyncResult struct {
+	Source string            `json:"source"`
+	Stats  *domain.SyncStats `json:"stats,omitempty"`
+	Error  string            `json:"error,omitempty"`
+}
+
+// syncOne handles POST /sync/{source}.
+func (s *Server) syncOne(ctx context.Context, w http.ResponseWriter, id string) {
+	s.logger.Info("sync triggered via admin api", "source", id)
+
+	stats, err := s.syncers[id].Sync(ctx)
+	if errors.Is(err, service.ErrSyncInProgress) || errors.Is(err, service.ErrSourcePaused) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		s.logger.Error("admin sync failed", "source", id, "error", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
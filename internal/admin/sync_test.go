@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+)
+
+func (s *ServerTestSuite) TestSync_Source() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "ecb", Fetched: 3, New: 2}, nil)
+
+	rec := s.do(http.MethodPost, "/sync/ecb")
+
+	s.Equal(http.StatusOK, rec.Code)
+	s.Equal("application/json", rec.Header().Get("Content-Type"))
+
+	var stats domain.SyncStats
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &stats))
+	s.Equal("ecb", stats.SourceID)
+	s.Equal(3, stats.Fetched)
+	s.Equal(2, stats.New)
+}
+
+func (s *ServerTestSuite) TestSync_AllSources() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "ecb"}, nil)
+	s.other.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "other"}, nil)
+
+	rec := s.do(http.MethodPost, "/sync")
+
+	s.Equal(http.StatusOK, rec.Code)
+
+	var results []sourceSyncResult
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &results))
+	s.Equal([]sourceSyncResult{
+		{Source: "ecb", Stats: &domain.SyncStats{SourceID: "ecb"}},
+		{Source: "other", Stats: &domain.SyncStats{SourceID: "other"}},
+	}, results)
+}
+
+func (s *ServerTestSuite) TestSync_AllSourcesReportsEveryFailure() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(nil, service.ErrSyncInProgress)
+	s.other.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "other", Errors: 1}, errors.New("api error"))
+
+	rec := s.do(http.MethodPost, "/sync")
+
+	s.Equal(http.StatusInternalServerError, rec.Code)
+	var results []sourceSyncResult
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &results))
+	s.Equal([]sourceSyncResult{
+		{Source: "ecb", Error: service.ErrSyncInProgress.Error()},
+		{Source: "other", Stats: &domain.SyncStats{SourceID: "other", Errors: 1}, Error: "api error"},
+	}, results)
+}
+
+func (s *ServerTestSuite) TestSync_AllSourcesInProgress() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(&domain.SyncStats{SourceID: "ecb"}, nil)
+	s.other.EXPECT().Sync(gomock.Any()).Return(nil, service.ErrSyncInProgress)
+
+	rec := s.do(http.MethodPost, "/sync")
+
+	s.Equal(http.StatusConflict, rec.Code)
+	var results []sourceSyncResult
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &results))
+	s.Require().Len(results, 2)
+	s.Empty(results[0].Error)
+	s.Equal(service.ErrSyncInProgress.Error(), results[1].Error)
+}
+
+func (s *ServerTestSuite) TestSync_InProgress() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(nil, service.ErrSyncInProgress)
+
+	rec := s.do(http.MethodPost, "/sync/ecb")
+
+	s.Equal(http.StatusConflict, rec.Code)
+	s.Contains(rec.Body.String(), service.ErrSyncInProgress.Error())
+}
+
+func (s *ServerTestSuite) TestSync_Failure() {
+	s.ecb.EXPECT().Sync(gomock.Any()).Return(nil, errors.New("api error"))
+
+	rec := s.do(http.MethodPost, "/sync/ecb")
+
+	s.Equal(http.StatusInternalServerError, rec.Code)
+	s.Contains(rec.Body.String(), "api error")
+}
+
+func (s *ServerTestSuite) TestSync_UnknownSource() {
+	rec := s.do(http.MethodPost, "/sync/unknown")
+
+	s.Equal(http.StatusNotFound, rec.Code)
+}
+
+func (s *ServerTestSuite) TestSync_MethodNotAllowed() {
+	rec := s.do(http.MethodGet, "/sync/ecb")
+
+	s.Equal(http.StatusMethodNotAllowed, rec.Code)
+}
+
+func (s *ServerTestSuite) TestSync_ShutdownCancelsAndWaits() {
+	started := make(chan struct{})
+	var stopped bool
+	s.ecb.EXPECT().Sync(gomock.Any()).DoAndReturn(func(ctx context.Context) (*domain.SyncStats, error) {
+		close(started)
+		<-ctx.Done()
+		stopped = true
+		return nil, ctx.Err()
+	})
+
+	go s.do(http.MethodPost, "/sync/ecb")
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.NoError(s.server.Shutdown(ctx))
+	s.True(stopped, "Shutdown returned before the sync stopped")
+
+	rec := s.do(http.MethodPost, "/sync/ecb")
+	s.Equal(http.StatusServiceUnavailable, rec.Code)
+}
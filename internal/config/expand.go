@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+	"strings"
+)
+
+// expandEnv replaces $VAR and ${VAR} with the value of the environment
+// variable, like os.ExpandEnv, and ${VAR:-default} with default if VAR is
+// unset or empty. The default can't contain "}".
+func expandEnv(s string) string {
+	return os.Expand(s, func(name string) string {
+		name, def, hasDefault := strings.Cut(name, ":-")
+		value := os.Getenv(name)
+		if value == "" && hasDefault {
+			return def
+		}
+		return value
+	})
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redacted replaces secrets in redacted output. It matches what url.URL.Redacted
+// puts in place of a password.
+const redacted = "xxxxx"
+
+// Redacted returns a copy of the config that is safe to log: passwords,
+// secrets and the credentials in connection URLs are masked. Unset secrets
+// stay empty, so it still shows which ones are configured.
+func (c *Config) Redacted() *Config {
+	r := *c
+	r.Database.Password = redactSecret(r.Database.Password)
+	r.RabbitMQ.URL = RedactURL(r.RabbitMQ.URL)
+	r.RabbitMQ.SigningSecret = redactSecret(r.RabbitMQ.SigningSecret)
+	r.Webhook.URL = RedactURL(r.Webhook.URL)
+	r.Webhook.Secret = redactSecret(r.Webhook.Secret)
+	return &r
+}
+
+func redactSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// RedactURL masks the password in a URL such as an AMQP URL. A URL that can't
+// be parsed is masked entirely, since it can't be told where the password is.
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	return u.Redacted()
+}
+
+// dsnPassword matches the password of a key=value DSN, quoted or not.
+var dsnPassword = regexp.MustCompile(`password=('(?:[^'\\]|\\.)*'|\S*)`)
+
+// RedactDSN masks the password in a Postgres DSN, either key=value or URL.
+func RedactDSN(dsn string) string {
+	if strings.Contains(dsn, "://") {
+		return RedactURL(dsn)
+	}
+	return dsnPassword.ReplaceAllString(dsn, "password="+redacted)
+}
@@ -1,29 +1,259 @@
 package domain
 
-import "time"
+import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
 
+// Article is published as JSON to downstream consumers. Keys are snake_case to
+// match the database columns; optional pointer fields are always present and
+// null when unset.
 type Article struct {
+	ID           int64       `json:"id,omitempty"`
+	SourceID     string      `json:"source_id"` // identifies the source (e.g., "ecb", "espn")
+	ExternalID   int64       `json:"external_id"`
+	ExternalKey  string      `json:"external_key,omitempty"` // source's own key if it isn't numeric, e.g. an RSS GUID; see SetExternalKey
+	Title        string      `json:"title"`
+	Description  *string     `json:"description"`
+	Summary      *string     `json:"summary"`
+	Body         *string     `json:"body"`
+	Author       *string     `json:"author"`
+	CanonicalURL string      `json:"canonical_url"`
+	ImageURL     *string     `json:"image_url"` // primary image, also the first Media item
+	PublishedAt  time.Time   `json:"published_at"`
+	LastModified time.Time   `json:"last_modified"`
+	Duration     int         `json:"duration"`
+	ReadingTime  int         `json:"reading_time,omitempty"` // minutes, set by the reading-time enricher
+	Category     string      `json:"category"`
+	Language     string      `json:"language,omitempty"` // e.g. "en-GB"; empty if the source doesn't say
+	Tags         []Tag       `json:"tags,omitempty"`
+	Media        []MediaItem `json:"media,omitempty"`
+	Status       string      `json:"status,omitempty"` // StatusDraft, StatusPublished or StatusArchived; empty until stored
+	CreatedAt    time.Time   `json:"created_at,omitzero"`
+	UpdatedAt    time.Time   `json:"updated_at,omitzero"`
+	Raw          []byte      `json:"-"` // upstream payload, set by sources that keep it
+	Sequence     int64       `json:"-"` // per-source publish sequence number, set just before publishing; 0 if not numbered
+}
+
+// ContentHash returns a hash of the article content, ignoring storage fields
+// (ID, status, timestamps) and LastModified, so re-sent but unchanged content
+// can be told apart from a real update.
+func (a *Article) ContentHash() string {
+	content := *a
+	content.ID = 0
+	content.Status = ""
+	content.LastModified = time.Time{}
+	content.CreatedAt = time.Time{}
+	content.UpdatedAt = time.Time{}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// FieldChange is a field whose value differs between two versions of an
+// article. Old and New are the field's JSON values, null if it is absent.
+type FieldChange struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+// Diff returns the fields, by JSON key and in key order, whose value in next
+// differs from a. Like ContentHash, it ignores the storage fields (ID, status,
+// created and updated times); times are compared in UTC and tags regardless
+// of their order.
+func (a *Article) Diff(next *Article) []FieldChange {
+	old, updated := a.diffFields(), next.diffFields()
+
+	names := make([]string, 0, len(old)+len(updated))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range updated {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var changes []FieldChange
+	for _, name := range names {
+		oldValue, newValue := old[name], updated[name]
+		if bytes.Equal(oldValue, newValue) {
+			continue
+		}
+		if oldValue == nil {
+			oldValue = json.RawMessage("null")
+		}
+		if newValue == nil {
+			newValue = json.RawMessage("null")
+		}
+		changes = append(changes, FieldChange{Field: name, Old: oldValue, New: newValue})
+	}
+	return changes
+}
+
+// diffFields returns the JSON fields Diff compares.
+func (a *Article) diffFields() map[string]json.RawMessage {
+	content := *a
+	content.ID = 0
+	content.Status = ""
+	content.CreatedAt = time.Time{}
+	content.UpdatedAt = time.Time{}
+	content.PublishedAt = content.PublishedAt.UTC()
+	content.LastModified = content.LastModified.UTC()
+	content.Tags = slices.SortedFunc(slices.Values(a.Tags), func(x, y Tag) int {
+		return cmp.Or(cmp.Compare(x.ID, y.ID), strings.Compare(x.Label, y.Label))
+	})
+
+	var fields map[string]json.RawMessage
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// Key returns the key that identifies the article within its source: the
+// ExternalKey if the source has one, otherwise the ExternalID.
+func (a *Article) Key() string {
+	if a.ExternalKey != "" {
+		return a.ExternalKey
+	}
+	return strconv.FormatInt(a.ExternalID, 10)
+}
+
+// SetExternalKey identifies an article of a source without numeric IDs by
+// key. ExternalID is derived from it for consumers that expect a number; the
+// derived IDs are negative, so they can't collide with the IDs of sources
+// that have numeric ones. The sync and the stores track articles by key, so
+// two keys deriving the same ID are still told apart.
+func (a *Article) SetExternalKey(key string) {
+	a.ExternalKey = key
+	a.ExternalID = syntheticID(key)
+}
+
+// ExistingArticle is what the sync needs to know about a stored article to
+// decide whether a fetched one is new, updated or unchanged.
+type ExistingArticle struct {
 	ID           int64
-	SourceID     string // identifies the source (e.g., "ecb", "espn")
-	ExternalID   int64
-	Title        string
-	Description  *string
-	Summary      *string
-	Body         *string
-	Author       *string
-	CanonicalURL string
-	ImageURL     *string
-	PublishedAt  time.Time
 	LastModified time.Time
-	Duration     int
-	Tags         []Tag
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ContentHash  string
+	// PublishPending is set when its last update was stored but not yet
+	// published.
+	PublishPending bool
+}
+
+// Article statuses. They are set by hand, through ArticleStore.SetStatus;
+// syncs create articles as published.
+const (
+	StatusDraft     = "draft"
+	StatusPublished = "published"
+	StatusArchived  = "archived"
+)
+
+// ValidStatus reports whether status is one of the article statuses.
+func ValidStatus(status string) bool {
+	switch status {
+	case StatusDraft, StatusPublished, StatusArchived:
+		return true
+	default:
+		return false
+	}
+}
+
+// Article categories. Sources may also produce their own.
+const (
+	CategoryArticle = "article"
+	CategoryVideo   = "video"
+)
+
+// ArticleFilter narrows down article listings. Zero values mean "no constraint".
+type ArticleFilter struct {
+	SourceID string
+	Category string
+	From     time.Time      // published at or after
+	To       time.Time      // published before
+	After    *ArticleCursor // keyset cursor: only articles listed after it
+	Limit    int
+}
+
+// ArticleCursor is the position of an article in listings, which are ordered
+// by (PublishedAt, ExternalID, ID) so that articles published at the same time
+// always come in the same order.
+type ArticleCursor struct {
+	PublishedAt time.Time
+	ExternalID  int64
+	ID          int64
+}
+
+// Cursor returns the position of the article in listings.
+func (a *Article) Cursor() *ArticleCursor {
+	return &ArticleCursor{PublishedAt: a.PublishedAt, ExternalID: a.ExternalID, ID: a.ID}
+}
+
+// MediaItem is one media rendition attached to an article.
+type MediaItem struct {
+	URL    string `json:"url"`
+	Type   string `json:"type"` // e.g. "image", "video"
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
 }
 
 type Tag struct {
-	ID    int64
-	Label string
+	ID    int64  `json:"id"`
+	Label string `json:"label"`
+}
+
+// SyntheticTagID derives a stable ID for a tag the source sent without one
+// from its label. It is always negative, so it can't collide with the
+// positive IDs sources assign.
+func SyntheticTagID(label string) int64 {
+	return syntheticID(label)
+}
+
+// syntheticID hashes s to a negative int64.
+func syntheticID(s string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return -int64(h.Sum64()>>1) - 1
+}
+
+// NormalizeTagLabel returns the form of label that tags are deduplicated on
+// when their labels are normalized: trimmed, lowercased and with runs of
+// whitespace collapsed to a single space, so "Test", " test" and "TEST" are
+// one tag.
+func NormalizeTagLabel(label string) string {
+	return strings.Join(strings.Fields(strings.ToLower(label)), " ")
+}
+
+// ArticleRevision is a prior version of an article, recorded when an update
+// replaced it.
+type ArticleRevision struct {
+	ID        int64   `db:"id"`
+	ArticleID int64   `db:"article_id"`
+	Title     string  `db:"title"`
+	Summary   *string `db:"summary"`
+	Body      *string `db:"body"`
+	// LastModified is the upstream version the revision was.
+	LastModified time.Time `db:"last_modified"`
+	// CreatedAt is when it was replaced.
+	CreatedAt time.Time `db:"created_at"`
 }
 
 type SyncState struct {
@@ -32,4 +262,8 @@ type SyncState struct {
 	LastSyncedAt  time.Time `db:"last_synced_at"`
 	LastArticleID int64     `db:"last_article_id"`
 	TotalSynced   int64     `db:"total_synced"`
-}
\ No newline at end of file
+	// LastPublishedAt is the newest PublishedAt of the articles synced, nil
+	// before any was. Unlike LastSyncedAt, the last time the source was
+	// checked, it tells how fresh its content is.
+	LastPublishedAt *time.Time `db:"last_published_at"`
+}
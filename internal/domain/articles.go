@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Articles is a batch of articles, e.g. the result of one fetch.
+type Articles []Article
+
+// ExternalIDs returns the external IDs in order.
+func (a Articles) ExternalIDs() []int64 {
+	ids := make([]int64, len(a))
+	for i, article := range a {
+		ids[i] = article.ExternalID
+	}
+	return ids
+}
+
+// Keys returns the keys in order; see Article.Key.
+func (a Articles) Keys() []string {
+	keys := make([]string, len(a))
+	for i := range a {
+		keys[i] = a[i].Key()
+	}
+	return keys
+}
+
+// FilterAfter returns the articles published after t.
+func (a Articles) FilterAfter(t time.Time) Articles {
+	var filtered Articles
+	for _, article := range a {
+		if article.PublishedAt.After(t) {
+			filtered = append(filtered, article)
+		}
+	}
+	return filtered
+}
+
+// Dedup keeps one article per (source_id, key), the one with the newest
+// LastModified, in order of first appearance.
+func (a Articles) Dedup() Articles {
+	type key struct {
+		sourceID string
+		key      string
+	}
+
+	index := make(map[key]int, len(a))
+	deduped := make(Articles, 0, len(a))
+	for _, article := range a {
+		k := key{article.SourceID, article.Key()}
+		if i, ok := index[k]; ok {
+			if article.LastModified.After(deduped[i].LastModified) {
+				deduped[i] = article
+			}
+			continue
+		}
+		index[k] = len(deduped)
+		deduped = append(deduped, article)
+	}
+	return deduped
+}
+
+// SortByPublishedAt orders the articles oldest first, or newest first if
+// newestFirst is set. Articles published at the same time are ordered by
+// ExternalID, either way, so the order doesn't depend on the input order.
+func (a Articles) SortByPublishedAt(newestFirst bool) {
+	sort.SliceStable(a, func(i, j int) bool {
+		if !a[i].PublishedAt.Equal(a[j].PublishedAt) {
+			if newestFirst {
+				return a[i].PublishedAt.After(a[j].PublishedAt)
+			}
+			return a[i].PublishedAt.Before(a[j].PublishedAt)
+		}
+		return a[i].ExternalID < a[j].ExternalID
+	})
+}
+
+// TagIDs returns the IDs of the article's tags in order.
+func (a *Article) TagIDs() []int64 {
+	ids := make([]int64, len(a.Tags))
+	for i, tag := range a.Tags {
+		ids[i] = tag.ID
+	}
+	return ids
+}
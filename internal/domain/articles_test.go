@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ArticlesTestSuite struct {
+	suite.Suite
+	now time.Time
+}
+
+func (s *ArticlesTestSuite) SetupTest() {
+	s.now = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+}
+
+func TestArticlesTestSuite(t *testing.T) {
+	suite.Run(t, new(ArticlesTestSuite))
+}
+
+func (s *ArticlesTestSuite) TestExternalIDs() {
+	articles := Articles{{ExternalID: 3}, {ExternalID: 1}, {ExternalID: 2}}
+
+	s.Equal([]int64{3, 1, 2}, articles.ExternalIDs())
+	s.Empty(Articles(nil).ExternalIDs())
+}
+
+func (s *ArticlesTestSuite) TestKeys() {
+	articles := Articles{{ExternalID: 3}, {ExternalID: 1}}
+	articles[1].SetExternalKey("https://example.com/feed/item/1")
+
+	s.Equal([]string{"3", "https://example.com/feed/item/1"}, articles.Keys())
+	s.Empty(Articles(nil).Keys())
+}
+
+func (s *ArticlesTestSuite) TestFilterAfter() {
+	articles := Articles{
+		{ExternalID: 1, PublishedAt: s.now.Add(-2 * time.Hour)},
+		{ExternalID: 2, PublishedAt: s.now},
+		{ExternalID: 3, PublishedAt: s.now.Add(time.Hour)},
+	}
+
+	filtered := articles.FilterAfter(s.now)
+
+	s.Equal([]int64{3}, filtered.ExternalIDs())
+}
+
+func (s *ArticlesTestSuite) TestDedup() {
+	articles := Articles{
+		{SourceID: "a", ExternalID: 1, Title: "old", LastModified: s.now.Add(-time.Hour)},
+		{SourceID: "a", ExternalID: 2, Title: "other", LastModified: s.now},
+		{SourceID: "a", ExternalID: 1, Title: "new", LastModified: s.now},
+		{SourceID: "b", ExternalID: 1, Title: "other source", LastModified: s.now},
+		{SourceID: "a", ExternalID: 2, Title: "stale copy", LastModified: s.now.Add(-time.Hour)},
+	}
+
+	deduped := articles.Dedup()
+
+	s.Require().Len(deduped, 3)
+	s.Equal("new", deduped[0].Title)
+	s.Equal("other", deduped[1].Title)
+	s.Equal("other source", deduped[2].Title)
+}
+
+func (s *ArticlesTestSuite) TestDedup_ByKey() {
+	// Keys deriving the same ExternalID are still different articles.
+	first := Article{SourceID: "a", Title: "first", ExternalKey: "item-1", ExternalID: -1}
+	second := Article{SourceID: "a", Title: "second", ExternalKey: "item-2", ExternalID: -1}
+
+	deduped := Articles{first, second}.Dedup()
+
+	s.Len(deduped, 2)
+}
+
+func (s *ArticlesTestSuite) TestSortByPublishedAt() {
+	articles := Articles{
+		{ExternalID: 2, PublishedAt: s.now},
+		{ExternalID: 1, PublishedAt: s.now.Add(-time.Hour)},
+		{ExternalID: 4, PublishedAt: s.now},
+		{ExternalID: 3, PublishedAt: s.now.Add(time.Hour)},
+	}
+
+	articles.SortByPublishedAt(false)
+	s.Equal([]int64{1, 2, 4, 3}, articles.ExternalIDs())
+
+	articles.SortByPublishedAt(true)
+	s.Equal([]int64{3, 2, 4, 1}, articles.ExternalIDs())
+}
+
+func (s *ArticlesTestSuite) TestSortByPublishedAt_TiesByExternalID() {
+	// The same articles in any input order sort the same way.
+	for _, ids := range [][]int64{{5, 2, 9, 1}, {1, 9, 2, 5}, {9, 5, 1, 2}} {
+		var articles Articles
+		for _, id := range ids {
+			publishedAt := s.now
+			if id == 9 {
+				publishedAt = s.now.Add(-time.Hour)
+			}
+			articles = append(articles, Article{ExternalID: id, PublishedAt: publishedAt})
+		}
+
+		articles.SortByPublishedAt(false)
+		s.Equal([]int64{9, 1, 2, 5}, articles.ExternalIDs())
+
+		articles.SortByPublishedAt(true)
+		s.Equal([]int64{1, 2, 5, 9}, articles.ExternalIDs())
+	}
+}
+
+func (s *ArticlesTestSuite) TestTagIDs() {
+	article := Article{Tags: []Tag{{ID: 5, Label: "x"}, {ID: 2, Label: "y"}}}
+
+	s.Equal([]int64{5, 2}, article.TagIDs())
+}
+
+func (s *ArticlesTestSuite) TestSyntheticTagID() {
+	id := SyntheticTagID("England")
+
+	s.Negative(id)
+	s.Equal(id, SyntheticTagID("England"))
+	s.NotEqual(id, SyntheticTagID("Australia"))
+	s.Negative(SyntheticTagID(""))
+}
+
+func (s *ArticlesTestSuite) TestKey() {
+	numeric := Article{ExternalID: 67890}
+	s.Equal("67890", numeric.Key())
+
+	var keyed Article
+	keyed.SetExternalKey("https://example.com/feed/item/1")
+	s.Equal("https://example.com/feed/item/1", keyed.Key())
+	s.Negative(keyed.ExternalID)
+
+	var same Article
+	same.SetExternalKey("https://example.com/feed/item/1")
+	s.Equal(keyed.ExternalID, same.ExternalID)
+	same.SetExternalKey("https://example.com/feed/item/2")
+	s.NotEqual(keyed.ExternalID, same.ExternalID)
+}
+
+func (s *ArticlesTestSuite) TestNormalizeTagLabel() {
+	for _, label := range []string{"Test Match", "test match", "TEST MATCH", "  Test   Match ", "test\tmatch\n"} {
+		s.Equal("test match", NormalizeTagLabel(label), label)
+	}
+	s.Equal("", NormalizeTagLabel("   "))
+}
+
+func (s *ArticlesTestSuite) TestValidStatus() {
+	for _, status := range []string{StatusDraft, StatusPublished, StatusArchived} {
+		s.True(ValidStatus(status), status)
+	}
+	s.False(ValidStatus(""))
+	s.False(ValidStatus("deleted"))
+}
+
+func (s *ArticlesTestSuite) TestContentHash_IgnoresStatus() {
+	article := Article{ExternalID: 1, Title: "Title", Status: StatusPublished}
+	archived := article
+	archived.Status = StatusArchived
+
+	s.Equal(article.ContentHash(), archived.ContentHash())
+}
+
+func (s *ArticlesTestSuite) TestDiff_ChangedTitle() {
+	stored := Article{ID: 7, ExternalID: 1, Title: "Old title", Status: StatusPublished, PublishedAt: s.now}
+	incoming := Article{ExternalID: 1, Title: "New title", PublishedAt: s.now}
+
+	s.Equal([]FieldChange{
+		{Field: "title", Old: []byte(`"Old title"`), New: []byte(`"New title"`)},
+	}, stored.Diff(&incoming))
+}
+
+func (s *ArticlesTestSuite) TestDiff_AddedAndRemovedFields() {
+	stored := Article{ExternalID: 1, Language: "en-GB", Tags: []Tag{{ID: 2, Label: "b"}, {ID: 1, Label: "a"}}}
+	incoming := Article{ExternalID: 1, ReadingTime: 3, Tags: []Tag{{ID: 1, Label: "a"}, {ID: 2, Label: "b"}}}
+
+	s.Equal([]FieldChange{
+		{Field: "language", Old: []byte(`"en-GB"`), New: []byte(`null`)},
+		{Field: "reading_time", Old: []byte(`null`), New: []byte(`3`)},
+	}, stored.Diff(&incoming))
+}
+
+func (s *ArticlesTestSuite) TestDiff_Unchanged() {
+	stored := Article{ExternalID: 1, Title: "Title", PublishedAt: s.now.In(time.FixedZone("CET", 3600))}
+	incoming := Article{ExternalID: 1, Title: "Title", PublishedAt: s.now}
+
+	s.Empty(stored.Diff(&incoming))
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "context"
+
+type sourceIDKey struct{}
+
+// WithSourceID returns a copy of ctx carrying the ID of the source the work
+// done with it belongs to, so stores and the transaction manager can tag their
+// logs and metrics by source. It returns ctx itself if it already carries
+// that ID.
+func WithSourceID(ctx context.Context, sourceID string) context.Context {
+	if SourceIDFromContext(ctx) == sourceID {
+		return ctx
+	}
+	return context.WithValue(ctx, sourceIDKey{}, sourceID)
+}
+
+// SourceIDFromContext returns the source ID carried by ctx, or "" if it
+// carries none.
+func SourceIDFromContext(ctx context.Context) string {
+	sourceID, _ := ctx.Value(sourceIDKey{}).(string)
+	return sourceID
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ContextTestSuite struct {
+	suite.Suite
+}
+
+func TestContextTestSuite(t *testing.T) {
+	suite.Run(t, new(ContextTestSuite))
+}
+
+func (s *ContextTestSuite) TestSourceID() {
+	ctx := context.Background()
+	s.Empty(SourceIDFromContext(ctx))
+
+	ecbCtx := WithSourceID(ctx, "ecb")
+	s.Equal("ecb", SourceIDFromContext(ecbCtx))
+	s.Empty(SourceIDFromContext(ctx))
+
+	s.Equal("other", SourceIDFromContext(WithSourceID(ecbCtx, "other")))
+}
+
+func (s *ContextTestSuite) TestWithSourceID_ReusesMatchingContext() {
+	ctx := WithSourceID(context.Background(), "ecb")
+
+	s.Same(ctx, WithSourceID(ctx, "ecb"))
+}
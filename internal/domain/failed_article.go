@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FailedArticle records an article whose save has failed. Once QuarantinedAt
+// is set, syncs skip the article until the record is cleared.
+type FailedArticle struct {
+	SourceID      string          `db:"source_id" json:"source_id"`
+	ExternalID    int64           `db:"external_id" json:"external_id"`
+	ExternalKey   string          `db:"external_key" json:"external_key"`
+	Attempts      int             `db:"attempts" json:"attempts"`
+	LastError     string          `db:"last_error" json:"last_error"`
+	Article       json.RawMessage `db:"article" json:"article"`
+	FirstFailedAt time.Time       `db:"first_failed_at" json:"first_failed_at"`
+	LastFailedAt  time.Time       `db:"last_failed_at" json:"last_failed_at"`
+	QuarantinedAt *time.Time      `db:"quarantined_at" json:"quarantined_at,omitempty"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// RawPayload is the upstream representation an article was last mapped from.
+type RawPayload struct {
+	ID          int64     `db:"id"`
+	SourceID    string    `db:"source_id"`
+	ExternalID  int64     `db:"external_id"`
+	ExternalKey string    `db:"external_key"`
+	Payload     []byte    `db:"payload"`
+	FetchedAt   time.Time `db:"fetched_at"`
+}
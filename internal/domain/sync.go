@@ -4,12 +4,69 @@ import "time"
 
 // SyncStats holds statistics about a sync operation.
 type SyncStats struct {
-	SourceID  string
-	Fetched   int
-	New       int
-	Updated   int
-	Skipped   int
-	Errors    int
-	Published int
-	Duration  time.Duration
+	SourceID   string        `json:"source_id"`
+	Fetched    int           `json:"fetched"`
+	New        int           `json:"new"`
+	Updated    int           `json:"updated"`
+	Skipped    int           `json:"skipped"`
+	Errors     int           `json:"errors"`
+	Published  int           `json:"published"`
+	Suppressed int           `json:"suppressed"` // saved, but not published by the publish filter
+	Deferred   int           `json:"deferred"`
+	Duration   time.Duration `json:"duration_ns"`
+	// Quarantined counts the fetched articles skipped because they are
+	// quarantined after repeatedly failing to save.
+	Quarantined int `json:"quarantined"`
+	// TagErrors counts the articles saved without their tags because storing
+	// or linking them failed and such errors are tolerated.
+	TagErrors int `json:"tag_errors"`
+	// FetchDuration, PersistDuration and PublishDuration break Duration down
+	// by stage. Persisting and publishing are summed over the articles.
+	FetchDuration   time.Duration `json:"fetch_duration_ns"`
+	PersistDuration time.Duration `json:"persist_duration_ns"`
+	PublishDuration time.Duration `json:"publish_duration_ns"`
+	// PublishDeferred counts the updates stored but not published because the
+	// article was modified within the quiet period. A later sync publishes them.
+	PublishDeferred int `json:"publish_deferred"`
+}
+
+// SyncResult is the outcome of a sync: its stats and, when collected, the
+// articles it saved.
+type SyncResult struct {
+	SyncStats
+	// Changes lists the saved articles in the order they were saved.
+	Changes []ArticleChange `json:"changes,omitempty"`
+}
+
+// ArticleChange is an article created or updated by a sync.
+type ArticleChange struct {
+	ID         int64  `json:"id"`
+	ExternalID int64  `json:"external_id"`
+	Action     string `json:"action"` // ActionCreated or ActionUpdated
+	// Diff lists the fields an update changes. Only dry runs fill it in.
+	Diff []FieldChange `json:"diff,omitempty"`
+}
+
+const (
+	ActionCreated = "created"
+	ActionUpdated = "updated"
+)
+
+// SourceHealth aggregates the outcome of a source's syncs.
+type SourceHealth struct {
+	SourceID      string     `db:"source_id" json:"source_id"`
+	LastSuccessAt *time.Time `db:"last_success_at" json:"last_success_at,omitempty"`
+	LastFailureAt *time.Time `db:"last_failure_at" json:"last_failure_at,omitempty"`
+	// ConsecutiveFailures counts the failed syncs since the last successful
+	// one; LastError is the error of the last failed sync.
+	ConsecutiveFailures int    `db:"consecutive_failures" json:"consecutive_failures"`
+	LastError           string `db:"last_error" json:"last_error,omitempty"`
+	// Runs counts every sync, failed or not, and AverageDuration is their mean
+	// duration.
+	Runs            int           `db:"runs" json:"runs"`
+	AverageDuration time.Duration `db:"average_duration_ns" json:"average_duration_ns"`
+	// LastCheckAt is when the source was last health-checked, and
+	// LastCheckError the error of that check, empty if it passed.
+	LastCheckAt    *time.Time `db:"last_check_at" json:"last_check_at,omitempty"`
+	LastCheckError string     `db:"last_check_error" json:"last_check_error,omitempty"`
 }
@@ -0,0 +1,44 @@
+package enrich
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"news_fetcher/internal/domain"
+)
+
+// ReadingTimeName is the name of the reading-time enricher in the config.
+const ReadingTimeName = "reading_time"
+
+// DefaultWordsPerMinute is a typical adult reading speed.
+const DefaultWordsPerMinute = 200
+
+// htmlTag matches markup in article bodies, which is not read.
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// ReadingTime sets Article.ReadingTime to the minutes needed to read the body,
+// rounded up. Articles without a body get zero.
+type ReadingTime struct {
+	wordsPerMinute int
+}
+
+// NewReadingTime creates a reading-time enricher. A non-positive
+// wordsPerMinute means DefaultWordsPerMinute.
+func NewReadingTime(wordsPerMinute int) *ReadingTime {
+	if wordsPerMinute <= 0 {
+		wordsPerMinute = DefaultWordsPerMinute
+	}
+	return &ReadingTime{wordsPerMinute: wordsPerMinute}
+}
+
+func (r *ReadingTime) Enrich(_ context.Context, article *domain.Article) error {
+	if article.Body == nil {
+		article.ReadingTime = 0
+		return nil
+	}
+
+	words := len(strings.Fields(htmlTag.ReplaceAllString(*article.Body, " ")))
+	article.ReadingTime = (words + r.wordsPerMinute - 1) / r.wordsPerMinute
+	return nil
+}
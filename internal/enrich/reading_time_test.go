@@ -0,0 +1,70 @@
+package enrich
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/testdata/utils"
+)
+
+type ReadingTimeTestSuite struct {
+	suite.Suite
+	enricher *ReadingTime
+}
+
+func (s *ReadingTimeTestSuite) SetupTest() {
+	s.enricher = NewReadingTime(DefaultWordsPerMinute)
+}
+
+func TestReadingTimeTestSuite(t *testing.T) {
+	suite.Run(t, new(ReadingTimeTestSuite))
+}
+
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("word ", n))
+}
+
+func (s *ReadingTimeTestSuite) TestEnrich() {
+	tests := []struct {
+		name string
+		body *string
+		want int
+	}{
+		{"no body", nil, 0},
+		{"empty body", utils.Ptr(""), 0},
+		{"one word", utils.Ptr("word"), 1},
+		{"exactly one minute", utils.Ptr(words(200)), 1},
+		{"rounds up", utils.Ptr(words(201)), 2},
+		{"long read", utils.Ptr(words(1000)), 5},
+		{"markup not counted", utils.Ptr("<p class=\"lead\">" + words(200) + "</p><img src=\"a.jpg\">"), 1},
+		{"tags separate words", utils.Ptr("<p>" + words(200) + "</p><p>more</p>"), 2},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			article := &domain.Article{Body: tt.body, ReadingTime: 99}
+
+			err := s.enricher.Enrich(context.Background(), article)
+
+			s.Require().NoError(err)
+			s.Equal(tt.want, article.ReadingTime)
+		})
+	}
+}
+
+func (s *ReadingTimeTestSuite) TestNewReadingTime_CustomSpeed() {
+	article := &domain.Article{Body: utils.Ptr(words(300))}
+
+	err := NewReadingTime(100).Enrich(context.Background(), article)
+
+	s.Require().NoError(err)
+	s.Equal(3, article.ReadingTime)
+}
+
+func (s *ReadingTimeTestSuite) TestNewReadingTime_DefaultSpeed() {
+	s.Equal(DefaultWordsPerMinute, NewReadingTime(0).wordsPerMinute)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "news_fetcher"
+
+var (
+	// SchedulerSkippedTicks counts ticks dropped because the previous sync overran the interval.
+	SchedulerSkippedTicks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "scheduler_skipped_ticks_total",
+		Help:      "Scheduler ticks skipped because the previous sync was still running.",
+	})
+
+	// LastSuccessTimestamp is the unix time of the last successful sync per source.
+	LastSuccessTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful sync.",
+	}, []string{"source"})
+
+	// LastPublishedTimestamp is the unix time the newest synced article was
+	// published, per source.
+	LastPublishedTimestamp = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_published_timestamp_seconds",
+		Help:      "Unix time the newest synced article was published.",
+	}, []string{"source"})
+
+	// SyncStageDuration is the time a sync spent per stage: fetch, persist or publish.
+	SyncStageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sync_stage_duration_seconds",
+		Help:      "Time a sync spent fetching, persisting or publishing articles.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+	}, []string{"source", "stage"})
+
+	// SourceConsecutiveFailures is the number of failed syncs per source since
+	// its last successful one.
+	SourceConsecutiveFailures = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "source_consecutive_failures",
+		Help:      "Failed syncs since the last successful one.",
+	}, []string{"source"})
+
+	// SourceAverageSyncDuration is the mean duration of the syncs per source.
+	SourceAverageSyncDuration = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "source_average_sync_duration_seconds",
+		Help:      "Mean duration of the syncs of a source.",
+	}, []string{"source"})
+
+	// RetentionDeletedArticles counts the articles deleted per source because
+	// they fell out of the retention window.
+	RetentionDeletedArticles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "retention_deleted_articles_total",
+		Help:      "Articles deleted after falling out of the retention window.",
+	}, []string{"source"})
+)
@@ -0,0 +1,97 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"news_fetcher/internal/domain"
+)
+
+const (
+	cloudEventsSpecVersion = "1.0"
+	cloudEventsContentType = "application/cloudevents+json"
+
+	EventTypeArticleCreated = "com.newsfetcher.article.created"
+	EventTypeArticleUpdated = "com.newsfetcher.article.updated"
+)
+
+// CloudEvent is a CloudEvents 1.0 structured-mode envelope carrying an article.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+	// Extension attributes carrying the MessageMetadata; CloudEvents only
+	// allows lowercase alphanumeric attribute names.
+	BodyLength    int  `json:"bodylength"`
+	SummaryLength int  `json:"summarylength"`
+	HasImage      bool `json:"hasimage"`
+	TagCount      int  `json:"tagcount"`
+	// Sequence is the ArticleMessage sequence number, if numbered.
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// newCloudEvent wraps an already projected article. The ID is derived from the
+// article version, so a republished version keeps its ID and consumers can
+// deduplicate on it.
+func newCloudEvent(article *domain.Article, isNew bool, data json.RawMessage, now time.Time) CloudEvent {
+	eventType := EventTypeArticleUpdated
+	if isNew {
+		eventType = EventTypeArticleCreated
+	}
+
+	metadata := NewMessageMetadata(article)
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              fmt.Sprintf("%s-%d-%d", article.SourceID, article.ExternalID, article.LastModified.UnixMilli()),
+		Source:          "/news_fetcher/" + article.SourceID,
+		Type:            eventType,
+		Subject:         fmt.Sprintf("%d", article.ExternalID),
+		Time:            now,
+		DataContentType: "application/json",
+		Data:            data,
+		BodyLength:      metadata.BodyLength,
+		SummaryLength:   metadata.SummaryLength,
+		HasImage:        metadata.HasImage,
+		TagCount:        metadata.TagCount,
+		Sequence:        article.Sequence,
+	}
+}
+
+// Metadata returns the metadata carried in the extension attributes.
+func (e CloudEvent) Metadata() MessageMetadata {
+	return MessageMetadata{
+		BodyLength:    e.BodyLength,
+		SummaryLength: e.SummaryLength,
+		HasImage:      e.HasImage,
+		TagCount:      e.TagCount,
+	}
+}
+
+// headers returns the event attributes as AMQP application properties, per the
+// CloudEvents AMQP binding, so brokers can route without parsing the body.
+func (e CloudEvent) headers() amqp.Table {
+	headers := amqp.Table{
+		"cloudEvents:specversion":   e.SpecVersion,
+		"cloudEvents:id":            e.ID,
+		"cloudEvents:source":        e.Source,
+		"cloudEvents:type":          e.Type,
+		"cloudEvents:subject":       e.Subject,
+		"cloudEvents:time":          e.Time.Format(time.RFC3339Nano),
+		"cloudEvents:bodylength":    int32(e.BodyLength),
+		"cloudEvents:summarylength": int32(e.SummaryLength),
+		"cloudEvents:hasimage":      e.HasImage,
+		"cloudEvents:tagcount":      int32(e.TagCount),
+	}
+	if e.Sequence > 0 {
+		headers["cloudEvents:sequence"] = e.Sequence
+	}
+	return headers
+}
@@ -0,0 +1,145 @@
+package publisher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ConsumerConfig configures a Consumer.
+type ConsumerConfig struct {
+	URL       string
+	QueueName string
+	// Prefetch is how many unacknowledged messages the broker delivers at a
+	// time. Zero means defaultPrefetch.
+	Prefetch int
+}
+
+const defaultPrefetch = 10
+
+// Consumer reads the article messages published to a queue, e.g. for
+// end-to-end tests or to tail the events while debugging. It understands
+// every format the publisher writes: legacy or CloudEvents, gzipped or not.
+type Consumer struct {
+	cfg    ConsumerConfig
+	logger *slog.Logger
+}
+
+func NewConsumer(cfg ConsumerConfig, logger *slog.Logger) *Consumer {
+	if cfg.Prefetch <= 0 {
+		cfg.Prefetch = defaultPrefetch
+	}
+	return &Consumer{cfg: cfg, logger: logger.With("queue", cfg.QueueName)}
+}
+
+// Consume passes every message of the queue to handler until ctx is cancelled
+// or the connection is lost. A message is acked when handler returns nil. If
+// it fails, the message is requeued once and dropped (or dead-lettered, if the
+// queue has a dead-letter exchange) the second time; a message that can't be
+// decoded is dropped right away.
+func (c *Consumer) Consume(ctx context.Context, handler func(ArticleMessage) error) error {
+	conn, err := amqp.DialConfig(c.cfg.URL, amqp.Config{
+		Dial: amqp.DefaultDial(dialTimeout),
+	})
+	if err != nil {
+		return fmt.Errorf("connect to rabbitmq: %w", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open channel: %w", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(c.cfg.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("set prefetch: %w", err)
+	}
+
+	deliveries, err := ch.ConsumeWithContext(ctx, c.cfg.QueueName, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consume %s: %w", c.cfg.QueueName, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case d, ok := <-deliveries:
+			if !ok {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return errors.New("delivery channel closed")
+			}
+			if err := c.handle(d, handler); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// handle decodes and handles one delivery and acknowledges it. Only a failed
+// acknowledgement is returned.
+func (c *Consumer) handle(d amqp.Delivery, handler func(ArticleMessage) error) error {
+	msg, err := DecodeDelivery(d)
+	if err != nil {
+		c.logger.Warn("dropping undecodable message", "message_id", d.MessageId, "error", err)
+		return d.Nack(false, false)
+	}
+
+	if err := handler(msg); err != nil {
+		requeue := !d.Redelivered
+		c.logger.Warn("message handler failed",
+			"external_id", msg.Article.ExternalID,
+			"requeue", requeue,
+			"error", err,
+		)
+		return d.Nack(false, requeue)
+	}
+	return d.Ack(false)
+}
+
+// DecodeDelivery decodes a message written by the publisher, decompressing it
+// and unwrapping a CloudEvents envelope as needed.
+func DecodeDelivery(d amqp.Delivery) (ArticleMessage, error) {
+	body := d.Body
+	if d.ContentEncoding == "gzip" {
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return ArticleMessage{}, fmt.Errorf("decompress message: %w", err)
+		}
+		body, err = io.ReadAll(zr)
+		if err != nil {
+			return ArticleMessage{}, fmt.Errorf("decompress message: %w", err)
+		}
+	}
+
+	var msg ArticleMessage
+	if d.ContentType == cloudEventsContentType {
+		var event CloudEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			return ArticleMessage{}, fmt.Errorf("decode cloudevent: %w", err)
+		}
+		if err := json.Unmarshal(event.Data, &msg.Article); err != nil {
+			return ArticleMessage{}, fmt.Errorf("decode cloudevent data: %w", err)
+		}
+		msg.Action = actionFor(event.Type == EventTypeArticleCreated)
+		msg.Metadata = event.Metadata()
+		msg.Timestamp = event.Time
+		msg.Sequence = event.Sequence
+		return msg, nil
+	}
+
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return ArticleMessage{}, fmt.Errorf("decode message: %w", err)
+	}
+	return msg, nil
+}
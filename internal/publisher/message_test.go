@@ -0,0 +1,338 @@
+package publisher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/testdata/utils"
+)
+
+type MessageTestSuite struct {
+	suite.Suite
+	logger  *slog.Logger
+	now     time.Time
+	article *domain.Article
+}
+
+func (s *MessageTestSuite) SetupTest() {
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.now = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.article = &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   123,
+		Title:        "Title",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  s.now,
+		LastModified: s.now,
+	}
+}
+
+func TestMessageTestSuite(t *testing.T) {
+	suite.Run(t, new(MessageTestSuite))
+}
+
+func (s *MessageTestSuite) publisher(cfg Config) *RabbitMQ {
+	pub, err := newRabbitMQ(cfg, s.logger)
+	s.Require().NoError(err)
+	return pub
+}
+
+func (s *MessageTestSuite) TestLegacyFormat() {
+	pub := s.publisher(Config{})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal("application/json", msg.ContentType)
+	s.Equal(uint8(amqp.Persistent), msg.DeliveryMode)
+
+	var received ArticleMessage
+	s.Require().NoError(json.Unmarshal(msg.Body, &received))
+	s.Equal("create", received.Action)
+	s.Equal(*s.article, received.Article)
+	s.Equal(s.now, received.Timestamp)
+}
+
+func (s *MessageTestSuite) TestCloudEventsFormat() {
+	pub := s.publisher(Config{Format: FormatCloudEvents})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal("application/cloudevents+json", msg.ContentType)
+	s.Equal(uint8(amqp.Persistent), msg.DeliveryMode)
+
+	var event CloudEvent
+	s.Require().NoError(json.Unmarshal(msg.Body, &event))
+	s.Equal("1.0", event.SpecVersion)
+	s.Equal("ecb-123-1735732800000", event.ID)
+	s.Equal("/news_fetcher/ecb", event.Source)
+	s.Equal(EventTypeArticleCreated, event.Type)
+	s.Equal("123", event.Subject)
+	s.Equal(s.now, event.Time)
+	s.Equal("application/json", event.DataContentType)
+
+	var article domain.Article
+	s.Require().NoError(json.Unmarshal(event.Data, &article))
+	s.Equal(*s.article, article)
+
+	s.Equal(event.ID, msg.MessageId)
+	s.Equal(event.Type, msg.Type)
+	s.Equal("1.0", msg.Headers["cloudEvents:specversion"])
+	s.Equal(EventTypeArticleCreated, msg.Headers["cloudEvents:type"])
+	s.Equal("/news_fetcher/ecb", msg.Headers["cloudEvents:source"])
+}
+
+func (s *MessageTestSuite) TestMetadata() {
+	s.article.Body = utils.Ptr("Ünïcödé body")
+	s.article.Summary = utils.Ptr("Summary")
+	s.article.ImageURL = utils.Ptr("https://example.com/image.jpg")
+	s.article.Tags = []domain.Tag{{ID: 1, Label: "News"}, {ID: 2, Label: "Cricket"}}
+	want := MessageMetadata{BodyLength: 12, SummaryLength: 7, HasImage: true, TagCount: 2}
+
+	s.Equal(want, NewMessageMetadata(s.article))
+	s.Equal(MessageMetadata{}, NewMessageMetadata(&domain.Article{ImageURL: utils.Ptr("")}))
+
+	// The metadata describes the whole article, even if the mask drops fields.
+	msg, err := s.publisher(Config{Fields: []string{"title"}}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	var received ArticleMessage
+	s.Require().NoError(json.Unmarshal(msg.Body, &received))
+	s.Equal(want, received.Metadata)
+
+	msg, err = s.publisher(Config{Format: FormatCloudEvents}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	var event CloudEvent
+	s.Require().NoError(json.Unmarshal(msg.Body, &event))
+	s.Equal(want, event.Metadata())
+	s.Equal(int32(12), msg.Headers["cloudEvents:bodylength"])
+	s.Equal(true, msg.Headers["cloudEvents:hasimage"])
+}
+
+func (s *MessageTestSuite) TestSequence() {
+	s.article.Sequence = 7
+
+	msg, err := s.publisher(Config{Fields: []string{"title"}}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	var received ArticleMessage
+	s.Require().NoError(json.Unmarshal(msg.Body, &received))
+	s.Equal(int64(7), received.Sequence)
+
+	msg, err = s.publisher(Config{Format: FormatCloudEvents}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	decoded, err := DecodeDelivery(delivery(msg))
+	s.Require().NoError(err)
+	s.Equal(int64(7), decoded.Sequence)
+	s.Equal(int64(7), msg.Headers["cloudEvents:sequence"])
+
+	// Unnumbered messages leave it out.
+	s.article.Sequence = 0
+	msg, err = s.publisher(Config{}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	s.NotContains(string(msg.Body), "sequence")
+	msg, err = s.publisher(Config{Format: FormatCloudEvents}).buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+	s.NotContains(msg.Headers, "cloudEvents:sequence")
+}
+
+func (s *MessageTestSuite) TestCloudEventsFormat_Update() {
+	pub := s.publisher(Config{Format: FormatCloudEvents})
+
+	msg, err := pub.buildMessage(s.article, false, s.now)
+	s.Require().NoError(err)
+
+	var event CloudEvent
+	s.Require().NoError(json.Unmarshal(msg.Body, &event))
+	s.Equal(EventTypeArticleUpdated, event.Type)
+}
+
+func (s *MessageTestSuite) TestCloudEventsFormat_AppliesFieldMask() {
+	pub := s.publisher(Config{Format: FormatCloudEvents, Fields: []string{"title"}})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	var event CloudEvent
+	s.Require().NoError(json.Unmarshal(msg.Body, &event))
+	s.JSONEq(`{"title":"Title"}`, string(event.Data))
+}
+
+func (s *MessageTestSuite) TestCompression_RoundTrip() {
+	s.article.Body = utils.Ptr(strings.Repeat("long body ", 1000))
+	pub := s.publisher(Config{CompressThreshold: 1024})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal("gzip", msg.ContentEncoding)
+	s.Less(len(msg.Body), 1024)
+
+	var received ArticleMessage
+	s.Require().NoError(json.Unmarshal(gunzip(s.T(), msg.Body), &received))
+	s.Equal(*s.article, received.Article)
+}
+
+func (s *MessageTestSuite) TestCompression_BelowThreshold() {
+	pub := s.publisher(Config{CompressThreshold: 1 << 20})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Empty(msg.ContentEncoding)
+	s.True(json.Valid(msg.Body))
+}
+
+func (s *MessageTestSuite) TestSign_KnownVector() {
+	// RFC 4231, test case 2.
+	s.Equal(
+		"sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		Sign([]byte("Jefe"), []byte("what do ya want for nothing?")),
+	)
+}
+
+func (s *MessageTestSuite) TestSigning() {
+	pub := s.publisher(Config{SigningSecret: "secret"})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal(Sign([]byte("secret"), msg.Body), msg.Headers[SignatureHeader])
+}
+
+func (s *MessageTestSuite) TestSigning_CloudEventsKeepsHeaders() {
+	pub := s.publisher(Config{Format: FormatCloudEvents, SigningSecret: "secret"})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal(Sign([]byte("secret"), msg.Body), msg.Headers[SignatureHeader])
+	s.Equal("1.0", msg.Headers["cloudEvents:specversion"])
+}
+
+func (s *MessageTestSuite) TestSigning_SignsCompressedBody() {
+	s.article.Body = utils.Ptr(strings.Repeat("long body ", 1000))
+	pub := s.publisher(Config{CompressThreshold: 1024, SigningSecret: "secret"})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.Equal("gzip", msg.ContentEncoding)
+	s.Equal(Sign([]byte("secret"), msg.Body), msg.Headers[SignatureHeader])
+}
+
+func (s *MessageTestSuite) TestSigning_Disabled() {
+	pub := s.publisher(Config{})
+
+	msg, err := pub.buildMessage(s.article, true, s.now)
+	s.Require().NoError(err)
+
+	s.NotContains(msg.Headers, SignatureHeader)
+}
+
+func (s *MessageTestSuite) TestApplyOptions() {
+	pub := s.publisher(Config{MessageTTL: time.Hour, Priority: 3})
+
+	var msg amqp.Publishing
+	pub.applyOptions(&msg, PublishOptions{})
+	s.Equal("3600000", msg.Expiration)
+	s.Equal(uint8(3), msg.Priority)
+
+	pub.applyOptions(&msg, PublishOptions{TTL: 1500 * time.Millisecond, Priority: utils.Ptr(uint8(0))})
+	s.Equal("1500", msg.Expiration)
+	s.Zero(msg.Priority)
+}
+
+func (s *MessageTestSuite) TestApplyOptions_Unset() {
+	pub := s.publisher(Config{})
+
+	var msg amqp.Publishing
+	pub.applyOptions(&msg, PublishOptions{})
+	s.Empty(msg.Expiration)
+	s.Zero(msg.Priority)
+}
+
+func (s *MessageTestSuite) TestSyncCompleted() {
+	pub := s.publisher(Config{SigningSecret: "secret", CompressThreshold: 1})
+	stats := &domain.SyncStats{SourceID: "ecb", Fetched: 3, New: 2, Updated: 1, Duration: time.Second}
+
+	msg, err := pub.buildSyncCompleted(stats, s.now)
+	s.Require().NoError(err)
+
+	s.Equal(MessageTypeSyncCompleted, msg.Type)
+	s.Empty(msg.ContentEncoding)
+	s.Equal(Sign([]byte("secret"), msg.Body), msg.Headers[SignatureHeader])
+
+	var received SyncCompletedMessage
+	s.Require().NoError(json.Unmarshal(msg.Body, &received))
+	s.Equal(SyncCompletedMessage{Type: MessageTypeSyncCompleted, Stats: *stats, Timestamp: s.now}, received)
+}
+
+func (s *MessageTestSuite) TestDecodeDelivery_RoundTrip() {
+	s.article.Body = utils.Ptr(strings.Repeat("long body ", 1000))
+	configs := map[string]Config{
+		"legacy":                 {},
+		"legacy compressed":      {CompressThreshold: 1024},
+		"cloudevents":            {Format: FormatCloudEvents},
+		"cloudevents compressed": {Format: FormatCloudEvents, CompressThreshold: 1024},
+	}
+
+	for name, cfg := range configs {
+		s.Run(name, func() {
+			msg, err := s.publisher(cfg).buildMessage(s.article, false, s.now)
+			s.Require().NoError(err)
+
+			received, err := DecodeDelivery(delivery(msg))
+
+			s.Require().NoError(err)
+			s.Equal("update", received.Action)
+			s.Equal(*s.article, received.Article)
+			s.Equal(NewMessageMetadata(s.article), received.Metadata)
+			s.Equal(s.now, received.Timestamp)
+		})
+	}
+}
+
+func (s *MessageTestSuite) TestDecodeDelivery_Invalid() {
+	_, err := DecodeDelivery(amqp.Delivery{ContentType: "application/json", Body: []byte("not json")})
+	s.Error(err)
+
+	_, err = DecodeDelivery(amqp.Delivery{ContentEncoding: "gzip", Body: []byte("not gzip")})
+	s.Error(err)
+}
+
+// delivery returns the delivery a consumer receives for a published message.
+func delivery(msg amqp.Publishing) amqp.Delivery {
+	return amqp.Delivery{
+		ContentType:     msg.ContentType,
+		ContentEncoding: msg.ContentEncoding,
+		Headers:         msg.Headers,
+		Body:            msg.Body,
+	}
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("open gzip reader: %v", err)
+	}
+	defer zr.Close()
+
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	return out
+}
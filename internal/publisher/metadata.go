@@ -0,0 +1,34 @@
+package publisher
+
+import (
+	"unicode/utf8"
+
+	"news_fetcher/internal/domain"
+)
+
+// MessageMetadata is computed from the article at publish time, so consumers
+// can route or prioritize messages without parsing the article. It describes
+// the whole article, even if the field mask leaves parts of it out.
+type MessageMetadata struct {
+	BodyLength    int  `json:"body_length"`    // in characters
+	SummaryLength int  `json:"summary_length"` // in characters
+	HasImage      bool `json:"has_image"`
+	TagCount      int  `json:"tag_count"`
+}
+
+// NewMessageMetadata computes the metadata of an article.
+func NewMessageMetadata(article *domain.Article) MessageMetadata {
+	return MessageMetadata{
+		BodyLength:    runeCount(article.Body),
+		SummaryLength: runeCount(article.Summary),
+		HasImage:      article.ImageURL != nil && *article.ImageURL != "",
+		TagCount:      len(article.Tags),
+	}
+}
+
+func runeCount(s *string) int {
+	if s == nil {
+		return 0
+	}
+	return utf8.RuneCountInString(*s)
+}
@@ -0,0 +1,35 @@
+package publisher
+
+import (
+	"context"
+	"log/slog"
+
+	"news_fetcher/internal/domain"
+)
+
+// Null is a publisher that drops every message. It lets the syncer run
+// without a broker.
+type Null struct {
+	logger *slog.Logger
+}
+
+func NewNull(logger *slog.Logger) *Null {
+	return &Null{logger: logger}
+}
+
+func (n *Null) Publish(ctx context.Context, article *domain.Article, isNew bool) error {
+	n.logger.Debug("publisher disabled, dropping article",
+		"external_id", article.ExternalID,
+	)
+	return nil
+}
+
+// Discards reports that nothing Null publishes is delivered, so it isn't
+// counted as published.
+func (n *Null) Discards() bool {
+	return true
+}
+
+func (n *Null) Close() error {
+	return nil
+}
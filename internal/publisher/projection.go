@@ -0,0 +1,88 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"news_fetcher/internal/domain"
+)
+
+// fieldMask projects an article onto a subset of its JSON fields before it is
+// published. Field names are the article's JSON keys.
+type fieldMask struct {
+	include map[string]bool
+	exclude map[string]bool
+}
+
+// articleFields are the article's JSON keys, the names a fieldMask accepts.
+var articleFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeFor[domain.Article]()
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}()
+
+// newFieldMask returns nil if neither list is set, meaning the whole article
+// is published. If include is set, only those fields are kept; exclude then
+// removes fields from what remains. A name that isn't an article field is an
+// error, rather than silently matching nothing.
+func newFieldMask(include, exclude []string) (*fieldMask, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, nil
+	}
+
+	var unknown []string
+	for _, f := range append(append([]string{}, include...), exclude...) {
+		if !articleFields[f] {
+			unknown = append(unknown, fmt.Sprintf("%q", f))
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown article fields: %s", strings.Join(unknown, ", "))
+	}
+
+	m := &fieldMask{}
+	if len(include) > 0 {
+		m.include = make(map[string]bool, len(include))
+		for _, f := range include {
+			m.include[f] = true
+		}
+	}
+	if len(exclude) > 0 {
+		m.exclude = make(map[string]bool, len(exclude))
+		for _, f := range exclude {
+			m.exclude[f] = true
+		}
+	}
+	return m, nil
+}
+
+func (m *fieldMask) apply(article *domain.Article) (json.RawMessage, error) {
+	data, err := json.Marshal(article)
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decode article fields: %w", err)
+	}
+
+	for name := range fields {
+		if (m.include != nil && !m.include[name]) || m.exclude[name] {
+			delete(fields, name)
+		}
+	}
+
+	return json.Marshal(fields)
+}
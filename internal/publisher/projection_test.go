@@ -0,0 +1,104 @@
+package publisher
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/testdata/utils"
+)
+
+type ProjectionTestSuite struct {
+	suite.Suite
+	article *domain.Article
+}
+
+func (s *ProjectionTestSuite) SetupTest() {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.article = &domain.Article{
+		ID:           1,
+		SourceID:     "ecb",
+		ExternalID:   123,
+		Title:        "Title",
+		Body:         utils.Ptr("a very long body"),
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+}
+
+func TestProjectionTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectionTestSuite))
+}
+
+func (s *ProjectionTestSuite) mask(include, exclude []string) *fieldMask {
+	mask, err := newFieldMask(include, exclude)
+	s.Require().NoError(err)
+	return mask
+}
+
+func (s *ProjectionTestSuite) project(mask *fieldMask) map[string]json.RawMessage {
+	data, err := mask.apply(s.article)
+	s.Require().NoError(err)
+
+	var fields map[string]json.RawMessage
+	s.Require().NoError(json.Unmarshal(data, &fields))
+	return fields
+}
+
+func (s *ProjectionTestSuite) TestNoMask_PublishesWholeArticle() {
+	s.Nil(s.mask(nil, nil))
+
+	data, err := s.mask(nil, nil).apply(s.article)
+	s.Require().NoError(err)
+
+	var article domain.Article
+	s.Require().NoError(json.Unmarshal(data, &article))
+	s.Equal(*s.article, article)
+}
+
+func (s *ProjectionTestSuite) TestInclude() {
+	fields := s.project(s.mask([]string{"external_id", "canonical_url"}, nil))
+
+	s.Len(fields, 2)
+	s.JSONEq(`123`, string(fields["external_id"]))
+	s.JSONEq(`"https://example.com/article"`, string(fields["canonical_url"]))
+}
+
+func (s *ProjectionTestSuite) TestExclude() {
+	fields := s.project(s.mask(nil, []string{"body"}))
+
+	s.NotContains(fields, "body")
+	s.Contains(fields, "title")
+	s.Contains(fields, "canonical_url")
+}
+
+func (s *ProjectionTestSuite) TestIncludeAndExclude() {
+	fields := s.project(s.mask([]string{"title", "body"}, []string{"body"}))
+
+	s.Len(fields, 1)
+	s.Contains(fields, "title")
+}
+
+func (s *ProjectionTestSuite) TestUnknownFields() {
+	_, err := newFieldMask([]string{"title", "Title"}, []string{"raw"})
+
+	s.EqualError(err, `unknown article fields: "Title", "raw"`)
+}
+
+func (s *ProjectionTestSuite) TestSnakeCaseKeys_NullPointers() {
+	fields := s.project(nil)
+
+	s.JSONEq(`"ecb"`, string(fields["source_id"]))
+	s.JSONEq(`123`, string(fields["external_id"]))
+	s.JSONEq(`"https://example.com/article"`, string(fields["canonical_url"]))
+	s.JSONEq(`"2025-01-01T12:00:00Z"`, string(fields["published_at"]))
+	s.JSONEq(`null`, string(fields["description"]))
+	s.JSONEq(`null`, string(fields["image_url"]))
+	s.NotContains(fields, "tags")
+	s.NotContains(fields, "created_at")
+	s.NotContains(fields, "Title")
+}
@@ -1,10 +1,14 @@
 package publisher
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"sync"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
@@ -13,11 +17,16 @@ import (
 )
 
 type RabbitMQ struct {
-	conn       *amqp.Connection
-	channel    *amqp.Channel
-	exchange   string
-	routingKey string
-	logger     *slog.Logger
+	cfg         Config
+	exchange    string
+	routingKeys []string
+	mask        *fieldMask
+	format      string
+	logger      *slog.Logger
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
 }
 
 type Config struct {
@@ -25,22 +34,178 @@ type Config struct {
 	Exchange   string
 	RoutingKey string
 	QueueName  string
+	// Bindings declares additional queues, each bound with its own routing key.
+	// Every message is published once per distinct routing key.
+	Bindings []Binding
+	// Fields, if set, limits the published article to these JSON fields.
+	Fields []string
+	// ExcludeFields drops these JSON fields from the published article.
+	ExcludeFields []string
+	// Format is the message envelope: FormatLegacy (default) or FormatCloudEvents.
+	Format string
+	// CompressThreshold gzips message bodies larger than this many bytes.
+	// Zero disables compression.
+	CompressThreshold int
+	// SigningSecret, if set, signs every message body; the signature is sent
+	// in the SignatureHeader header.
+	SigningSecret string
+	// SyncCompletedRoutingKey, if set, is the routing key of the
+	// SyncCompletedMessage sent after every successful sync. No queue is
+	// declared for it; consumers bind their own.
+	SyncCompletedRoutingKey string
+	// Passive only checks that the exchange and queues exist, for brokers
+	// where they are provisioned separately and the syncer may not declare
+	// them. Connecting fails if one is missing; bindings are not created.
+	Passive bool
+	// MessageTTL, if set, is the default expiration of article messages: the
+	// broker drops them once they have waited in a queue this long.
+	MessageTTL time.Duration
+	// Priority, if set, is the default priority of article messages, from 1
+	// to MaxPriority, and the queues are declared as priority queues. The
+	// broker refuses to redeclare an existing queue as one, so turning it on
+	// for existing queues means recreating them.
+	Priority uint8
+}
+
+// MaxPriority is the x-max-priority the queues are declared with when
+// Config.Priority is set.
+const MaxPriority = 10
+
+// PublishOptions overrides the configured message properties for a single
+// article message.
+type PublishOptions struct {
+	// TTL, if set, replaces Config.MessageTTL.
+	TTL time.Duration
+	// Priority, if not nil, replaces Config.Priority. It only has an effect
+	// on priority queues, i.e. with Config.Priority set.
+	Priority *uint8
+}
+
+const (
+	FormatLegacy      = "legacy"
+	FormatCloudEvents = "cloudevents"
+)
+
+type Binding struct {
+	RoutingKey string
+	QueueName  string
 }
 
+// bindings returns the primary binding followed by the additional ones.
+func (c Config) bindings() []Binding {
+	all := make([]Binding, 0, len(c.Bindings)+1)
+	if c.QueueName != "" || c.RoutingKey != "" {
+		all = append(all, Binding{RoutingKey: c.RoutingKey, QueueName: c.QueueName})
+	}
+	return append(all, c.Bindings...)
+}
+
+// dialTimeout bounds how long a (re)connect attempt may block a publish or a
+// readiness check.
+const dialTimeout = 5 * time.Second
+
 func NewRabbitMQ(cfg Config, logger *slog.Logger) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(cfg.URL)
+	r, err := newRabbitMQ(cfg, logger)
 	if err != nil {
-		return nil, fmt.Errorf("connect to rabbitmq: %w", err)
+		return nil, err
+	}
+	if err := r.connect(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewLazyRabbitMQ returns a publisher that doesn't require the broker to be up.
+// If the initial connect fails, it reconnects on the next Publish or Ready call.
+// It only fails on an invalid config.
+func NewLazyRabbitMQ(cfg Config, logger *slog.Logger) (*RabbitMQ, error) {
+	r, err := newRabbitMQ(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.connect(); err != nil {
+		logger.Warn("rabbitmq unavailable, will retry on publish", "error", err)
+	}
+	return r, nil
+}
+
+func newRabbitMQ(cfg Config, logger *slog.Logger) (*RabbitMQ, error) {
+	var routingKeys []string
+	seen := make(map[string]bool)
+	for _, b := range cfg.bindings() {
+		if !seen[b.RoutingKey] {
+			seen[b.RoutingKey] = true
+			routingKeys = append(routingKeys, b.RoutingKey)
+		}
+	}
+
+	switch cfg.Format {
+	case "", FormatLegacy, FormatCloudEvents:
+	default:
+		logger.Warn("unknown message format, using legacy", "format", cfg.Format)
+	}
+
+	mask, err := newFieldMask(cfg.Fields, cfg.ExcludeFields)
+	if err != nil {
+		return nil, fmt.Errorf("field mask: %w", err)
+	}
+
+	return &RabbitMQ{
+		cfg:         cfg,
+		exchange:    cfg.Exchange,
+		routingKeys: routingKeys,
+		mask:        mask,
+		format:      cfg.Format,
+		logger:      logger,
+	}, nil
+}
+
+// connect dials the broker and declares the exchange and queues, or with
+// Passive checks that they exist.
+// The caller must hold r.mu or have exclusive access to r.
+func (r *RabbitMQ) connect() error {
+	conn, err := amqp.DialConfig(r.cfg.URL, amqp.Config{
+		Heartbeat: 10 * time.Second,
+		Locale:    "en_US",
+		Dial:      amqp.DefaultDial(dialTimeout),
+	})
+	if err != nil {
+		return fmt.Errorf("connect to rabbitmq: %w", err)
 	}
 
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("open channel: %w", err)
+		return fmt.Errorf("open channel: %w", err)
+	}
+
+	declare := r.declare
+	if r.cfg.Passive {
+		declare = r.declarePassive
+	}
+	if err := declare(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return err
 	}
 
-	err = ch.ExchangeDeclare(
-		cfg.Exchange,
+	r.conn = conn
+	r.channel = ch
+
+	r.logger.Info("connected to rabbitmq",
+		"exchange", r.cfg.Exchange,
+		"passive", r.cfg.Passive,
+		"bindings", len(r.cfg.bindings()),
+		"routing_keys", r.routingKeys,
+	)
+
+	return nil
+}
+
+// declare declares the exchange and the queues and binds them.
+func (r *RabbitMQ) declare(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclare(
+		r.cfg.Exchange,
 		"direct",
 		true,
 		false,
@@ -49,13 +214,48 @@ func NewRabbitMQ(cfg Config, logger *slog.Logger) (*RabbitMQ, error) {
 		nil,
 	)
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("declare exchange: %w", err)
+		return fmt.Errorf("declare exchange: %w", err)
+	}
+
+	var args amqp.Table
+	if r.cfg.Priority > 0 {
+		args = amqp.Table{"x-max-priority": MaxPriority}
 	}
 
-	q, err := ch.QueueDeclare(
-		cfg.QueueName,
+	for _, b := range r.cfg.bindings() {
+		q, err := ch.QueueDeclare(
+			b.QueueName,
+			true,
+			false,
+			false,
+			false,
+			args,
+		)
+		if err != nil {
+			return fmt.Errorf("declare queue %s: %w", b.QueueName, err)
+		}
+
+		err = ch.QueueBind(
+			q.Name,
+			b.RoutingKey,
+			r.cfg.Exchange,
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("bind queue %s: %w", b.QueueName, err)
+		}
+	}
+	return nil
+}
+
+// declarePassive checks that the exchange and the queues exist without
+// declaring them. A failed check closes the channel, so it stops at the first
+// missing one.
+func (r *RabbitMQ) declarePassive(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclarePassive(
+		r.cfg.Exchange,
+		"direct",
 		true,
 		false,
 		false,
@@ -63,93 +263,296 @@ func NewRabbitMQ(cfg Config, logger *slog.Logger) (*RabbitMQ, error) {
 		nil,
 	)
 	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("declare queue: %w", err)
+		return fmt.Errorf("exchange %s must exist in passive mode: %w", r.cfg.Exchange, err)
 	}
 
-	err = ch.QueueBind(
-		q.Name,
-		cfg.RoutingKey,
-		cfg.Exchange,
-		false,
-		nil,
-	)
-	if err != nil {
-		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("bind queue: %w", err)
+	for _, b := range r.cfg.bindings() {
+		if b.QueueName == "" {
+			continue
+		}
+		_, err := ch.QueueDeclarePassive(
+			b.QueueName,
+			true,
+			false,
+			false,
+			false,
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("queue %s must exist in passive mode: %w", b.QueueName, err)
+		}
+	}
+	return nil
+}
+
+// ensureChannel reconnects if there is no open channel.
+// The caller must hold r.mu.
+func (r *RabbitMQ) ensureChannel() error {
+	if r.channel != nil && !r.channel.IsClosed() {
+		return nil
 	}
 
-	logger.Info("connected to rabbitmq",
-		"exchange", cfg.Exchange,
-		"queue", cfg.QueueName,
-		"routing_key", cfg.RoutingKey,
-	)
+	r.closeConn()
+	r.logger.Info("reconnecting to rabbitmq")
+	return r.connect()
+}
 
-	return &RabbitMQ{
-		conn:       conn,
-		channel:    ch,
-		exchange:   cfg.Exchange,
-		routingKey: cfg.RoutingKey,
-		logger:     logger,
-	}, nil
+// Ready reports whether the broker is reachable, reconnecting if needed.
+func (r *RabbitMQ) Ready(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.ensureChannel()
 }
 
 type ArticleMessage struct {
-	Action    string         `json:"action"` // "create" or "update"
-	Article   domain.Article `json:"article"`
-	Timestamp time.Time      `json:"timestamp"`
+	Action    string          `json:"action"` // "create" or "update"
+	Article   domain.Article  `json:"article"`
+	Metadata  MessageMetadata `json:"metadata"`
+	Timestamp time.Time       `json:"timestamp"`
+	// Sequence numbers the messages of a source in publish order, from 1,
+	// so consumers can reorder them and detect gaps. It is only set with
+	// publisher.sequence_numbers.
+	Sequence int64 `json:"sequence,omitempty"`
+}
+
+// MessageTypeSyncCompleted is the type of the SyncCompletedMessage, set both
+// in the body and as the AMQP type property.
+const MessageTypeSyncCompleted = "sync.completed"
+
+// SyncCompletedMessage is a control message announcing that a sync pass of a
+// source finished successfully, e.g. so consumers can trigger a reindex.
+type SyncCompletedMessage struct {
+	Type      string           `json:"type"`
+	Stats     domain.SyncStats `json:"stats"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// projectedMessage has the same shape as ArticleMessage, with the article
+// already projected through the field mask.
+type projectedMessage struct {
+	Action    string          `json:"action"`
+	Article   json.RawMessage `json:"article"`
+	Metadata  MessageMetadata `json:"metadata"`
+	Timestamp time.Time       `json:"timestamp"`
+	Sequence  int64           `json:"sequence,omitempty"`
 }
 
 func (r *RabbitMQ) Publish(ctx context.Context, article *domain.Article, isNew bool) error {
-	action := "update"
+	return r.PublishWithOptions(ctx, article, isNew, PublishOptions{})
+}
+
+// PublishWithOptions publishes like Publish, with opts overriding the
+// configured message TTL and priority.
+func (r *RabbitMQ) PublishWithOptions(ctx context.Context, article *domain.Article, isNew bool, opts PublishOptions) error {
+	msg, err := r.buildMessage(article, isNew, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+	r.applyOptions(&msg, opts)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.ensureChannel(); err != nil {
+		return err
+	}
+
+	for _, routingKey := range r.routingKeys {
+		err = r.channel.PublishWithContext(
+			ctx,
+			r.exchange,
+			routingKey,
+			false,
+			false,
+			msg,
+		)
+		if err != nil {
+			return fmt.Errorf("publish message to %s: %w", routingKey, err)
+		}
+	}
+
+	r.logger.Debug("published article",
+		"external_id", article.ExternalID,
+		"action", actionFor(isNew),
+	)
+
+	return nil
+}
+
+// PublishSyncCompleted announces a completed sync pass with its stats, if
+// SyncCompletedRoutingKey is set. The message is never compressed.
+func (r *RabbitMQ) PublishSyncCompleted(ctx context.Context, stats *domain.SyncStats) error {
+	if r.cfg.SyncCompletedRoutingKey == "" {
+		return nil
+	}
+
+	msg, err := r.buildSyncCompleted(stats, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.ensureChannel(); err != nil {
+		return err
+	}
+
+	err = r.channel.PublishWithContext(ctx, r.exchange, r.cfg.SyncCompletedRoutingKey, false, false, msg)
+	if err != nil {
+		return fmt.Errorf("publish sync completed to %s: %w", r.cfg.SyncCompletedRoutingKey, err)
+	}
+
+	r.logger.Debug("published sync completed", "source", stats.SourceID)
+	return nil
+}
+
+func (r *RabbitMQ) buildSyncCompleted(stats *domain.SyncStats, now time.Time) (amqp.Publishing, error) {
+	body, err := json.Marshal(SyncCompletedMessage{
+		Type:      MessageTypeSyncCompleted,
+		Stats:     *stats,
+		Timestamp: now,
+	})
+	if err != nil {
+		return amqp.Publishing{}, fmt.Errorf("marshal sync completed: %w", err)
+	}
+
+	msg := amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Type:         MessageTypeSyncCompleted,
+		Body:         body,
+		Timestamp:    now,
+	}
+	if r.cfg.SigningSecret != "" {
+		msg.Headers = amqp.Table{SignatureHeader: Sign([]byte(r.cfg.SigningSecret), body)}
+	}
+	return msg, nil
+}
+
+// applyOptions sets the expiration and priority of an article message from
+// opts, falling back to the configured defaults.
+func (r *RabbitMQ) applyOptions(msg *amqp.Publishing, opts PublishOptions) {
+	ttl := r.cfg.MessageTTL
+	if opts.TTL > 0 {
+		ttl = opts.TTL
+	}
+	if ttl > 0 {
+		msg.Expiration = strconv.FormatInt(ttl.Milliseconds(), 10)
+	}
+
+	msg.Priority = r.cfg.Priority
+	if opts.Priority != nil {
+		msg.Priority = *opts.Priority
+	}
+}
+
+func actionFor(isNew bool) string {
 	if isNew {
-		action = "create"
+		return "create"
+	}
+	return "update"
+}
+
+// buildMessage encodes the article in the configured format, compressing the
+// body if it is over the threshold and signing it if a secret is configured.
+func (r *RabbitMQ) buildMessage(article *domain.Article, isNew bool, now time.Time) (amqp.Publishing, error) {
+	msg, err := r.encode(article, isNew, now)
+	if err != nil {
+		return amqp.Publishing{}, err
 	}
 
-	msg := ArticleMessage{
-		Action:    action,
-		Article:   *article,
-		Timestamp: time.Now().UTC(),
+	if r.cfg.CompressThreshold > 0 && len(msg.Body) > r.cfg.CompressThreshold {
+		compressed, err := gzipBytes(msg.Body)
+		if err != nil {
+			return amqp.Publishing{}, fmt.Errorf("compress message: %w", err)
+		}
+		msg.Body = compressed
+		msg.ContentEncoding = "gzip"
 	}
 
-	body, err := json.Marshal(msg)
+	if r.cfg.SigningSecret != "" {
+		if msg.Headers == nil {
+			msg.Headers = amqp.Table{}
+		}
+		msg.Headers[SignatureHeader] = Sign([]byte(r.cfg.SigningSecret), msg.Body)
+	}
+
+	return msg, nil
+}
+
+func (r *RabbitMQ) encode(article *domain.Article, isNew bool, now time.Time) (amqp.Publishing, error) {
+	projected, err := r.mask.apply(article)
 	if err != nil {
-		return fmt.Errorf("marshal message: %w", err)
+		return amqp.Publishing{}, fmt.Errorf("project article: %w", err)
 	}
 
-	err = r.channel.PublishWithContext(
-		ctx,
-		r.exchange,
-		r.routingKey,
-		false,
-		false,
-		amqp.Publishing{
+	if r.format == FormatCloudEvents {
+		event := newCloudEvent(article, isNew, projected, now)
+		body, err := json.Marshal(event)
+		if err != nil {
+			return amqp.Publishing{}, fmt.Errorf("marshal cloudevent: %w", err)
+		}
+		return amqp.Publishing{
 			DeliveryMode: amqp.Persistent,
-			ContentType:  "application/json",
+			ContentType:  cloudEventsContentType,
+			MessageId:    event.ID,
+			Type:         event.Type,
+			Headers:      event.headers(),
 			Body:         body,
-			Timestamp:    time.Now(),
-		},
-	)
-	if err != nil {
-		return fmt.Errorf("publish message: %w", err)
+			Timestamp:    now,
+		}, nil
 	}
 
-	r.logger.Debug("published article",
-		"external_id", article.ExternalID,
-		"action", action,
-	)
+	body, err := json.Marshal(projectedMessage{
+		Action:    actionFor(isNew),
+		Article:   projected,
+		Metadata:  NewMessageMetadata(article),
+		Timestamp: now,
+		Sequence:  article.Sequence,
+	})
+	if err != nil {
+		return amqp.Publishing{}, fmt.Errorf("marshal message: %w", err)
+	}
+	return amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "application/json",
+		Body:         body,
+		Timestamp:    now,
+	}, nil
+}
 
-	return nil
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 func (r *RabbitMQ) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.closeConn()
+}
+
+// closeConn closes the channel and connection, if any.
+// The caller must hold r.mu.
+func (r *RabbitMQ) closeConn() error {
+	var err error
 	if r.channel != nil {
 		r.channel.Close()
+		r.channel = nil
 	}
 	if r.conn != nil {
-		return r.conn.Close()
+		err = r.conn.Close()
+		r.conn = nil
 	}
-	return nil
+	return err
 }
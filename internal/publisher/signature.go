@@ -0,0 +1,20 @@
+package publisher
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignatureHeader is the AMQP header (and webhook HTTP header) carrying the
+// message signature, if a signing secret is configured.
+const SignatureHeader = "X-Signature-256"
+
+// Sign returns "sha256=" followed by the hex HMAC-SHA256 of body keyed with
+// secret. Consumers recompute it over the body bytes as received, before
+// decompressing them, and compare with hmac.Equal.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,153 @@
+// Package webhook publishes article messages as HTTP POST requests.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/publisher"
+)
+
+// Config holds webhook publisher configuration.
+type Config struct {
+	URL string
+	// Secret signs every request body. Empty disables the signature header.
+	Secret         string
+	Timeout        time.Duration
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// Publisher POSTs each article as a publisher.ArticleMessage to a URL.
+type Publisher struct {
+	httpClient     *http.Client
+	url            string
+	secret         []byte
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	logger         *slog.Logger
+}
+
+// New creates a webhook publisher.
+func New(cfg Config, logger *slog.Logger) *Publisher {
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+
+	return &Publisher{
+		httpClient: &http.Client{
+			Timeout: cfg.Timeout,
+		},
+		url:            cfg.URL,
+		secret:         []byte(cfg.Secret),
+		maxAttempts:    maxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+		logger:         logger,
+	}
+}
+
+// errPermanent marks a response that retrying won't fix.
+var errPermanent = errors.New("permanent failure")
+
+func (p *Publisher) Publish(ctx context.Context, article *domain.Article, isNew bool) error {
+	action := "update"
+	if isNew {
+		action = "create"
+	}
+
+	body, err := json.Marshal(publisher.ArticleMessage{
+		Action:    action,
+		Article:   *article,
+		Metadata:  publisher.NewMessageMetadata(article),
+		Timestamp: time.Now().UTC(),
+		Sequence:  article.Sequence,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal message: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = p.post(ctx, body)
+		if err == nil {
+			break
+		}
+		if errors.Is(err, errPermanent) || attempt == p.maxAttempts {
+			return fmt.Errorf("after %d attempts: %w", attempt, err)
+		}
+
+		backoff := p.calculateBackoff(attempt)
+		p.logger.Warn("webhook request failed, retrying",
+			"attempt", attempt,
+			"backoff", backoff,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+
+	p.logger.Debug("published article",
+		"external_id", article.ExternalID,
+		"action", action,
+	)
+
+	return nil
+}
+
+func (p *Publisher) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "NewsFetcher/1.0")
+	if len(p.secret) > 0 {
+		req.Header.Set(publisher.SignatureHeader, publisher.Sign(p.secret, body))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode >= 500, resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: unexpected status: %d", errPermanent, resp.StatusCode)
+	}
+}
+
+func (p *Publisher) calculateBackoff(attempt int) time.Duration {
+	backoff := p.initialBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+	}
+	if p.maxBackoff > 0 && backoff > p.maxBackoff {
+		backoff = p.maxBackoff
+	}
+	return backoff
+}
+
+func (p *Publisher) Close() error {
+	p.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,162 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/publisher"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	logger  *slog.Logger
+	article *domain.Article
+}
+
+func (s *WebhookTestSuite) SetupTest() {
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s.article = &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   123,
+		Title:        "Title",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
+
+// request is what the test server received.
+type request struct {
+	method    string
+	header    http.Header
+	body      []byte
+	signature string
+}
+
+func (s *WebhookTestSuite) serve(statuses ...int) (*httptest.Server, chan request) {
+	requests := make(chan request, 10)
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- request{method: r.Method, header: r.Header, body: body, signature: r.Header.Get(publisher.SignatureHeader)}
+
+		status := http.StatusOK
+		if n := int(calls.Add(1)) - 1; n < len(statuses) {
+			status = statuses[n]
+		}
+		w.WriteHeader(status)
+	}))
+	s.T().Cleanup(server.Close)
+	return server, requests
+}
+
+func (s *WebhookTestSuite) newPublisher(url string, secret string) *Publisher {
+	return New(Config{
+		URL:            url,
+		Secret:         secret,
+		Timeout:        time.Second,
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}, s.logger)
+}
+
+func (s *WebhookTestSuite) TestPublish_PostsSignedMessage() {
+	server, requests := s.serve()
+	pub := s.newPublisher(server.URL, "secret")
+
+	s.Require().NoError(pub.Publish(context.Background(), s.article, true))
+
+	req := <-requests
+	s.Equal(http.MethodPost, req.method)
+	s.Equal("application/json", req.header.Get("Content-Type"))
+
+	var msg publisher.ArticleMessage
+	s.Require().NoError(json.Unmarshal(req.body, &msg))
+	s.Equal("create", msg.Action)
+	s.Equal(*s.article, msg.Article)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(req.body)
+	s.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), req.signature)
+}
+
+func (s *WebhookTestSuite) TestPublish_UpdateAction() {
+	server, requests := s.serve()
+	pub := s.newPublisher(server.URL, "secret")
+
+	s.Require().NoError(pub.Publish(context.Background(), s.article, false))
+
+	var msg publisher.ArticleMessage
+	s.Require().NoError(json.Unmarshal((<-requests).body, &msg))
+	s.Equal("update", msg.Action)
+}
+
+func (s *WebhookTestSuite) TestPublish_Sequence() {
+	server, requests := s.serve()
+	pub := s.newPublisher(server.URL, "")
+	s.article.Sequence = 7
+
+	s.Require().NoError(pub.Publish(context.Background(), s.article, true))
+
+	var msg publisher.ArticleMessage
+	s.Require().NoError(json.Unmarshal((<-requests).body, &msg))
+	s.Equal(int64(7), msg.Sequence)
+}
+
+func (s *WebhookTestSuite) TestPublish_NoSecretNoSignature() {
+	server, requests := s.serve()
+	pub := s.newPublisher(server.URL, "")
+
+	s.Require().NoError(pub.Publish(context.Background(), s.article, true))
+
+	s.Empty((<-requests).signature)
+}
+
+func (s *WebhookTestSuite) TestPublish_RetriesServerErrors() {
+	server, requests := s.serve(http.StatusServiceUnavailable, http.StatusBadGateway)
+	pub := s.newPublisher(server.URL, "secret")
+
+	s.Require().NoError(pub.Publish(context.Background(), s.article, true))
+
+	s.Len(requests, 3)
+}
+
+func (s *WebhookTestSuite) TestPublish_GivesUpAfterMaxAttempts() {
+	server, requests := s.serve(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	pub := s.newPublisher(server.URL, "secret")
+
+	err := pub.Publish(context.Background(), s.article, true)
+
+	s.ErrorContains(err, "after 3 attempts")
+	s.Len(requests, 3)
+}
+
+func (s *WebhookTestSuite) TestPublish_DoesNotRetryClientErrors() {
+	server, requests := s.serve(http.StatusBadRequest)
+	pub := s.newPublisher(server.URL, "secret")
+
+	err := pub.Publish(context.Background(), s.article, true)
+
+	s.ErrorContains(err, "unexpected status: 400")
+	s.Len(requests, 1)
+}
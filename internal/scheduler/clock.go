@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock abstracts the scheduler's and jobs' tickers so they can be driven
+// deterministically in tests.
+type Clock interface {
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker is the subset of time.Ticker the scheduler relies on.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock is a Clock backed by the time package.
+type RealClock struct{}
+
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// ManualClock is a Clock for tests: time only moves when Advance is called.
+type ManualClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*manualWaiter
+}
+
+type manualWaiter struct {
+	clock   *ManualClock
+	next    time.Time
+	period  time.Duration
+	ch      chan time.Time
+	stopped chan struct{}
+	once    sync.Once
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	c := &ManualClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// NewTicker returns a ticker whose ticks are delivered synchronously by Advance.
+func (c *ManualClock) NewTicker(d time.Duration) Ticker {
+	w := &manualWaiter{period: d, ch: make(chan time.Time)}
+	c.add(w, d)
+	return w
+}
+
+func (c *ManualClock) add(w *manualWaiter, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	w.clock = c
+	w.next = c.now.Add(d)
+	w.stopped = make(chan struct{})
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+}
+
+// Advance moves time forward by d, firing every due tick in chronological
+// order. Ticker ticks are handed over synchronously, so Advance
+// blocks until the receiver takes each one or stops the ticker.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	target := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.SliceStable(c.waiters, func(i, j int) bool {
+			return c.waiters[i].next.Before(c.waiters[j].next)
+		})
+		if len(c.waiters) == 0 || c.waiters[0].next.After(target) {
+			c.now = target
+			c.mu.Unlock()
+			return
+		}
+
+		w := c.waiters[0]
+		fireAt := w.next
+		c.now = fireAt
+		w.next = w.next.Add(w.period)
+		c.mu.Unlock()
+
+		select {
+		case w.ch <- fireAt:
+		case <-w.stopped:
+		}
+	}
+}
+
+// BlockUntil waits until at least n tickers are registered.
+func (c *ManualClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *ManualClock) remove(w *manualWaiter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			break
+		}
+	}
+	c.cond.Broadcast()
+}
+
+func (w *manualWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+func (w *manualWaiter) Stop() {
+	w.once.Do(func() {
+		w.clock.remove(w)
+		close(w.stopped)
+	})
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Job runs a background task, such as tag reconciliation, at a fixed
+// interval. Unlike Scheduler, a run that outlasts the interval delays the next
+// tick instead of overlapping with it.
+type Job struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context) error
+	clock    Clock
+	logger   *slog.Logger
+}
+
+func NewJob(name string, interval time.Duration, run func(ctx context.Context) error, clock Clock, logger *slog.Logger) *Job {
+	return &Job{
+		name:     name,
+		interval: interval,
+		run:      run,
+		clock:    clock,
+		logger:   logger.With("job", name),
+	}
+}
+
+// Start runs the job every interval until ctx is cancelled. A failed run is
+// logged and retried on the next tick.
+func (j *Job) Start(ctx context.Context) error {
+	j.logger.Info("job started", "interval", j.interval)
+
+	ticker := j.clock.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("job stopped")
+			return ctx.Err()
+		case <-ticker.C():
+			if err := j.run(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Error("job failed", "error", err)
+			}
+		}
+	}
+}
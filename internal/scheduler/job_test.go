@@ -0,0 +1,82 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type JobTestSuite struct {
+	suite.Suite
+	clock  *ManualClock
+	logger *slog.Logger
+}
+
+func (s *JobTestSuite) SetupTest() {
+	s.clock = NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func TestJobTestSuite(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
+
+func (s *JobTestSuite) start(run func(ctx context.Context) error) (context.CancelFunc, <-chan error) {
+	job := NewJob("test", time.Hour, run, s.clock, s.logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- job.Start(ctx) }()
+
+	s.clock.BlockUntil(1)
+	return cancel, errCh
+}
+
+func (s *JobTestSuite) TestStart_RunsOncePerInterval() {
+	var runs atomic.Int32
+	done := make(chan struct{}, 10)
+	cancel, errCh := s.start(func(ctx context.Context) error {
+		runs.Add(1)
+		done <- struct{}{}
+		return nil
+	})
+
+	s.clock.Advance(30 * time.Minute)
+	s.Equal(int32(0), runs.Load())
+
+	for i := 0; i < 3; i++ {
+		s.clock.Advance(time.Hour)
+		<-done
+	}
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(int32(3), runs.Load())
+}
+
+func (s *JobTestSuite) TestStart_KeepsRunningAfterFailure() {
+	var runs atomic.Int32
+	done := make(chan struct{}, 10)
+	cancel, errCh := s.start(func(ctx context.Context) error {
+		done <- struct{}{}
+		if runs.Add(1) == 1 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	s.clock.Advance(time.Hour)
+	<-done
+	s.clock.Advance(time.Hour)
+	<-done
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(int32(2), runs.Load())
+}
@@ -2,11 +2,16 @@ package scheduler
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"news_fetcher/internal/config"
 	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+	"news_fetcher/internal/service"
 )
 
 // Syncer defines the interface for sync operations.
@@ -17,41 +22,266 @@ type Syncer interface {
 type Scheduler struct {
 	syncer Syncer
 	cfg    config.SyncConfig
+	clock  Clock
 	logger *slog.Logger
+
+	running atomic.Bool
+	wg      sync.WaitGroup
+
+	summaryMu sync.Mutex
+	summary   Summary
+
+	mu              sync.Mutex
+	interval        time.Duration
+	failures        int // consecutive failed syncs, for the backoff
+	intervalChanged chan struct{}
+	tickInterval    atomic.Int64 // interval of the running ticker
 }
 
-func NewScheduler(syncer Syncer, cfg config.SyncConfig, logger *slog.Logger) *Scheduler {
-	return &Scheduler{
+// Summary describes the syncs run by the scheduler so far.
+type Summary struct {
+	// Runs counts the completed syncs, Failures those that returned an error.
+	Runs     int
+	Failures int
+	// LastStats and LastErr are the result of the last completed sync.
+	// LastStats may be nil if it failed before fetching.
+	LastStats *domain.SyncStats
+	LastErr   error
+}
+
+const (
+	// defaultMinInterval is the interval floor used when cfg.MinInterval is unset.
+	defaultMinInterval = 30 * time.Second
+	// defaultMaxFailureBackoff caps the failure backoff when
+	// cfg.MaxFailureBackoff is unset.
+	defaultMaxFailureBackoff = time.Hour
+)
+
+// NewScheduler creates a scheduler syncing every cfg.Interval. An interval
+// below cfg.MinInterval is raised to it, so that a misconfigured one can't
+// make the scheduler hammer the source and the database. After consecutive
+// failed syncs the interval doubles, up to cfg.MaxFailureBackoff, until a sync
+// succeeds.
+func NewScheduler(syncer Syncer, cfg config.SyncConfig, clock Clock, logger *slog.Logger) *Scheduler {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = defaultMinInterval
+	}
+	if cfg.MaxFailureBackoff <= 0 {
+		cfg.MaxFailureBackoff = defaultMaxFailureBackoff
+	}
+
+	s := &Scheduler{
 		syncer: syncer,
 		cfg:    cfg,
+		clock:  clock,
 		logger: logger,
+
+		intervalChanged: make(chan struct{}, 1),
 	}
+	s.interval = s.clamp(cfg.Interval)
+	return s
 }
 
+// Start runs syncs until ctx is cancelled. It returns once the sync in
+// progress, if any, has finished.
 func (s *Scheduler) Start(ctx context.Context) error {
-	s.logger.Info("scheduler started", "interval", s.cfg.Interval)
+	s.logger.Info("scheduler started", "interval", s.Interval())
 
-	s.runSync(ctx)
+	if s.cfg.RunOnStart {
+		s.trySync(ctx)
+	}
 
-	ticker := time.NewTicker(s.cfg.Interval)
-	defer ticker.Stop()
+	ticker := s.newTicker()
+	defer func() { ticker.Stop() }()
 
 	for {
 		select {
 		case <-ctx.Done():
+			s.wg.Wait()
 			s.logger.Info("scheduler stopped")
 			return ctx.Err()
-		case <-ticker.C:
-			s.runSync(ctx)
+		case <-ticker.C():
+			s.trySync(ctx)
+		case <-s.intervalChanged:
+			ticker.Stop()
+			ticker = s.newTicker()
+			s.logger.Info("scheduler interval changed", "interval", time.Duration(s.tickInterval.Load()))
 		}
 	}
 }
 
+// Interval returns the configured tick interval, without the failure backoff.
+func (s *Scheduler) Interval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval
+}
+
+// SetInterval changes the tick interval of a running scheduler. The ticker is
+// replaced on the next loop iteration. Non-positive values are ignored and
+// values below the minimum interval are raised to it.
+func (s *Scheduler) SetInterval(d time.Duration) {
+	if d <= 0 {
+		s.logger.Warn("ignoring invalid scheduler interval", "interval", d)
+		return
+	}
+	d = s.clamp(d)
+
+	s.mu.Lock()
+	s.interval = d
+	s.mu.Unlock()
+
+	s.notifyIntervalChanged()
+}
+
+func (s *Scheduler) notifyIntervalChanged() {
+	select {
+	case s.intervalChanged <- struct{}{}:
+	default:
+	}
+}
+
+// clamp raises d to the minimum interval, logging when it does.
+func (s *Scheduler) clamp(d time.Duration) time.Duration {
+	if d >= s.cfg.MinInterval {
+		return d
+	}
+	s.logger.Warn("scheduler interval below minimum, using minimum",
+		"interval", d,
+		"min_interval", s.cfg.MinInterval,
+	)
+	return s.cfg.MinInterval
+}
+
+// effectiveInterval is the interval, doubled for each consecutive failed sync
+// up to the maximum backoff.
+func (s *Scheduler) effectiveInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return backoffInterval(s.interval, s.failures, s.cfg.MaxFailureBackoff)
+}
+
+func backoffInterval(interval time.Duration, failures int, maxBackoff time.Duration) time.Duration {
+	d := interval
+	for i := 0; i < failures && d < maxBackoff; i++ {
+		d *= 2
+	}
+	if d > maxBackoff {
+		d = max(interval, maxBackoff)
+	}
+	return d
+}
+
+// recordOutcome counts consecutive failures and lengthens or resets the
+// interval accordingly.
+func (s *Scheduler) recordOutcome(failed bool) {
+	s.mu.Lock()
+	if !failed && s.failures == 0 {
+		s.mu.Unlock()
+		return
+	}
+	if failed {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+	failures := s.failures
+	d := backoffInterval(s.interval, failures, s.cfg.MaxFailureBackoff)
+	s.mu.Unlock()
+
+	if failed {
+		s.logger.Warn("backing off after consecutive sync failures",
+			"consecutive_failures", failures,
+			"interval", d,
+		)
+	} else {
+		s.logger.Info("sync succeeded, resetting interval", "interval", d)
+	}
+	s.notifyIntervalChanged()
+}
+
+func (s *Scheduler) newTicker() Ticker {
+	d := s.effectiveInterval()
+	s.tickInterval.Store(int64(d))
+	return s.clock.NewTicker(d)
+}
+
+// Summary returns a summary of the syncs completed so far.
+func (s *Scheduler) Summary() Summary {
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+	return s.summary
+}
+
+// Running reports whether a sync started by the scheduler is in progress.
+func (s *Scheduler) Running() bool {
+	return s.running.Load()
+}
+
+// trySync starts a sync in the background unless the previous one is still
+// running, in which case the tick is skipped rather than queued.
+func (s *Scheduler) trySync(ctx context.Context) {
+	if !s.running.CompareAndSwap(false, true) {
+		s.logger.Warn("sync still running, skipping interval", "interval", s.Interval())
+		metrics.SchedulerSkippedTicks.Inc()
+		return
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.running.Store(false)
+		s.runSync(ctx)
+	}()
+}
+
 func (s *Scheduler) runSync(ctx context.Context) {
 	syncCtx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
 	defer cancel()
 
-	if _, err := s.syncer.Sync(syncCtx); err != nil {
-		s.logger.Error("sync failed", "error", err)
+	stats, err := s.syncer.Sync(syncCtx)
+	if errors.Is(err, service.ErrSyncInProgress) {
+		s.logger.Warn("sync already in progress, skipping interval")
+		metrics.SchedulerSkippedTicks.Inc()
+		return
+	}
+	if errors.Is(err, service.ErrSourcePaused) {
+		// Logged by the service; a paused source isn't a failure.
+		return
+	}
+	if errors.Is(err, ErrNothingDue) {
+		// Nothing ran, so the backoff and summary are left as they are.
+		return
+	}
+	if err != nil {
+		s.logger.Error("sync failed", "stage", failedStage(err), "error", err)
+	}
+	s.recordOutcome(err != nil)
+
+	s.summaryMu.Lock()
+	defer s.summaryMu.Unlock()
+	s.summary.Runs++
+	if err != nil {
+		s.summary.Failures++
+	}
+	s.summary.LastStats = stats
+	s.summary.LastErr = err
+}
+
+// failedStage names the pipeline stage an error from Sync originated in.
+func failedStage(err error) string {
+	var fetchErr *service.FetchError
+	var storeErr *service.StoreError
+	var publishErr *service.PublishError
+
+	switch {
+	case errors.As(err, &fetchErr):
+		return "fetch"
+	case errors.As(err, &storeErr):
+		return "store"
+	case errors.As(err, &publishErr):
+		return "publish"
+	default:
+		return "unknown"
 	}
 }
@@ -0,0 +1,324 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+	"news_fetcher/internal/service"
+)
+
+type fakeSyncer struct {
+	mu      sync.Mutex
+	calls   int
+	done    chan struct{}
+	release chan struct{} // when set, each Sync blocks until it receives from it
+	errs    []error       // returned by successive calls; nil once exhausted
+}
+
+func newFakeSyncer() *fakeSyncer {
+	return &fakeSyncer{done: make(chan struct{}, 100)}
+}
+
+func (f *fakeSyncer) Sync(ctx context.Context) (*domain.SyncStats, error) {
+	f.mu.Lock()
+	f.calls++
+	calls := f.calls
+	var err error
+	if calls <= len(f.errs) {
+		err = f.errs[calls-1]
+	}
+	f.mu.Unlock()
+	f.done <- struct{}{}
+	if f.release != nil {
+		<-f.release
+	}
+	return &domain.SyncStats{Fetched: calls}, err
+}
+
+func (f *fakeSyncer) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	syncer *fakeSyncer
+	clock  *ManualClock
+	sched  *Scheduler
+	logger *slog.Logger
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.syncer = newFakeSyncer()
+	s.clock = NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func (s *SchedulerTestSuite) start(cfg config.SyncConfig) (context.CancelFunc, <-chan error) {
+	s.sched = NewScheduler(s.syncer, cfg, s.clock, s.logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.sched.Start(ctx) }()
+
+	s.clock.BlockUntil(1)
+	return cancel, errCh
+}
+
+// tick advances the clock by d and waits for the sync it triggers to finish.
+func (s *SchedulerTestSuite) tick(d time.Duration) {
+	s.clock.Advance(d)
+	s.waitSyncs(1)
+	s.Eventually(func() bool { return !s.sched.Running() }, time.Second, time.Millisecond)
+}
+
+func (s *SchedulerTestSuite) waitSyncs(n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.syncer.done:
+		case <-time.After(time.Second):
+			s.FailNow("timeout waiting for sync", "got %d of %d", i, n)
+		}
+	}
+}
+
+func (s *SchedulerTestSuite) TestStart_RunOnStart() {
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute, RunOnStart: true})
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(1, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestStart_NoRunOnStart() {
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute, RunOnStart: false})
+	s.Equal(0, s.syncer.Calls())
+
+	s.clock.Advance(time.Minute)
+	s.waitSyncs(1)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(1, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestStart_SyncsOncePerInterval() {
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute})
+
+	for i := 0; i < 10; i++ {
+		s.tick(time.Minute)
+	}
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(10, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestStart_PartialIntervalsDoNotTrigger() {
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute})
+
+	s.tick(90 * time.Second)
+
+	s.clock.Advance(20 * time.Second)
+	s.Equal(1, s.syncer.Calls())
+
+	s.tick(10 * time.Second)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(2, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestStart_SkipsTickWhileSyncRunning() {
+	s.syncer.release = make(chan struct{})
+	skippedBefore := testutil.ToFloat64(metrics.SchedulerSkippedTicks)
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Hour})
+
+	s.clock.Advance(time.Minute)
+	s.waitSyncs(1)
+	s.True(s.sched.Running())
+
+	s.clock.Advance(3 * time.Minute)
+	s.Eventually(func() bool {
+		return testutil.ToFloat64(metrics.SchedulerSkippedTicks) == skippedBefore+3
+	}, time.Second, time.Millisecond)
+	s.Equal(1, s.syncer.Calls())
+
+	s.syncer.release <- struct{}{}
+	s.Eventually(func() bool { return !s.sched.Running() }, time.Second, time.Millisecond)
+
+	s.clock.Advance(time.Minute)
+	s.waitSyncs(1)
+	s.syncer.release <- struct{}{}
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(2, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestStart_WaitsForRunningSync() {
+	s.syncer.release = make(chan struct{})
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Hour})
+
+	s.clock.Advance(time.Minute)
+	s.waitSyncs(1)
+	cancel()
+
+	select {
+	case <-errCh:
+		s.FailNow("returned before the running sync finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	s.syncer.release <- struct{}{}
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(1, s.sched.Summary().Runs)
+}
+
+func (s *SchedulerTestSuite) TestSummary() {
+	cause := &service.FetchError{Err: errors.New("boom")}
+	s.syncer.errs = []error{nil, cause}
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute})
+	s.Equal(Summary{}, s.sched.Summary())
+
+	s.tick(time.Minute)
+	s.Equal(Summary{Runs: 1, LastStats: &domain.SyncStats{Fetched: 1}}, s.sched.Summary())
+
+	s.tick(time.Minute)
+	s.Equal(Summary{Runs: 2, Failures: 1, LastStats: &domain.SyncStats{Fetched: 2}, LastErr: cause}, s.sched.Summary())
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+}
+
+func (s *SchedulerTestSuite) TestSummary_PausedSourceIsNotAFailure() {
+	s.syncer.errs = []error{service.ErrSourcePaused}
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute})
+
+	s.tick(time.Minute)
+	s.Equal(Summary{}, s.sched.Summary())
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+}
+
+// waitTickInterval waits until the running ticker has interval d.
+func (s *SchedulerTestSuite) waitTickInterval(d time.Duration) {
+	s.Eventually(func() bool {
+		return time.Duration(s.sched.tickInterval.Load()) == d
+	}, time.Second, time.Millisecond)
+	s.clock.BlockUntil(1)
+}
+
+func (s *SchedulerTestSuite) TestStart_BacksOffAfterFailures() {
+	cause := &service.FetchError{Err: errors.New("boom")}
+	s.syncer.errs = []error{cause, cause, cause, nil}
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute, MaxFailureBackoff: 5 * time.Minute})
+
+	s.tick(time.Minute)
+	s.waitTickInterval(2 * time.Minute)
+
+	s.clock.Advance(time.Minute)
+	s.Equal(1, s.syncer.Calls())
+	s.tick(time.Minute)
+	s.waitTickInterval(4 * time.Minute)
+
+	s.clock.Advance(3 * time.Minute)
+	s.Equal(2, s.syncer.Calls())
+	s.tick(time.Minute)
+	// Capped at MaxFailureBackoff.
+	s.waitTickInterval(5 * time.Minute)
+
+	s.clock.Advance(4 * time.Minute)
+	s.Equal(3, s.syncer.Calls())
+	s.tick(time.Minute)
+	// The success resets the interval.
+	s.waitTickInterval(time.Minute)
+
+	s.tick(time.Minute)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(5, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestBackoffInterval() {
+	s.Equal(time.Minute, backoffInterval(time.Minute, 0, time.Hour))
+	s.Equal(8*time.Minute, backoffInterval(time.Minute, 3, time.Hour))
+	s.Equal(time.Hour, backoffInterval(time.Minute, 10, time.Hour))
+	s.Equal(time.Hour, backoffInterval(time.Minute, 1000, time.Hour))
+	// A cap below the interval disables the backoff.
+	s.Equal(time.Minute, backoffInterval(time.Minute, 3, time.Second))
+}
+
+func (s *SchedulerTestSuite) TestSetInterval_ChangesCadence() {
+	cancel, errCh := s.start(config.SyncConfig{Interval: time.Minute, Timeout: time.Minute})
+
+	s.tick(time.Minute)
+	s.tick(time.Minute)
+
+	s.sched.SetInterval(5 * time.Minute)
+	s.Eventually(func() bool {
+		return time.Duration(s.sched.tickInterval.Load()) == 5*time.Minute
+	}, time.Second, time.Millisecond)
+
+	s.clock.Advance(4 * time.Minute)
+	s.Equal(2, s.syncer.Calls())
+
+	s.tick(time.Minute)
+	s.tick(5 * time.Minute)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(4, s.syncer.Calls())
+}
+
+func (s *SchedulerTestSuite) TestSetInterval_IgnoresNonPositive() {
+	sched := NewScheduler(s.syncer, config.SyncConfig{Interval: time.Minute}, s.clock, s.logger)
+
+	sched.SetInterval(0)
+	s.Equal(time.Minute, sched.Interval())
+
+	sched.SetInterval(-time.Second)
+	s.Equal(time.Minute, sched.Interval())
+
+	sched.SetInterval(2 * time.Minute)
+	s.Equal(2*time.Minute, sched.Interval())
+}
+
+func (s *SchedulerTestSuite) TestFailedStage() {
+	cause := errors.New("boom")
+
+	s.Equal("fetch", failedStage(&service.FetchError{Err: cause}))
+	s.Equal("store", failedStage(fmt.Errorf("wrapped: %w", &service.StoreError{Op: "save article", Err: cause})))
+	s.Equal("publish", failedStage(&service.PublishError{Err: cause}))
+	s.Equal("unknown", failedStage(cause))
+}
+
+func (s *SchedulerTestSuite) TestNewScheduler_ClampsToMinInterval() {
+	sched := NewScheduler(s.syncer, config.SyncConfig{Interval: time.Millisecond}, s.clock, s.logger)
+	s.Equal(defaultMinInterval, sched.Interval())
+
+	sched = NewScheduler(s.syncer, config.SyncConfig{Interval: time.Millisecond, MinInterval: time.Second}, s.clock, s.logger)
+	s.Equal(time.Second, sched.Interval())
+
+	sched.SetInterval(time.Millisecond)
+	s.Equal(time.Second, sched.Interval())
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+)
+
+// ErrNothingDue is returned by Weighted.Sync on a tick no source is due on.
+// The scheduler skips such a tick, as it neither succeeded nor failed.
+var ErrNothingDue = errors.New("no source due on this tick")
+
+// WeightedSource is a source a Weighted syncer syncs every Every ticks.
+type WeightedSource struct {
+	ID     string
+	Syncer Syncer
+	// Every is how many ticks apart the source is synced: 1 syncs it on every
+	// tick, 3 on every third one. Zero or less counts as 1.
+	Every int
+}
+
+// Weighted is a Syncer for the scheduler that syncs several sources at
+// different rates, so a high-value feed can be synced more often than a
+// low-value one on the same interval. Each call to Sync is a tick: a source
+// with Every n is synced on ticks 0, n, 2n and so on, counted from the first
+// call, so every source is synced on the first tick. Which sources a tick
+// syncs only depends on how many ticks came before it.
+type Weighted struct {
+	sources []WeightedSource
+
+	mu   sync.Mutex
+	tick int
+}
+
+// NewWeighted creates a Weighted syncer for the sources, synced in the given
+// order on the ticks they are due.
+func NewWeighted(sources ...WeightedSource) *Weighted {
+	return &Weighted{sources: sources}
+}
+
+func (src WeightedSource) due(tick int) bool {
+	return src.Every <= 1 || tick%src.Every == 0
+}
+
+// Sync syncs the sources due on this tick, one after the other, and returns
+// their stats added up. Errors are returned joined, each prefixed with its
+// source. A source that is paused or already syncing is left out; only if
+// that's true of every due source is its error returned, so the scheduler
+// treats the tick accordingly. A tick no source is due on returns
+// ErrNothingDue.
+func (w *Weighted) Sync(ctx context.Context) (*domain.SyncStats, error) {
+	w.mu.Lock()
+	tick := w.tick
+	w.tick++
+	w.mu.Unlock()
+
+	var total *domain.SyncStats
+	var errs []error
+	var skipErr error
+	due := false
+	for _, src := range w.sources {
+		if !src.due(tick) {
+			continue
+		}
+		due = true
+		stats, err := src.Syncer.Sync(ctx)
+		if errors.Is(err, service.ErrSourcePaused) || errors.Is(err, service.ErrSyncInProgress) {
+			if skipErr == nil {
+				skipErr = err
+			}
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("source %s: %w", src.ID, err))
+		}
+		total = addStats(total, stats)
+	}
+	if !due {
+		return nil, ErrNothingDue
+	}
+	if total == nil && len(errs) == 0 && skipErr != nil {
+		return nil, skipErr
+	}
+	return total, errors.Join(errs...)
+}
+
+// addStats adds stats to total, returning total. A nil total starts with a
+// copy of stats; a total of several sources has no SourceID.
+func addStats(total, stats *domain.SyncStats) *domain.SyncStats {
+	if stats == nil {
+		return total
+	}
+	if total == nil {
+		sum := *stats
+		return &sum
+	}
+	if total.SourceID != stats.SourceID {
+		total.SourceID = ""
+	}
+	total.Fetched += stats.Fetched
+	total.New += stats.New
+	total.Updated += stats.Updated
+	total.Skipped += stats.Skipped
+	total.Errors += stats.Errors
+	total.Published += stats.Published
+	total.Suppressed += stats.Suppressed
+	total.Deferred += stats.Deferred
+	total.Duration += stats.Duration
+	total.Quarantined += stats.Quarantined
+	total.TagErrors += stats.TagErrors
+	total.FetchDuration += stats.FetchDuration
+	total.PersistDuration += stats.PersistDuration
+	total.PublishDuration += stats.PublishDuration
+	total.PublishDeferred += stats.PublishDeferred
+	return total
+}
@@ -0,0 +1,213 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
+)
+
+type syncFunc func(ctx context.Context) (*domain.SyncStats, error)
+
+func (f syncFunc) Sync(ctx context.Context) (*domain.SyncStats, error) {
+	return f(ctx)
+}
+
+type WeightedTestSuite struct {
+	suite.Suite
+}
+
+func TestWeightedTestSuite(t *testing.T) {
+	suite.Run(t, new(WeightedTestSuite))
+}
+
+// recorder returns a source appending its ID to synced whenever it's synced.
+func recorder(id string, every int, synced *[]string) WeightedSource {
+	return WeightedSource{ID: id, Every: every, Syncer: syncFunc(func(context.Context) (*domain.SyncStats, error) {
+		*synced = append(*synced, id)
+		return &domain.SyncStats{SourceID: id, Fetched: 1}, nil
+	})}
+}
+
+func (s *WeightedTestSuite) TestSync_DueSources() {
+	var synced []string
+	w := NewWeighted(recorder("a", 1, &synced), recorder("b", 2, &synced), recorder("c", 3, &synced))
+
+	var ticks [][]string
+	for i := 0; i < 6; i++ {
+		synced = nil
+		_, err := w.Sync(context.Background())
+		s.Require().NoError(err)
+		ticks = append(ticks, synced)
+	}
+
+	s.Equal([][]string{
+		{"a", "b", "c"},
+		{"a"},
+		{"a", "b"},
+		{"a", "c"},
+		{"a", "b"},
+		{"a"},
+	}, ticks)
+}
+
+func (s *WeightedTestSuite) TestSync_NothingDue() {
+	var synced []string
+	w := NewWeighted(recorder("a", 3, &synced))
+
+	_, err := w.Sync(context.Background())
+	s.Require().NoError(err)
+	stats, err := w.Sync(context.Background())
+
+	s.ErrorIs(err, ErrNothingDue)
+	s.Nil(stats)
+	s.Equal([]string{"a"}, synced)
+}
+
+func (s *WeightedTestSuite) TestSync_ZeroEveryIsEveryTick() {
+	var synced []string
+	w := NewWeighted(recorder("a", 0, &synced))
+
+	for i := 0; i < 3; i++ {
+		_, err := w.Sync(context.Background())
+		s.Require().NoError(err)
+	}
+
+	s.Equal([]string{"a", "a", "a"}, synced)
+}
+
+func (s *WeightedTestSuite) TestSync_AddsUpStats() {
+	var synced []string
+	w := NewWeighted(recorder("a", 1, &synced), recorder("b", 1, &synced))
+
+	stats, err := w.Sync(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal(&domain.SyncStats{Fetched: 2}, stats)
+}
+
+func (s *WeightedTestSuite) TestSync_SingleSourceKeepsStats() {
+	var synced []string
+	w := NewWeighted(recorder("a", 1, &synced), recorder("b", 2, &synced))
+	_, err := w.Sync(context.Background())
+	s.Require().NoError(err)
+
+	stats, err := w.Sync(context.Background())
+
+	s.Require().NoError(err)
+	s.Equal(&domain.SyncStats{SourceID: "a", Fetched: 1}, stats)
+}
+
+func (s *WeightedTestSuite) TestSync_ErrorDoesNotStopOtherSources() {
+	var synced []string
+	failing := WeightedSource{ID: "a", Syncer: syncFunc(func(context.Context) (*domain.SyncStats, error) {
+		return nil, &service.FetchError{Err: errors.New("unexpected status: 502")}
+	})}
+	w := NewWeighted(failing, recorder("b", 1, &synced))
+
+	stats, err := w.Sync(context.Background())
+
+	s.ErrorContains(err, "source a: ")
+	s.ErrorContains(err, "unexpected status: 502")
+	s.Equal("fetch", failedStage(err))
+	s.Equal([]string{"b"}, synced)
+	s.Equal(1, stats.Fetched)
+}
+
+func (s *WeightedTestSuite) TestSync_PausedSourceSkipped() {
+	var synced []string
+	paused := WeightedSource{ID: "a", Syncer: syncFunc(func(context.Context) (*domain.SyncStats, error) {
+		return nil, service.ErrSourcePaused
+	})}
+
+	stats, err := NewWeighted(paused, recorder("b", 1, &synced)).Sync(context.Background())
+	s.Require().NoError(err)
+	s.Equal("b", stats.SourceID)
+
+	stats, err = NewWeighted(paused).Sync(context.Background())
+	s.ErrorIs(err, service.ErrSourcePaused, "a tick syncing only paused sources is reported as paused")
+	s.Nil(stats)
+}
+
+func (s *WeightedTestSuite) TestScheduler_IdleTicksKeepBackoff() {
+	cause := &service.FetchError{Err: errors.New("unexpected status: 502")}
+	failing := newFakeSyncer()
+	failing.errs = []error{cause, cause}
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	weighted := NewWeighted(WeightedSource{ID: "a", Syncer: failing, Every: 3})
+	sched := NewScheduler(weighted, config.SyncConfig{Interval: time.Minute, Timeout: time.Minute, MaxFailureBackoff: time.Hour}, clock, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- sched.Start(ctx) }()
+	clock.BlockUntil(1)
+
+	// The first tick syncs the source, which fails and backs off.
+	clock.Advance(time.Minute)
+	<-failing.done
+	s.Eventually(func() bool { return time.Duration(sched.tickInterval.Load()) == 2*time.Minute }, time.Second, time.Millisecond)
+	clock.BlockUntil(1)
+
+	// The next two ticks have nothing due and leave the backoff alone.
+	for tick := 2; tick <= 3; tick++ {
+		clock.Advance(2 * time.Minute)
+		s.Eventually(func() bool {
+			weighted.mu.Lock()
+			defer weighted.mu.Unlock()
+			return weighted.tick == tick && !sched.Running()
+		}, time.Second, time.Millisecond)
+	}
+	s.Equal(2*time.Minute, time.Duration(sched.tickInterval.Load()))
+	s.Equal(Summary{Runs: 1, Failures: 1, LastStats: &domain.SyncStats{Fetched: 1}, LastErr: errors.Join(fmt.Errorf("source a: %w", cause))}, sched.Summary())
+
+	// Due again, the source fails a second time and the backoff grows.
+	clock.Advance(2 * time.Minute)
+	<-failing.done
+	s.Eventually(func() bool { return time.Duration(sched.tickInterval.Load()) == 4*time.Minute }, time.Second, time.Millisecond)
+	s.Equal(2, sched.Summary().Failures)
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(2, failing.Calls())
+}
+
+func (s *WeightedTestSuite) TestScheduler_RelativeCounts() {
+	high, low := newFakeSyncer(), newFakeSyncer()
+	clock := NewManualClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	sched := NewScheduler(NewWeighted(
+		WeightedSource{ID: "high", Syncer: high, Every: 1},
+		WeightedSource{ID: "low", Syncer: low, Every: 3},
+	), config.SyncConfig{Interval: time.Minute, Timeout: time.Minute}, clock, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- sched.Start(ctx) }()
+	clock.BlockUntil(1)
+
+	for i := 0; i < 12; i++ {
+		clock.Advance(time.Minute)
+		select {
+		case <-high.done:
+		case <-time.After(time.Second):
+			s.FailNow("timeout waiting for sync", "tick %d", i)
+		}
+		s.Eventually(func() bool { return !sched.Running() }, time.Second, time.Millisecond)
+	}
+
+	cancel()
+	s.ErrorIs(<-errCh, context.Canceled)
+	s.Equal(12, high.Calls())
+	s.Equal(4, low.Calls())
+	s.Equal(12, sched.Summary().Runs)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSyncInProgress is returned by Sync when another sync of the same source is running.
+var ErrSyncInProgress = errors.New("sync already in progress")
+
+// ErrSourcePaused is returned by Sync while the source is paused.
+var ErrSourcePaused = errors.New("source is paused")
+
+// FetchError reports a failure to fetch articles from the source.
+type FetchError struct {
+	SourceID string
+	Err      error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch articles: %v", e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// StoreError reports a failure to read or write the database. Op names the
+// failed operation, e.g. "filter for sync" or "update sync state".
+type StoreError struct {
+	Op  string
+	Err error
+}
+
+func (e *StoreError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Op, e.Err)
+}
+
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+// PublishError reports a failure to publish an article to the broker.
+type PublishError struct {
+	ExternalID int64
+	Err        error
+}
+
+func (e *PublishError) Error() string {
+	return fmt.Sprintf("publish article %d: %v", e.ExternalID, e.Err)
+}
+
+func (e *PublishError) Unwrap() error {
+	return e.Err
+}
+
+// EnrichError reports a failure of an enricher on an article.
+type EnrichError struct {
+	ExternalID int64
+	Err        error
+}
+
+func (e *EnrichError) Error() string {
+	return fmt.Sprintf("enrich article %d: %v", e.ExternalID, e.Err)
+}
+
+func (e *EnrichError) Unwrap() error {
+	return e.Err
+}
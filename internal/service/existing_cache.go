@@ -0,0 +1,230 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"news_fetcher/internal/domain"
+)
+
+// CachedArticleStore is an ArticleStore that remembers the stored versions
+// GetExisting returned, so frequent syncs of a mostly static feed don't look
+// up the same articles every time. It keeps at most size articles, each for
+// at most ttl, evicting the least recently used first. Only articles found
+// are cached; new ones are always looked up.
+//
+// Upsert and SetPublishPending drop the entry of the article they change, and
+// DeleteOlderThan the entries of the source. Writes that bypass the store,
+// e.g. by another process, are only seen once the entry expires. It is safe
+// for concurrent use.
+type CachedArticleStore struct {
+	ArticleStore
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	order   *list.List // of *cacheEntry, most recently used first
+	entries map[cacheKey]*list.Element
+	byID    map[int64]cacheKey // stored article ID to its entry
+}
+
+type cacheKey struct {
+	sourceID string
+	key      string
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	existing  domain.ExistingArticle
+	expiresAt time.Time
+}
+
+// NewCachedArticleStore puts a cache of size articles, kept for ttl, in front
+// of store.
+func NewCachedArticleStore(store ArticleStore, size int, ttl time.Duration) *CachedArticleStore {
+	return &CachedArticleStore{
+		ArticleStore: store,
+		size:         size,
+		ttl:          ttl,
+		now:          time.Now,
+		order:        list.New(),
+		entries:      make(map[cacheKey]*list.Element),
+		byID:         make(map[int64]cacheKey),
+	}
+}
+
+// GetExisting returns the cached stored versions and looks up the rest.
+func (c *CachedArticleStore) GetExisting(ctx context.Context, sourceID string, keys []string) (map[string]domain.ExistingArticle, error) {
+	result, missing := c.lookup(sourceID, keys, time.Time{})
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.ArticleStore.GetExisting(ctx, sourceID, missing)
+	if err != nil {
+		return nil, err
+	}
+	c.add(sourceID, found, result)
+	return result, nil
+}
+
+// GetExistingSince returns the cached stored versions last modified at or
+// after since and looks up the rest with that bound.
+func (c *CachedArticleStore) GetExistingSince(ctx context.Context, sourceID string, keys []string, since time.Time) (map[string]domain.ExistingArticle, error) {
+	result, missing := c.lookup(sourceID, keys, since)
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	found, err := c.ArticleStore.GetExistingSince(ctx, sourceID, missing, since)
+	if err != nil {
+		return nil, err
+	}
+	c.add(sourceID, found, result)
+	return result, nil
+}
+
+// Upsert stores the article and drops its cached version, whether or not the
+// upsert succeeded.
+func (c *CachedArticleStore) Upsert(ctx context.Context, article *domain.Article) (int64, error) {
+	c.invalidate(cacheKey{article.SourceID, article.Key()})
+	id, err := c.ArticleStore.Upsert(ctx, article)
+	// Dropped again, in case a lookup cached the old version meanwhile.
+	c.invalidate(cacheKey{article.SourceID, article.Key()})
+	return id, err
+}
+
+// SetPublishPending records the flag and drops the cached version of the
+// article.
+func (c *CachedArticleStore) SetPublishPending(ctx context.Context, id int64, pending bool) error {
+	c.invalidateID(id)
+	err := c.ArticleStore.SetPublishPending(ctx, id, pending)
+	c.invalidateID(id)
+	return err
+}
+
+// DeleteOlderThan deletes a source's articles published before cutoff, if
+// the store it wraps is a RetentionStore, and drops the source's cached
+// articles, as the ones deleted are new again to the next sync.
+func (c *CachedArticleStore) DeleteOlderThan(ctx context.Context, sourceID string, cutoff time.Time) (int64, error) {
+	store, ok := c.ArticleStore.(RetentionStore)
+	if !ok {
+		return 0, errors.New("article store doesn't delete articles")
+	}
+	n, err := store.DeleteOlderThan(ctx, sourceID, cutoff)
+	// Dropped after, so versions a lookup cached meanwhile go too.
+	c.invalidateSource(sourceID)
+	return n, err
+}
+
+// Analyze refreshes the planner statistics through the store it wraps, if
+// it is a RetentionStore.
+func (c *CachedArticleStore) Analyze(ctx context.Context, vacuum bool) error {
+	store, ok := c.ArticleStore.(RetentionStore)
+	if !ok {
+		return errors.New("article store doesn't analyze articles")
+	}
+	return store.Analyze(ctx, vacuum)
+}
+
+// lookup returns the cached, unexpired versions among keys that were last
+// modified at or after since, and the keys it has none for.
+func (c *CachedArticleStore) lookup(sourceID string, keys []string, since time.Time) (map[string]domain.ExistingArticle, []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	result := make(map[string]domain.ExistingArticle)
+	var missing []string
+	for _, key := range keys {
+		el, ok := c.entries[cacheKey{sourceID, key}]
+		if !ok {
+			missing = append(missing, key)
+			continue
+		}
+		entry := el.Value.(*cacheEntry)
+		if !now.Before(entry.expiresAt) {
+			c.remove(el)
+			missing = append(missing, key)
+			continue
+		}
+		if entry.existing.LastModified.Before(since) {
+			missing = append(missing, key)
+			continue
+		}
+		c.order.MoveToFront(el)
+		result[key] = entry.existing
+	}
+	return result, missing
+}
+
+// add caches the versions found and adds them to result.
+func (c *CachedArticleStore) add(sourceID string, found, result map[string]domain.ExistingArticle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(c.ttl)
+	for k, existing := range found {
+		result[k] = existing
+
+		key := cacheKey{sourceID, k}
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+		c.entries[key] = c.order.PushFront(&cacheEntry{key: key, existing: existing, expiresAt: expiresAt})
+		c.byID[existing.ID] = key
+	}
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *CachedArticleStore) invalidate(key cacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *CachedArticleStore) invalidateID(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key, ok := c.byID[id]; ok {
+		if el, ok := c.entries[key]; ok {
+			c.remove(el)
+		}
+	}
+}
+
+func (c *CachedArticleStore) invalidateSource(sourceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, el := range c.entries {
+		if key.sourceID == sourceID {
+			c.remove(el)
+		}
+	}
+}
+
+// remove drops an entry. The caller must hold c.mu.
+func (c *CachedArticleStore) remove(el *list.Element) {
+	entry := c.order.Remove(el).(*cacheEntry)
+	delete(c.entries, entry.key)
+	delete(c.byID, entry.existing.ID)
+}
+
+// Len returns how many articles are cached, including expired ones not yet
+// evicted.
+func (c *CachedArticleStore) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
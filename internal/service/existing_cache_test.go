@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service/mocks"
+)
+
+type CachedArticleStoreTestSuite struct {
+	suite.Suite
+	ctrl  *gomock.Controller
+	store *mocks.MockArticleStore
+	cache *CachedArticleStore
+	now   time.Time
+}
+
+func (s *CachedArticleStoreTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.store = mocks.NewMockArticleStore(s.ctrl)
+	s.now = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	s.cache = NewCachedArticleStore(s.store, 3, time.Minute)
+	s.cache.now = func() time.Time { return s.now }
+}
+
+func TestCachedArticleStoreTestSuite(t *testing.T) {
+	suite.Run(t, new(CachedArticleStoreTestSuite))
+}
+
+func (s *CachedArticleStoreTestSuite) existing(id int64) domain.ExistingArticle {
+	return domain.ExistingArticle{ID: id * 10, LastModified: s.now.Add(-time.Duration(id) * time.Hour), ContentHash: "hash"}
+}
+
+func (s *CachedArticleStoreTestSuite) TestHitsAvoidTheStore() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1", "2", "3"}).Return(
+		map[string]domain.ExistingArticle{"1": s.existing(1), "2": s.existing(2)}, nil,
+	)
+	// Only the article not found before is looked up again.
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil)
+
+	first, err := s.cache.GetExisting(ctx, "ecb", []string{"1", "2", "3"})
+	s.Require().NoError(err)
+	second, err := s.cache.GetExisting(ctx, "ecb", []string{"1", "2", "3"})
+	s.Require().NoError(err)
+
+	s.Equal(map[string]domain.ExistingArticle{"1": s.existing(1), "2": s.existing(2)}, first)
+	s.Equal(first, second)
+}
+
+func (s *CachedArticleStoreTestSuite) TestAllHitsSkipTheStore() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil).Times(1)
+
+	for range 3 {
+		got, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+		s.Require().NoError(err)
+		s.Equal(s.existing(1), got["1"])
+	}
+}
+
+func (s *CachedArticleStoreTestSuite) TestSourcesAreCachedSeparately() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil)
+	s.store.EXPECT().GetExisting(ctx, "other", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	got, err := s.cache.GetExisting(ctx, "other", []string{"1"})
+	s.Require().NoError(err)
+	s.Empty(got)
+}
+
+func (s *CachedArticleStoreTestSuite) TestUpsertInvalidates() {
+	ctx := context.Background()
+	article := &domain.Article{SourceID: "ecb", ExternalID: 1}
+	updated := s.existing(1)
+	updated.LastModified = s.now
+
+	gomock.InOrder(
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil),
+		s.store.EXPECT().Upsert(ctx, article).Return(int64(10), nil),
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": updated}, nil),
+	)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	_, err = s.cache.Upsert(ctx, article)
+	s.Require().NoError(err)
+	got, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+
+	s.Equal(updated, got["1"])
+}
+
+func (s *CachedArticleStoreTestSuite) TestFailedUpsertInvalidates() {
+	ctx := context.Background()
+	article := &domain.Article{SourceID: "ecb", ExternalID: 1}
+
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil).Times(2)
+	s.store.EXPECT().Upsert(ctx, article).Return(int64(0), errors.New("deadlock detected"))
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	_, err = s.cache.Upsert(ctx, article)
+	s.Error(err)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+}
+
+func (s *CachedArticleStoreTestSuite) TestSetPublishPendingInvalidates() {
+	ctx := context.Background()
+	pending := s.existing(1)
+	pending.PublishPending = true
+
+	gomock.InOrder(
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil),
+		s.store.EXPECT().SetPublishPending(ctx, int64(10), true).Return(nil),
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": pending}, nil),
+	)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	s.Require().NoError(s.cache.SetPublishPending(ctx, 10, true))
+	got, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+
+	s.True(got["1"].PublishPending)
+}
+
+func (s *CachedArticleStoreTestSuite) TestEntriesExpire() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil).Times(2)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	s.now = s.now.Add(59 * time.Second)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	s.now = s.now.Add(time.Second)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+}
+
+func (s *CachedArticleStoreTestSuite) TestEvictsLeastRecentlyUsed() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1", "2", "3"}).Return(
+		map[string]domain.ExistingArticle{"1": s.existing(1), "2": s.existing(2), "3": s.existing(3)}, nil,
+	)
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"4"}).Return(map[string]domain.ExistingArticle{"4": s.existing(4)}, nil)
+	// 2 was used least recently, so it made room for 4.
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"2"}).Return(map[string]domain.ExistingArticle{"2": s.existing(2)}, nil)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1", "2", "3"})
+	s.Require().NoError(err)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"3"})
+	s.Require().NoError(err)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"4"})
+	s.Require().NoError(err)
+	s.Equal(3, s.cache.Len())
+
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"1", "3", "4"})
+	s.Require().NoError(err)
+	_, err = s.cache.GetExisting(ctx, "ecb", []string{"2"})
+	s.Require().NoError(err)
+}
+
+func (s *CachedArticleStoreTestSuite) TestGetExistingSince() {
+	ctx := context.Background()
+	since := s.now.Add(-90 * time.Minute)
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1", "2"}).Return(
+		map[string]domain.ExistingArticle{"1": s.existing(1), "2": s.existing(2)}, nil,
+	)
+	// 2 was last modified before since, so the cached version doesn't count.
+	s.store.EXPECT().GetExistingSince(ctx, "ecb", []string{"2", "3"}, since).Return(map[string]domain.ExistingArticle{}, nil)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1", "2"})
+	s.Require().NoError(err)
+	got, err := s.cache.GetExistingSince(ctx, "ecb", []string{"1", "2", "3"}, since)
+	s.Require().NoError(err)
+
+	s.Equal(map[string]domain.ExistingArticle{"1": s.existing(1)}, got)
+}
+
+func (s *CachedArticleStoreTestSuite) TestStoreErrorIsNotCached() {
+	ctx := context.Background()
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(nil, errors.New("connection refused"))
+	s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Error(err)
+	got, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	s.Equal(s.existing(1), got["1"])
+}
+
+// retentionArticleStore is an article store that also deletes old articles.
+type retentionArticleStore struct {
+	*mocks.MockArticleStore
+	*mocks.MockRetentionStore
+}
+
+func (s *CachedArticleStoreTestSuite) TestDeleteOlderThanInvalidatesSource() {
+	ctx := context.Background()
+	cutoff := s.now.Add(-24 * time.Hour)
+	retention := mocks.NewMockRetentionStore(s.ctrl)
+	s.cache = NewCachedArticleStore(retentionArticleStore{s.store, retention}, 3, time.Minute)
+	s.cache.now = func() time.Time { return s.now }
+
+	gomock.InOrder(
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil),
+		s.store.EXPECT().GetExisting(ctx, "other", []string{"1"}).Return(map[string]domain.ExistingArticle{"1": s.existing(1)}, nil),
+		retention.EXPECT().DeleteOlderThan(ctx, "ecb", cutoff).Return(int64(1), nil),
+		// The deleted article is looked up again, and found to be new.
+		s.store.EXPECT().GetExisting(ctx, "ecb", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil),
+	)
+
+	_, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	_, err = s.cache.GetExisting(ctx, "other", []string{"1"})
+	s.Require().NoError(err)
+
+	deleted, err := s.cache.DeleteOlderThan(ctx, "ecb", cutoff)
+	s.Require().NoError(err)
+	s.Equal(int64(1), deleted)
+
+	got, err := s.cache.GetExisting(ctx, "ecb", []string{"1"})
+	s.Require().NoError(err)
+	s.Empty(got)
+	// Other sources stay cached.
+	got, err = s.cache.GetExisting(ctx, "other", []string{"1"})
+	s.Require().NoError(err)
+	s.Equal(s.existing(1), got["1"])
+}
+
+func (s *CachedArticleStoreTestSuite) TestDeleteOlderThanWithoutRetentionStore() {
+	_, err := s.cache.DeleteOlderThan(context.Background(), "ecb", s.now)
+	s.Error(err)
+}
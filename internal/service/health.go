@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+)
+
+// HealthTracker aggregates the outcome of every source's syncs. It is safe for
+// concurrent use.
+type HealthTracker struct {
+	store  HealthStore
+	logger *slog.Logger
+
+	mu     sync.RWMutex
+	health map[string]domain.SourceHealth
+}
+
+// NewHealthTracker creates a tracker. With a nil store the health is kept in
+// memory only.
+func NewHealthTracker(store HealthStore, logger *slog.Logger) *HealthTracker {
+	return &HealthTracker{
+		store:  store,
+		logger: logger,
+		health: make(map[string]domain.SourceHealth),
+	}
+}
+
+// Restore loads the health saved by a previous process.
+func (t *HealthTracker) Restore(ctx context.Context) error {
+	if t.store == nil {
+		return nil
+	}
+
+	saved, err := t.store.List(ctx)
+	if err != nil {
+		return &StoreError{Op: "list source health", Err: err}
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, h := range saved {
+		t.health[h.SourceID] = h
+		observeHealth(h)
+	}
+	return nil
+}
+
+// Record adds the outcome of a sync of sourceID that took duration and failed
+// with err, or succeeded if err is nil. Failing to persist it is only logged.
+func (t *HealthTracker) Record(ctx context.Context, sourceID string, duration time.Duration, err error) {
+	now := time.Now()
+
+	t.mu.Lock()
+	h := t.health[sourceID]
+	h.SourceID = sourceID
+	h.Runs++
+	h.AverageDuration += (duration - h.AverageDuration) / time.Duration(h.Runs)
+	if err != nil {
+		h.LastFailureAt = &now
+		h.ConsecutiveFailures++
+		h.LastError = err.Error()
+	} else {
+		h.LastSuccessAt = &now
+		h.ConsecutiveFailures = 0
+	}
+	t.health[sourceID] = h
+	t.mu.Unlock()
+
+	observeHealth(h)
+
+	if t.store == nil {
+		return
+	}
+	// Recorded after interrupted syncs too, so don't inherit their cancellation.
+	if err := t.store.Save(context.WithoutCancel(ctx), &h); err != nil {
+		t.logger.Warn("failed to save source health", "source", sourceID, "error", err)
+	}
+}
+
+// RecordCheck adds the outcome of a health check of sourceID that failed
+// with err, or passed if err is nil. It leaves the sync counts alone. It is
+// only persisted when the outcome changes, so frequent checks don't each
+// write to the store; the LastCheckAt saved is that of the last change.
+// Failing to persist it is only logged.
+func (t *HealthTracker) RecordCheck(ctx context.Context, sourceID string, err error) {
+	now := time.Now()
+
+	t.mu.Lock()
+	h := t.health[sourceID]
+	prevCheckAt, prevCheckError := h.LastCheckAt, h.LastCheckError
+	h.SourceID = sourceID
+	h.LastCheckAt = &now
+	h.LastCheckError = ""
+	if err != nil {
+		h.LastCheckError = err.Error()
+	}
+	t.health[sourceID] = h
+	t.mu.Unlock()
+
+	if t.store == nil || (prevCheckAt != nil && prevCheckError == h.LastCheckError) {
+		return
+	}
+	if err := t.store.Save(context.WithoutCancel(ctx), &h); err != nil {
+		t.logger.Warn("failed to save source health", "source", sourceID, "error", err)
+	}
+}
+
+// Get returns the health of a source and whether any sync of it was recorded.
+func (t *HealthTracker) Get(sourceID string) (domain.SourceHealth, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	h, ok := t.health[sourceID]
+	return h, ok
+}
+
+// All returns the health of every source with recorded syncs, ordered by
+// source.
+func (t *HealthTracker) All() []domain.SourceHealth {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	all := make([]domain.SourceHealth, 0, len(t.health))
+	for _, h := range t.health {
+		all = append(all, h)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].SourceID < all[j].SourceID })
+	return all
+}
+
+func observeHealth(h domain.SourceHealth) {
+	metrics.SourceConsecutiveFailures.WithLabelValues(h.SourceID).Set(float64(h.ConsecutiveFailures))
+	metrics.SourceAverageSyncDuration.WithLabelValues(h.SourceID).Set(h.AverageDuration.Seconds())
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+	"news_fetcher/internal/service/mocks"
+)
+
+type HealthTrackerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	store   *mocks.MockHealthStore
+	tracker *HealthTracker
+}
+
+func (s *HealthTrackerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.store = mocks.NewMockHealthStore(s.ctrl)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.tracker = NewHealthTracker(s.store, logger)
+}
+
+func (s *HealthTrackerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestHealthTrackerTestSuite(t *testing.T) {
+	suite.Run(t, new(HealthTrackerTestSuite))
+}
+
+func (s *HealthTrackerTestSuite) TestRecord_SuccessAndFailures() {
+	ctx := context.Background()
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(4)
+
+	s.tracker.Record(ctx, "ecb", 2*time.Second, nil)
+	s.tracker.Record(ctx, "ecb", 4*time.Second, errors.New("timeout"))
+	s.tracker.Record(ctx, "ecb", 6*time.Second, errors.New("bad gateway"))
+
+	h, ok := s.tracker.Get("ecb")
+	s.Require().True(ok)
+	s.Equal(3, h.Runs)
+	s.Equal(2, h.ConsecutiveFailures)
+	s.Equal("bad gateway", h.LastError)
+	s.Equal(4*time.Second, h.AverageDuration)
+	s.Require().NotNil(h.LastSuccessAt)
+	s.Require().NotNil(h.LastFailureAt)
+	s.False(h.LastFailureAt.Before(*h.LastSuccessAt))
+	s.Equal(2.0, testutil.ToFloat64(metrics.SourceConsecutiveFailures.WithLabelValues("ecb")))
+	s.Equal(4.0, testutil.ToFloat64(metrics.SourceAverageSyncDuration.WithLabelValues("ecb")))
+
+	s.tracker.Record(ctx, "ecb", 8*time.Second, nil)
+
+	h, _ = s.tracker.Get("ecb")
+	s.Equal(4, h.Runs)
+	s.Zero(h.ConsecutiveFailures)
+	s.Equal(5*time.Second, h.AverageDuration)
+	s.False(h.LastSuccessAt.Before(*h.LastFailureAt))
+	// The last error is kept for reference after a success.
+	s.Equal("bad gateway", h.LastError)
+	s.Zero(testutil.ToFloat64(metrics.SourceConsecutiveFailures.WithLabelValues("ecb")))
+}
+
+func (s *HealthTrackerTestSuite) TestRecord_SavesHealth() {
+	ctx := context.Background()
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, h *domain.SourceHealth) error {
+			s.Equal("ecb", h.SourceID)
+			s.Equal(1, h.ConsecutiveFailures)
+			return errors.New("db down")
+		},
+	)
+
+	// A failed save is only logged.
+	s.tracker.Record(ctx, "ecb", time.Second, errors.New("timeout"))
+
+	h, ok := s.tracker.Get("ecb")
+	s.True(ok)
+	s.Equal(1, h.Runs)
+}
+
+func (s *HealthTrackerTestSuite) TestRecordCheck_KeepsSyncCounts() {
+	ctx := context.Background()
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).Times(3)
+
+	s.tracker.Record(ctx, "ecb", time.Second, errors.New("timeout"))
+	s.tracker.RecordCheck(ctx, "ecb", errors.New("connection refused"))
+
+	h, _ := s.tracker.Get("ecb")
+	s.Equal(1, h.Runs)
+	s.Equal(1, h.ConsecutiveFailures)
+	s.Equal("timeout", h.LastError)
+	s.Require().NotNil(h.LastCheckAt)
+	s.Equal("connection refused", h.LastCheckError)
+
+	s.tracker.RecordCheck(ctx, "ecb", nil)
+
+	h, _ = s.tracker.Get("ecb")
+	s.Empty(h.LastCheckError)
+}
+
+func (s *HealthTrackerTestSuite) TestRecordCheck_SavesOnlyChanges() {
+	ctx := context.Background()
+	var saved []string
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, h *domain.SourceHealth) error {
+			saved = append(saved, h.LastCheckError)
+			return nil
+		},
+	).Times(3)
+
+	s.tracker.RecordCheck(ctx, "ecb", nil)
+	s.tracker.RecordCheck(ctx, "ecb", nil)
+	s.tracker.RecordCheck(ctx, "ecb", errors.New("connection refused"))
+	s.tracker.RecordCheck(ctx, "ecb", errors.New("connection refused"))
+	s.tracker.RecordCheck(ctx, "ecb", nil)
+
+	s.Equal([]string{"", "connection refused", ""}, saved)
+	// The last check is still tracked, whether saved or not.
+	first, _ := s.tracker.Get("ecb")
+	s.tracker.RecordCheck(ctx, "ecb", nil)
+	last, _ := s.tracker.Get("ecb")
+	s.NotSame(first.LastCheckAt, last.LastCheckAt)
+}
+
+func (s *HealthTrackerTestSuite) TestAll_OrderedBySource() {
+	ctx := context.Background()
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	s.tracker.Record(ctx, "other", time.Second, nil)
+	s.tracker.Record(ctx, "ecb", time.Second, nil)
+
+	all := s.tracker.All()
+	s.Require().Len(all, 2)
+	s.Equal("ecb", all[0].SourceID)
+	s.Equal("other", all[1].SourceID)
+
+	_, ok := s.tracker.Get("unknown")
+	s.False(ok)
+}
+
+func (s *HealthTrackerTestSuite) TestRestore() {
+	ctx := context.Background()
+	s.store.EXPECT().List(ctx).Return([]domain.SourceHealth{
+		{SourceID: "ecb", Runs: 10, ConsecutiveFailures: 3, AverageDuration: 2 * time.Second},
+	}, nil)
+	s.store.EXPECT().Save(gomock.Any(), gomock.Any()).Return(nil)
+
+	s.Require().NoError(s.tracker.Restore(ctx))
+	s.tracker.Record(ctx, "ecb", 13*time.Second, errors.New("timeout"))
+
+	h, _ := s.tracker.Get("ecb")
+	s.Equal(11, h.Runs)
+	s.Equal(4, h.ConsecutiveFailures)
+	s.Equal(3*time.Second, h.AverageDuration)
+}
+
+func (s *HealthTrackerTestSuite) TestRestore_ListError() {
+	s.store.EXPECT().List(gomock.Any()).Return(nil, errors.New("db down"))
+
+	var storeErr *StoreError
+	s.ErrorAs(s.tracker.Restore(context.Background()), &storeErr)
+}
+
+func (s *HealthTrackerTestSuite) TestRecord_InMemoryOnly() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	tracker := NewHealthTracker(nil, logger)
+
+	s.NoError(tracker.Restore(context.Background()))
+	tracker.Record(context.Background(), "ecb", time.Second, nil)
+
+	h, ok := tracker.Get("ecb")
+	s.True(ok)
+	s.Equal(1, h.Runs)
+}
@@ -11,23 +11,109 @@ import (
 
 type ArticleStore interface {
 	Upsert(ctx context.Context, article *domain.Article) (int64, error)
-	GetExistingBySourceAndExternalIDs(ctx context.Context, sourceID string, ids []int64) (map[int64]time.Time, error)
+	// GetExisting returns the stored articles of a source among the given
+	// keys (see domain.Article.Key), keyed by key.
+	GetExisting(ctx context.Context, sourceID string, keys []string) (map[string]domain.ExistingArticle, error)
+	// GetExistingSince is GetExisting limited to the stored articles last
+	// modified at or after since.
+	GetExistingSince(ctx context.Context, sourceID string, keys []string, since time.Time) (map[string]domain.ExistingArticle, error)
+	// SetPublishPending records whether the stored version of an article
+	// still has to be published.
+	SetPublishPending(ctx context.Context, id int64, pending bool) error
+	// ListPublishPending returns the stored articles of a source, with their
+	// tags, whose last update still has to be published.
+	ListPublishPending(ctx context.Context, sourceID string) ([]domain.Article, error)
+	// GetByID returns a stored article with its tags, or domain.ErrNotFound.
+	GetByID(ctx context.Context, id int64) (*domain.Article, error)
 }
 
 type TagStore interface {
+	// UpsertBatch stores the tags. A store that keys tags by label rather
+	// than by the ID the source assigned sets each tag's ID to its stored one.
 	UpsertBatch(ctx context.Context, tags []domain.Tag) error
 	LinkToArticle(ctx context.Context, articleID int64, tagIDs []int64) error
 }
 
+// TagMismatchStore finds articles whose tag links differ from the tags
+// recorded with them, e.g. because linking failed.
+type TagMismatchStore interface {
+	// ListTagMismatches returns up to limit such articles with an id greater
+	// than afterID, ordered by id.
+	ListTagMismatches(ctx context.Context, afterID int64, limit int) ([]domain.Article, error)
+}
+
+// RetentionStore deletes articles that fell out of their retention window.
+type RetentionStore interface {
+	// DeleteOlderThan deletes a source's articles published before cutoff and
+	// returns how many it deleted.
+	DeleteOlderThan(ctx context.Context, sourceID string, cutoff time.Time) (int64, error)
+	// Analyze refreshes the planner statistics of the article tables, with
+	// vacuum set by running VACUUM ANALYZE.
+	Analyze(ctx context.Context, vacuum bool) error
+}
+
 type SyncStateStore interface {
 	Get(ctx context.Context, sourceID string) (*domain.SyncState, error)
 	Update(ctx context.Context, state *domain.SyncState) error
+	// TouchLastSynced sets only the last sync time of a source.
+	TouchLastSynced(ctx context.Context, sourceID string, t time.Time) error
+}
+
+type RawPayloadStore interface {
+	// Save stores the article's Raw payload, replacing any previous one.
+	Save(ctx context.Context, article *domain.Article) error
+}
+
+// FailureStore tracks articles whose save keeps failing, so they can be
+// quarantined instead of being retried every sync.
+type FailureStore interface {
+	// Failing returns the keys of a source's articles with recorded
+	// failures, mapped to whether they are quarantined.
+	Failing(ctx context.Context, sourceID string) (map[string]bool, error)
+	// RecordFailure counts a failed save and quarantines the article once it
+	// has failed maxAttempts times. It reports whether it is quarantined.
+	RecordFailure(ctx context.Context, article *domain.Article, cause error, maxAttempts int) (bool, error)
+	// Clear forgets the failures of the article with the given key.
+	Clear(ctx context.Context, sourceID, key string) error
+}
+
+// PauseStore persists which sources are paused, so a pause survives a restart.
+type PauseStore interface {
+	SetPaused(ctx context.Context, sourceID string, paused bool) error
+	IsPaused(ctx context.Context, sourceID string) (bool, error)
+}
+
+// HealthStore persists the health of each source, so it survives a restart.
+type HealthStore interface {
+	Save(ctx context.Context, health *domain.SourceHealth) error
+	List(ctx context.Context) ([]domain.SourceHealth, error)
+}
+
+// SequenceStore hands out the publish sequence numbers of each source.
+type SequenceStore interface {
+	// Next returns the next sequence number of a source, starting at 1.
+	Next(ctx context.Context, sourceID string) (int64, error)
 }
 
 type Source interface {
 	ID() string
 	Name() string
-	FetchArticles(ctx context.Context, maxPages int) ([]domain.Article, error)
+	// FetchArticles fetches up to maxPages pages. A non-zero modifiedSince is a
+	// hint that only articles modified after it are needed.
+	FetchArticles(ctx context.Context, maxPages int, modifiedSince time.Time) ([]domain.Article, error)
+}
+
+// HealthChecker is implemented by sources that can cheaply check their
+// upstream is reachable, without a full fetch. Sources that can't are assumed
+// healthy.
+type HealthChecker interface {
+	Healthcheck(ctx context.Context) error
+}
+
+// Enricher adds information to an article after the source has transformed it
+// and before it is persisted, e.g. a reading time or a classification.
+type Enricher interface {
+	Enrich(ctx context.Context, article *domain.Article) error
 }
 
 type TransactionManager interface {
@@ -38,3 +124,15 @@ type Publisher interface {
 	Publish(ctx context.Context, article *domain.Article, isNew bool) error
 	Close() error
 }
+
+// Discarder is implemented by publishers that drop every article instead of
+// publishing it. What they "publish" isn't counted in SyncStats.Published.
+type Discarder interface {
+	Discards() bool
+}
+
+// SyncCompletedPublisher is implemented by publishers that announce each
+// successful sync pass.
+type SyncCompletedPublisher interface {
+	PublishSyncCompleted(ctx context.Context, stats *domain.SyncStats) error
+}
@@ -42,19 +42,78 @@ func (m *MockArticleStore) EXPECT() *MockArticleStoreMockRecorder {
 	return m.recorder
 }
 
-// GetExistingBySourceAndExternalIDs mocks base method.
-func (m *MockArticleStore) GetExistingBySourceAndExternalIDs(ctx context.Context, sourceID string, ids []int64) (map[int64]time.Time, error) {
+// GetByID mocks base method.
+func (m *MockArticleStore) GetByID(ctx context.Context, id int64) (*domain.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExistingBySourceAndExternalIDs", ctx, sourceID, ids)
-	ret0, _ := ret[0].(map[int64]time.Time)
+	ret := m.ctrl.Call(m, "GetByID", ctx, id)
+	ret0, _ := ret[0].(*domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetExistingBySourceAndExternalIDs indicates an expected call of GetExistingBySourceAndExternalIDs.
-func (mr *MockArticleStoreMockRecorder) GetExistingBySourceAndExternalIDs(ctx, sourceID, ids any) *gomock.Call {
+// GetByID indicates an expected call of GetByID.
+func (mr *MockArticleStoreMockRecorder) GetByID(ctx, id any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExistingBySourceAndExternalIDs", reflect.TypeOf((*MockArticleStore)(nil).GetExistingBySourceAndExternalIDs), ctx, sourceID, ids)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockArticleStore)(nil).GetByID), ctx, id)
+}
+
+// GetExisting mocks base method.
+func (m *MockArticleStore) GetExisting(ctx context.Context, sourceID string, keys []string) (map[string]domain.ExistingArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExisting", ctx, sourceID, keys)
+	ret0, _ := ret[0].(map[string]domain.ExistingArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExisting indicates an expected call of GetExisting.
+func (mr *MockArticleStoreMockRecorder) GetExisting(ctx, sourceID, keys any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExisting", reflect.TypeOf((*MockArticleStore)(nil).GetExisting), ctx, sourceID, keys)
+}
+
+// GetExistingSince mocks base method.
+func (m *MockArticleStore) GetExistingSince(ctx context.Context, sourceID string, keys []string, since time.Time) (map[string]domain.ExistingArticle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExistingSince", ctx, sourceID, keys, since)
+	ret0, _ := ret[0].(map[string]domain.ExistingArticle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExistingSince indicates an expected call of GetExistingSince.
+func (mr *MockArticleStoreMockRecorder) GetExistingSince(ctx, sourceID, keys, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExistingSince", reflect.TypeOf((*MockArticleStore)(nil).GetExistingSince), ctx, sourceID, keys, since)
+}
+
+// ListPublishPending mocks base method.
+func (m *MockArticleStore) ListPublishPending(ctx context.Context, sourceID string) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublishPending", ctx, sourceID)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPublishPending indicates an expected call of ListPublishPending.
+func (mr *MockArticleStoreMockRecorder) ListPublishPending(ctx, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublishPending", reflect.TypeOf((*MockArticleStore)(nil).ListPublishPending), ctx, sourceID)
+}
+
+// SetPublishPending mocks base method.
+func (m *MockArticleStore) SetPublishPending(ctx context.Context, id int64, pending bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPublishPending", ctx, id, pending)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPublishPending indicates an expected call of SetPublishPending.
+func (mr *MockArticleStoreMockRecorder) SetPublishPending(ctx, id, pending any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPublishPending", reflect.TypeOf((*MockArticleStore)(nil).SetPublishPending), ctx, id, pending)
 }
 
 // Upsert mocks base method.
@@ -124,6 +183,98 @@ func (mr *MockTagStoreMockRecorder) UpsertBatch(ctx, tags any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertBatch", reflect.TypeOf((*MockTagStore)(nil).UpsertBatch), ctx, tags)
 }
 
+// MockTagMismatchStore is a mock of TagMismatchStore interface.
+type MockTagMismatchStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTagMismatchStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockTagMismatchStoreMockRecorder is the mock recorder for MockTagMismatchStore.
+type MockTagMismatchStoreMockRecorder struct {
+	mock *MockTagMismatchStore
+}
+
+// NewMockTagMismatchStore creates a new mock instance.
+func NewMockTagMismatchStore(ctrl *gomock.Controller) *MockTagMismatchStore {
+	mock := &MockTagMismatchStore{ctrl: ctrl}
+	mock.recorder = &MockTagMismatchStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTagMismatchStore) EXPECT() *MockTagMismatchStoreMockRecorder {
+	return m.recorder
+}
+
+// ListTagMismatches mocks base method.
+func (m *MockTagMismatchStore) ListTagMismatches(ctx context.Context, afterID int64, limit int) ([]domain.Article, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTagMismatches", ctx, afterID, limit)
+	ret0, _ := ret[0].([]domain.Article)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTagMismatches indicates an expected call of ListTagMismatches.
+func (mr *MockTagMismatchStoreMockRecorder) ListTagMismatches(ctx, afterID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTagMismatches", reflect.TypeOf((*MockTagMismatchStore)(nil).ListTagMismatches), ctx, afterID, limit)
+}
+
+// MockRetentionStore is a mock of RetentionStore interface.
+type MockRetentionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRetentionStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRetentionStoreMockRecorder is the mock recorder for MockRetentionStore.
+type MockRetentionStoreMockRecorder struct {
+	mock *MockRetentionStore
+}
+
+// NewMockRetentionStore creates a new mock instance.
+func NewMockRetentionStore(ctrl *gomock.Controller) *MockRetentionStore {
+	mock := &MockRetentionStore{ctrl: ctrl}
+	mock.recorder = &MockRetentionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRetentionStore) EXPECT() *MockRetentionStoreMockRecorder {
+	return m.recorder
+}
+
+// Analyze mocks base method.
+func (m *MockRetentionStore) Analyze(ctx context.Context, vacuum bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Analyze", ctx, vacuum)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Analyze indicates an expected call of Analyze.
+func (mr *MockRetentionStoreMockRecorder) Analyze(ctx, vacuum any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Analyze", reflect.TypeOf((*MockRetentionStore)(nil).Analyze), ctx, vacuum)
+}
+
+// DeleteOlderThan mocks base method.
+func (m *MockRetentionStore) DeleteOlderThan(ctx context.Context, sourceID string, cutoff time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOlderThan", ctx, sourceID, cutoff)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOlderThan indicates an expected call of DeleteOlderThan.
+func (mr *MockRetentionStoreMockRecorder) DeleteOlderThan(ctx, sourceID, cutoff any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOlderThan", reflect.TypeOf((*MockRetentionStore)(nil).DeleteOlderThan), ctx, sourceID, cutoff)
+}
+
 // MockSyncStateStore is a mock of SyncStateStore interface.
 type MockSyncStateStore struct {
 	ctrl     *gomock.Controller
@@ -163,6 +314,20 @@ func (mr *MockSyncStateStoreMockRecorder) Get(ctx, sourceID any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockSyncStateStore)(nil).Get), ctx, sourceID)
 }
 
+// TouchLastSynced mocks base method.
+func (m *MockSyncStateStore) TouchLastSynced(ctx context.Context, sourceID string, t time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchLastSynced", ctx, sourceID, t)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchLastSynced indicates an expected call of TouchLastSynced.
+func (mr *MockSyncStateStoreMockRecorder) TouchLastSynced(ctx, sourceID, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchLastSynced", reflect.TypeOf((*MockSyncStateStore)(nil).TouchLastSynced), ctx, sourceID, t)
+}
+
 // Update mocks base method.
 func (m *MockSyncStateStore) Update(ctx context.Context, state *domain.SyncState) error {
 	m.ctrl.T.Helper()
@@ -177,6 +342,257 @@ func (mr *MockSyncStateStoreMockRecorder) Update(ctx, state any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockSyncStateStore)(nil).Update), ctx, state)
 }
 
+// MockRawPayloadStore is a mock of RawPayloadStore interface.
+type MockRawPayloadStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockRawPayloadStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockRawPayloadStoreMockRecorder is the mock recorder for MockRawPayloadStore.
+type MockRawPayloadStoreMockRecorder struct {
+	mock *MockRawPayloadStore
+}
+
+// NewMockRawPayloadStore creates a new mock instance.
+func NewMockRawPayloadStore(ctrl *gomock.Controller) *MockRawPayloadStore {
+	mock := &MockRawPayloadStore{ctrl: ctrl}
+	mock.recorder = &MockRawPayloadStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRawPayloadStore) EXPECT() *MockRawPayloadStoreMockRecorder {
+	return m.recorder
+}
+
+// Save mocks base method.
+func (m *MockRawPayloadStore) Save(ctx context.Context, article *domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, article)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockRawPayloadStoreMockRecorder) Save(ctx, article any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockRawPayloadStore)(nil).Save), ctx, article)
+}
+
+// MockFailureStore is a mock of FailureStore interface.
+type MockFailureStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailureStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockFailureStoreMockRecorder is the mock recorder for MockFailureStore.
+type MockFailureStoreMockRecorder struct {
+	mock *MockFailureStore
+}
+
+// NewMockFailureStore creates a new mock instance.
+func NewMockFailureStore(ctrl *gomock.Controller) *MockFailureStore {
+	mock := &MockFailureStore{ctrl: ctrl}
+	mock.recorder = &MockFailureStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailureStore) EXPECT() *MockFailureStoreMockRecorder {
+	return m.recorder
+}
+
+// Clear mocks base method.
+func (m *MockFailureStore) Clear(ctx context.Context, sourceID, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Clear", ctx, sourceID, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Clear indicates an expected call of Clear.
+func (mr *MockFailureStoreMockRecorder) Clear(ctx, sourceID, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Clear", reflect.TypeOf((*MockFailureStore)(nil).Clear), ctx, sourceID, key)
+}
+
+// Failing mocks base method.
+func (m *MockFailureStore) Failing(ctx context.Context, sourceID string) (map[string]bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Failing", ctx, sourceID)
+	ret0, _ := ret[0].(map[string]bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Failing indicates an expected call of Failing.
+func (mr *MockFailureStoreMockRecorder) Failing(ctx, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Failing", reflect.TypeOf((*MockFailureStore)(nil).Failing), ctx, sourceID)
+}
+
+// RecordFailure mocks base method.
+func (m *MockFailureStore) RecordFailure(ctx context.Context, article *domain.Article, cause error, maxAttempts int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, article, cause, maxAttempts)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockFailureStoreMockRecorder) RecordFailure(ctx, article, cause, maxAttempts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockFailureStore)(nil).RecordFailure), ctx, article, cause, maxAttempts)
+}
+
+// MockPauseStore is a mock of PauseStore interface.
+type MockPauseStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPauseStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockPauseStoreMockRecorder is the mock recorder for MockPauseStore.
+type MockPauseStoreMockRecorder struct {
+	mock *MockPauseStore
+}
+
+// NewMockPauseStore creates a new mock instance.
+func NewMockPauseStore(ctrl *gomock.Controller) *MockPauseStore {
+	mock := &MockPauseStore{ctrl: ctrl}
+	mock.recorder = &MockPauseStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPauseStore) EXPECT() *MockPauseStoreMockRecorder {
+	return m.recorder
+}
+
+// IsPaused mocks base method.
+func (m *MockPauseStore) IsPaused(ctx context.Context, sourceID string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsPaused", ctx, sourceID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsPaused indicates an expected call of IsPaused.
+func (mr *MockPauseStoreMockRecorder) IsPaused(ctx, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsPaused", reflect.TypeOf((*MockPauseStore)(nil).IsPaused), ctx, sourceID)
+}
+
+// SetPaused mocks base method.
+func (m *MockPauseStore) SetPaused(ctx context.Context, sourceID string, paused bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPaused", ctx, sourceID, paused)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPaused indicates an expected call of SetPaused.
+func (mr *MockPauseStoreMockRecorder) SetPaused(ctx, sourceID, paused any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPaused", reflect.TypeOf((*MockPauseStore)(nil).SetPaused), ctx, sourceID, paused)
+}
+
+// MockHealthStore is a mock of HealthStore interface.
+type MockHealthStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthStoreMockRecorder is the mock recorder for MockHealthStore.
+type MockHealthStoreMockRecorder struct {
+	mock *MockHealthStore
+}
+
+// NewMockHealthStore creates a new mock instance.
+func NewMockHealthStore(ctrl *gomock.Controller) *MockHealthStore {
+	mock := &MockHealthStore{ctrl: ctrl}
+	mock.recorder = &MockHealthStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthStore) EXPECT() *MockHealthStoreMockRecorder {
+	return m.recorder
+}
+
+// List mocks base method.
+func (m *MockHealthStore) List(ctx context.Context) ([]domain.SourceHealth, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx)
+	ret0, _ := ret[0].([]domain.SourceHealth)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockHealthStoreMockRecorder) List(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockHealthStore)(nil).List), ctx)
+}
+
+// Save mocks base method.
+func (m *MockHealthStore) Save(ctx context.Context, health *domain.SourceHealth) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, health)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockHealthStoreMockRecorder) Save(ctx, health any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockHealthStore)(nil).Save), ctx, health)
+}
+
+// MockSequenceStore is a mock of SequenceStore interface.
+type MockSequenceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockSequenceStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockSequenceStoreMockRecorder is the mock recorder for MockSequenceStore.
+type MockSequenceStoreMockRecorder struct {
+	mock *MockSequenceStore
+}
+
+// NewMockSequenceStore creates a new mock instance.
+func NewMockSequenceStore(ctrl *gomock.Controller) *MockSequenceStore {
+	mock := &MockSequenceStore{ctrl: ctrl}
+	mock.recorder = &MockSequenceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSequenceStore) EXPECT() *MockSequenceStoreMockRecorder {
+	return m.recorder
+}
+
+// Next mocks base method.
+func (m *MockSequenceStore) Next(ctx context.Context, sourceID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Next", ctx, sourceID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Next indicates an expected call of Next.
+func (mr *MockSequenceStoreMockRecorder) Next(ctx, sourceID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockSequenceStore)(nil).Next), ctx, sourceID)
+}
+
 // MockSource is a mock of Source interface.
 type MockSource struct {
 	ctrl     *gomock.Controller
@@ -202,18 +618,18 @@ func (m *MockSource) EXPECT() *MockSourceMockRecorder {
 }
 
 // FetchArticles mocks base method.
-func (m *MockSource) FetchArticles(ctx context.Context, maxPages int) ([]domain.Article, error) {
+func (m *MockSource) FetchArticles(ctx context.Context, maxPages int, modifiedSince time.Time) ([]domain.Article, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchArticles", ctx, maxPages)
+	ret := m.ctrl.Call(m, "FetchArticles", ctx, maxPages, modifiedSince)
 	ret0, _ := ret[0].([]domain.Article)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FetchArticles indicates an expected call of FetchArticles.
-func (mr *MockSourceMockRecorder) FetchArticles(ctx, maxPages any) *gomock.Call {
+func (mr *MockSourceMockRecorder) FetchArticles(ctx, maxPages, modifiedSince any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchArticles", reflect.TypeOf((*MockSource)(nil).FetchArticles), ctx, maxPages)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchArticles", reflect.TypeOf((*MockSource)(nil).FetchArticles), ctx, maxPages, modifiedSince)
 }
 
 // ID mocks base method.
@@ -244,6 +660,82 @@ func (mr *MockSourceMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockSource)(nil).Name))
 }
 
+// MockHealthChecker is a mock of HealthChecker interface.
+type MockHealthChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockHealthCheckerMockRecorder
+	isgomock struct{}
+}
+
+// MockHealthCheckerMockRecorder is the mock recorder for MockHealthChecker.
+type MockHealthCheckerMockRecorder struct {
+	mock *MockHealthChecker
+}
+
+// NewMockHealthChecker creates a new mock instance.
+func NewMockHealthChecker(ctrl *gomock.Controller) *MockHealthChecker {
+	mock := &MockHealthChecker{ctrl: ctrl}
+	mock.recorder = &MockHealthCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHealthChecker) EXPECT() *MockHealthCheckerMockRecorder {
+	return m.recorder
+}
+
+// Healthcheck mocks base method.
+func (m *MockHealthChecker) Healthcheck(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Healthcheck", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Healthcheck indicates an expected call of Healthcheck.
+func (mr *MockHealthCheckerMockRecorder) Healthcheck(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Healthcheck", reflect.TypeOf((*MockHealthChecker)(nil).Healthcheck), ctx)
+}
+
+// MockEnricher is a mock of Enricher interface.
+type MockEnricher struct {
+	ctrl     *gomock.Controller
+	recorder *MockEnricherMockRecorder
+	isgomock struct{}
+}
+
+// MockEnricherMockRecorder is the mock recorder for MockEnricher.
+type MockEnricherMockRecorder struct {
+	mock *MockEnricher
+}
+
+// NewMockEnricher creates a new mock instance.
+func NewMockEnricher(ctrl *gomock.Controller) *MockEnricher {
+	mock := &MockEnricher{ctrl: ctrl}
+	mock.recorder = &MockEnricherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEnricher) EXPECT() *MockEnricherMockRecorder {
+	return m.recorder
+}
+
+// Enrich mocks base method.
+func (m *MockEnricher) Enrich(ctx context.Context, article *domain.Article) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enrich", ctx, article)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Enrich indicates an expected call of Enrich.
+func (mr *MockEnricherMockRecorder) Enrich(ctx, article any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enrich", reflect.TypeOf((*MockEnricher)(nil).Enrich), ctx, article)
+}
+
 // MockTransactionManager is a mock of TransactionManager interface.
 type MockTransactionManager struct {
 	ctrl     *gomock.Controller
@@ -333,3 +825,79 @@ func (mr *MockPublisherMockRecorder) Publish(ctx, article, isNew any) *gomock.Ca
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockPublisher)(nil).Publish), ctx, article, isNew)
 }
+
+// MockDiscarder is a mock of Discarder interface.
+type MockDiscarder struct {
+	ctrl     *gomock.Controller
+	recorder *MockDiscarderMockRecorder
+	isgomock struct{}
+}
+
+// MockDiscarderMockRecorder is the mock recorder for MockDiscarder.
+type MockDiscarderMockRecorder struct {
+	mock *MockDiscarder
+}
+
+// NewMockDiscarder creates a new mock instance.
+func NewMockDiscarder(ctrl *gomock.Controller) *MockDiscarder {
+	mock := &MockDiscarder{ctrl: ctrl}
+	mock.recorder = &MockDiscarderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDiscarder) EXPECT() *MockDiscarderMockRecorder {
+	return m.recorder
+}
+
+// Discards mocks base method.
+func (m *MockDiscarder) Discards() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Discards")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Discards indicates an expected call of Discards.
+func (mr *MockDiscarderMockRecorder) Discards() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discards", reflect.TypeOf((*MockDiscarder)(nil).Discards))
+}
+
+// MockSyncCompletedPublisher is a mock of SyncCompletedPublisher interface.
+type MockSyncCompletedPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockSyncCompletedPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockSyncCompletedPublisherMockRecorder is the mock recorder for MockSyncCompletedPublisher.
+type MockSyncCompletedPublisherMockRecorder struct {
+	mock *MockSyncCompletedPublisher
+}
+
+// NewMockSyncCompletedPublisher creates a new mock instance.
+func NewMockSyncCompletedPublisher(ctrl *gomock.Controller) *MockSyncCompletedPublisher {
+	mock := &MockSyncCompletedPublisher{ctrl: ctrl}
+	mock.recorder = &MockSyncCompletedPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSyncCompletedPublisher) EXPECT() *MockSyncCompletedPublisherMockRecorder {
+	return m.recorder
+}
+
+// PublishSyncCompleted mocks base method.
+func (m *MockSyncCompletedPublisher) PublishSyncCompleted(ctx context.Context, stats *domain.SyncStats) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishSyncCompleted", ctx, stats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishSyncCompleted indicates an expected call of PublishSyncCompleted.
+func (mr *MockSyncCompletedPublisherMockRecorder) PublishSyncCompleted(ctx, stats any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishSyncCompleted", reflect.TypeOf((*MockSyncCompletedPublisher)(nil).PublishSyncCompleted), ctx, stats)
+}
@@ -0,0 +1,29 @@
+package service
+
+import "news_fetcher/internal/domain"
+
+// PublishFilter decides whether a saved article is published. Articles it
+// rejects are stored but not sent to the broker.
+type PublishFilter func(article *domain.Article) bool
+
+// SuppressTags returns a filter rejecting articles with any of the given tag
+// labels, or nil if there are none.
+func SuppressTags(labels []string) PublishFilter {
+	if len(labels) == 0 {
+		return nil
+	}
+
+	suppressed := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		suppressed[label] = true
+	}
+
+	return func(article *domain.Article) bool {
+		for _, tag := range article.Tags {
+			if suppressed[tag.Label] {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"news_fetcher/internal/domain"
+)
+
+func TestSuppressTags(t *testing.T) {
+	filter := SuppressTags([]string{"Embargoed", "Partner"})
+
+	assert.True(t, filter(&domain.Article{}))
+	assert.True(t, filter(&domain.Article{Tags: []domain.Tag{{ID: 1, Label: "News"}}}))
+	assert.False(t, filter(&domain.Article{Tags: []domain.Tag{{ID: 1, Label: "News"}, {ID: 2, Label: "Partner"}}}))
+	assert.False(t, filter(&domain.Article{Tags: []domain.Tag{{ID: 3, Label: "Embargoed"}}}))
+}
+
+func TestSuppressTags_Empty(t *testing.T) {
+	assert.Nil(t, SuppressTags(nil))
+}
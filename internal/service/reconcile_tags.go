@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"news_fetcher/internal/domain"
+)
+
+// reconcileBatchSize is the number of mismatched articles fetched at a time.
+const reconcileBatchSize = 100
+
+// TagReconciler re-links articles whose article_tags links differ from the tags
+// recorded with them, e.g. after linking failed with tolerate_tag_errors set.
+type TagReconciler struct {
+	articles  TagMismatchStore
+	tags      TagStore
+	txManager TransactionManager
+	logger    *slog.Logger
+}
+
+func NewTagReconciler(articles TagMismatchStore, tags TagStore, txManager TransactionManager, logger *slog.Logger) *TagReconciler {
+	return &TagReconciler{
+		articles:  articles,
+		tags:      tags,
+		txManager: txManager,
+		logger:    logger,
+	}
+}
+
+// Reconcile re-links every mismatched article and returns how many it fixed.
+// An article that fails is logged and left for the next run; Reconcile only
+// returns an error if the mismatches cannot be listed or ctx is cancelled.
+func (r *TagReconciler) Reconcile(ctx context.Context) (int, error) {
+	var fixed, failed int
+	var afterID int64
+	for {
+		articles, err := r.articles.ListTagMismatches(ctx, afterID, reconcileBatchSize)
+		if err != nil {
+			return fixed, &StoreError{Op: "list tag mismatches", Err: err}
+		}
+
+		for i := range articles {
+			if err := ctx.Err(); err != nil {
+				return fixed, err
+			}
+
+			article := &articles[i]
+			if err := r.relink(ctx, article); err != nil {
+				r.logger.Warn("failed to reconcile article tags",
+					"source", article.SourceID,
+					"external_id", article.ExternalID,
+					"error", err,
+				)
+				failed++
+				continue
+			}
+			fixed++
+		}
+
+		if len(articles) < reconcileBatchSize {
+			break
+		}
+		afterID = articles[len(articles)-1].ID
+	}
+
+	r.logger.Info("tag reconciliation completed", "fixed", fixed, "failed", failed)
+	return fixed, nil
+}
+
+func (r *TagReconciler) relink(ctx context.Context, article *domain.Article) error {
+	ctx = domain.WithSourceID(ctx, article.SourceID)
+	return r.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.tags.UpsertBatch(txCtx, article.Tags); err != nil {
+			return fmt.Errorf("upsert tags: %w", err)
+		}
+		if err := r.tags.LinkToArticle(txCtx, article.ID, article.TagIDs()); err != nil {
+			return fmt.Errorf("link tags: %w", err)
+		}
+		return nil
+	})
+}
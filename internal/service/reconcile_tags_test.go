@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service/mocks"
+)
+
+type TagReconcilerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	articles  *mocks.MockTagMismatchStore
+	tags      *mocks.MockTagStore
+	txManager *mocks.MockTransactionManager
+
+	reconciler *TagReconciler
+}
+
+func (s *TagReconcilerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+
+	s.articles = mocks.NewMockTagMismatchStore(s.ctrl)
+	s.tags = mocks.NewMockTagStore(s.ctrl)
+	s.txManager = mocks.NewMockTransactionManager(s.ctrl)
+
+	s.txManager.EXPECT().WithTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).AnyTimes()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.reconciler = NewTagReconciler(s.articles, s.tags, s.txManager, logger)
+}
+
+func (s *TagReconcilerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestTagReconcilerTestSuite(t *testing.T) {
+	suite.Run(t, new(TagReconcilerTestSuite))
+}
+
+func (s *TagReconcilerTestSuite) TestReconcile_RelinksMismatches() {
+	ctx := context.Background()
+	ecbCtx := domain.WithSourceID(ctx, "ecb")
+	tagged := domain.Article{ID: 10, SourceID: "ecb", ExternalID: 1, Tags: []domain.Tag{{ID: 1, Label: "News"}, {ID: 2, Label: "Cricket"}}}
+	untagged := domain.Article{ID: 11, SourceID: "ecb", ExternalID: 2}
+
+	s.articles.EXPECT().ListTagMismatches(ctx, int64(0), reconcileBatchSize).Return([]domain.Article{tagged, untagged}, nil)
+	s.tags.EXPECT().UpsertBatch(ecbCtx, tagged.Tags).Return(nil)
+	s.tags.EXPECT().LinkToArticle(ecbCtx, int64(10), []int64{1, 2}).Return(nil)
+	// Stale links of an article without recorded tags are removed.
+	s.tags.EXPECT().UpsertBatch(ecbCtx, gomock.Len(0)).Return(nil)
+	s.tags.EXPECT().LinkToArticle(ecbCtx, int64(11), []int64{}).Return(nil)
+
+	fixed, err := s.reconciler.Reconcile(ctx)
+
+	s.NoError(err)
+	s.Equal(2, fixed)
+}
+
+func (s *TagReconcilerTestSuite) TestReconcile_PagesThroughBatches() {
+	ctx := context.Background()
+	ecbCtx := domain.WithSourceID(ctx, "ecb")
+	first := make([]domain.Article, reconcileBatchSize)
+	for i := range first {
+		first[i] = domain.Article{ID: int64(i + 1), SourceID: "ecb", ExternalID: int64(i + 1)}
+	}
+	last := domain.Article{ID: 500, SourceID: "ecb", ExternalID: 500}
+
+	gomock.InOrder(
+		s.articles.EXPECT().ListTagMismatches(ctx, int64(0), reconcileBatchSize).Return(first, nil),
+		s.articles.EXPECT().ListTagMismatches(ctx, int64(reconcileBatchSize), reconcileBatchSize).Return([]domain.Article{last}, nil),
+	)
+	s.tags.EXPECT().UpsertBatch(ecbCtx, gomock.Any()).Return(nil).Times(reconcileBatchSize + 1)
+	s.tags.EXPECT().LinkToArticle(ecbCtx, gomock.Any(), gomock.Any()).Return(nil).Times(reconcileBatchSize + 1)
+
+	fixed, err := s.reconciler.Reconcile(ctx)
+
+	s.NoError(err)
+	s.Equal(reconcileBatchSize+1, fixed)
+}
+
+func (s *TagReconcilerTestSuite) TestReconcile_ContinuesAfterFailedArticle() {
+	ctx := context.Background()
+	ecbCtx := domain.WithSourceID(ctx, "ecb")
+	broken := domain.Article{ID: 10, SourceID: "ecb", ExternalID: 1, Tags: []domain.Tag{{ID: 1, Label: "News"}}}
+	ok := domain.Article{ID: 11, SourceID: "ecb", ExternalID: 2, Tags: []domain.Tag{{ID: 2, Label: "Cricket"}}}
+
+	s.articles.EXPECT().ListTagMismatches(ctx, int64(0), reconcileBatchSize).Return([]domain.Article{broken, ok}, nil)
+	s.tags.EXPECT().UpsertBatch(ecbCtx, broken.Tags).Return(nil)
+	s.tags.EXPECT().LinkToArticle(ecbCtx, int64(10), []int64{1}).Return(errors.New("foreign key violation"))
+	s.tags.EXPECT().UpsertBatch(ecbCtx, ok.Tags).Return(nil)
+	s.tags.EXPECT().LinkToArticle(ecbCtx, int64(11), []int64{2}).Return(nil)
+
+	fixed, err := s.reconciler.Reconcile(ctx)
+
+	s.NoError(err)
+	s.Equal(1, fixed)
+}
+
+func (s *TagReconcilerTestSuite) TestReconcile_ListError() {
+	ctx := context.Background()
+	s.articles.EXPECT().ListTagMismatches(ctx, int64(0), reconcileBatchSize).Return(nil, errors.New("connection refused"))
+
+	_, err := s.reconciler.Reconcile(ctx)
+
+	var storeErr *StoreError
+	s.ErrorAs(err, &storeErr)
+}
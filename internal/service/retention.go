@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"time"
+
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+)
+
+// RetentionPruner deletes the articles of each source published longer ago
+// than the source's retention.
+type RetentionPruner struct {
+	store     RetentionStore
+	retention map[string]time.Duration
+	logger    *slog.Logger
+
+	// maintenance is config.MaintenanceAnalyze, config.MaintenanceVacuum or
+	// empty; see SetMaintenance.
+	maintenance      string
+	maintenanceAfter int64
+}
+
+// NewRetentionPruner creates a pruner for the sources in retention, mapped to
+// how long their articles are kept.
+func NewRetentionPruner(store RetentionStore, retention map[string]time.Duration, logger *slog.Logger) *RetentionPruner {
+	return &RetentionPruner{
+		store:     store,
+		retention: retention,
+		logger:    logger,
+	}
+}
+
+// SetMaintenance makes Prune refresh the planner statistics once a run deleted
+// at least after articles: config.MaintenanceAnalyze runs ANALYZE,
+// config.MaintenanceVacuum runs VACUUM ANALYZE. An empty mode disables it.
+func (p *RetentionPruner) SetMaintenance(mode string, after int) {
+	p.maintenance = mode
+	p.maintenanceAfter = int64(after)
+}
+
+// Prune deletes the expired articles of every source and returns how many it
+// deleted. A source that fails doesn't stop the others; the failures are
+// returned together.
+func (p *RetentionPruner) Prune(ctx context.Context) (int64, error) {
+	sources := make([]string, 0, len(p.retention))
+	for sourceID := range p.retention {
+		sources = append(sources, sourceID)
+	}
+	sort.Strings(sources)
+
+	var total int64
+	var errs []error
+	for _, sourceID := range sources {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		cutoff := time.Now().Add(-p.retention[sourceID])
+		deleted, err := p.store.DeleteOlderThan(domain.WithSourceID(ctx, sourceID), sourceID, cutoff)
+		if err != nil {
+			errs = append(errs, &StoreError{Op: "delete expired articles of " + sourceID, Err: err})
+			continue
+		}
+
+		total += deleted
+		metrics.RetentionDeletedArticles.WithLabelValues(sourceID).Add(float64(deleted))
+		p.logger.Info("expired articles deleted",
+			"source", sourceID,
+			"cutoff", cutoff,
+			"deleted", deleted,
+		)
+	}
+
+	if p.maintenance != "" && total > 0 && total >= p.maintenanceAfter {
+		if err := p.maintain(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return total, errors.Join(errs...)
+}
+
+// maintain runs the configured maintenance on its own, outside any
+// transaction, since VACUUM can't run inside one.
+func (p *RetentionPruner) maintain(ctx context.Context) error {
+	vacuum := p.maintenance == config.MaintenanceVacuum
+	start := time.Now()
+	if err := p.store.Analyze(ctx, vacuum); err != nil {
+		return &StoreError{Op: p.maintenance + " articles", Err: err}
+	}
+	p.logger.Info("article tables maintained",
+		"maintenance", p.maintenance,
+		"duration", time.Since(start),
+	)
+	return nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+
+	"news_fetcher/internal/config"
+	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service/mocks"
+)
+
+type RetentionPrunerTestSuite struct {
+	suite.Suite
+	ctrl *gomock.Controller
+
+	store  *mocks.MockRetentionStore
+	logger *slog.Logger
+}
+
+func (s *RetentionPrunerTestSuite) SetupTest() {
+	s.ctrl = gomock.NewController(s.T())
+	s.store = mocks.NewMockRetentionStore(s.ctrl)
+	s.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func (s *RetentionPrunerTestSuite) TearDownTest() {
+	s.ctrl.Finish()
+}
+
+func TestRetentionPrunerTestSuite(t *testing.T) {
+	suite.Run(t, new(RetentionPrunerTestSuite))
+}
+
+// cutoffNear matches a cutoff retention before the time the test runs.
+func cutoffNear(retention time.Duration) gomock.Matcher {
+	want := time.Now().Add(-retention)
+	return gomock.Cond(func(x any) bool {
+		cutoff, ok := x.(time.Time)
+		return ok && cutoff.Sub(want).Abs() < time.Minute
+	})
+}
+
+func (s *RetentionPrunerTestSuite) TestPrune_DeletesPerSourceRetention() {
+	ctx := context.Background()
+	pruner := NewRetentionPruner(s.store, map[string]time.Duration{
+		"ecb":   90 * 24 * time.Hour,
+		"other": 365 * 24 * time.Hour,
+	}, s.logger)
+
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", cutoffNear(90*24*time.Hour)).Return(int64(3), nil)
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "other"), "other", cutoffNear(365*24*time.Hour)).Return(int64(2), nil)
+
+	deleted, err := pruner.Prune(ctx)
+
+	s.NoError(err)
+	s.Equal(int64(5), deleted)
+}
+
+func (s *RetentionPrunerTestSuite) TestPrune_ContinuesAfterFailedSource() {
+	ctx := context.Background()
+	pruner := NewRetentionPruner(s.store, map[string]time.Duration{
+		"ecb":   time.Hour,
+		"other": time.Hour,
+	}, s.logger)
+
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", gomock.Any()).Return(int64(0), errors.New("connection refused"))
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "other"), "other", gomock.Any()).Return(int64(4), nil)
+
+	deleted, err := pruner.Prune(ctx)
+
+	var storeErr *StoreError
+	s.ErrorAs(err, &storeErr)
+	s.ErrorContains(err, "delete expired articles of ecb")
+	s.Equal(int64(4), deleted)
+}
+
+func (s *RetentionPrunerTestSuite) TestPrune_AnalyzesAfterLargeDelete() {
+	ctx := context.Background()
+	pruner := NewRetentionPruner(s.store, map[string]time.Duration{"ecb": time.Hour}, s.logger)
+	pruner.SetMaintenance(config.MaintenanceAnalyze, 100)
+
+	gomock.InOrder(
+		s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", gomock.Any()).Return(int64(100), nil),
+		s.store.EXPECT().Analyze(ctx, false).Return(nil),
+	)
+
+	_, err := pruner.Prune(ctx)
+
+	s.NoError(err)
+}
+
+func (s *RetentionPrunerTestSuite) TestPrune_Vacuums() {
+	ctx := context.Background()
+	pruner := NewRetentionPruner(s.store, map[string]time.Duration{"ecb": time.Hour}, s.logger)
+	pruner.SetMaintenance(config.MaintenanceVacuum, 1)
+
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", gomock.Any()).Return(int64(1), nil)
+	s.store.EXPECT().Analyze(ctx, true).Return(errors.New("lock timeout"))
+
+	deleted, err := pruner.Prune(ctx)
+
+	s.ErrorContains(err, "vacuum articles")
+	s.Equal(int64(1), deleted)
+}
+
+func (s *RetentionPrunerTestSuite) TestPrune_SkipsMaintenanceBelowThreshold() {
+	ctx := context.Background()
+	pruner := NewRetentionPruner(s.store, map[string]time.Duration{"ecb": time.Hour}, s.logger)
+
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", gomock.Any()).Return(int64(5000), nil)
+	deleted, err := pruner.Prune(ctx)
+	s.NoError(err)
+	s.Equal(int64(5000), deleted)
+
+	pruner.SetMaintenance(config.MaintenanceAnalyze, 100)
+	s.store.EXPECT().DeleteOlderThan(domain.WithSourceID(ctx, "ecb"), "ecb", gomock.Any()).Return(int64(99), nil)
+	_, err = pruner.Prune(ctx)
+	s.NoError(err)
+}
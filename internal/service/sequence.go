@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"news_fetcher/internal/domain"
+)
+
+// PublishNumbered publishes an article, numbered by sequences if that is set.
+// The number is taken in a transaction that is only committed once the
+// publish succeeded, so a failed publish doesn't leave a gap consumers would
+// take for a lost message; article.Sequence is reset then. If the commit
+// fails after a successful publish, the number is handed out again.
+func PublishNumbered(ctx context.Context, tx TransactionManager, sequences SequenceStore, pub Publisher, article *domain.Article, isNew bool) error {
+	if sequences == nil {
+		return pub.Publish(ctx, article, isNew)
+	}
+
+	err := tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		seq, err := sequences.Next(txCtx, article.SourceID)
+		if err != nil {
+			return fmt.Errorf("next sequence number: %w", err)
+		}
+		article.Sequence = seq
+		return pub.Publish(ctx, article, isNew)
+	})
+	if err != nil {
+		article.Sequence = 0
+	}
+	return err
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"news_fetcher/internal/config"
 	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
 )
 
 type SyncService struct {
@@ -15,17 +18,38 @@ type SyncService struct {
 	articles  ArticleStore
 	tags      TagStore
 	syncState SyncStateStore
+	rawStore  RawPayloadStore
 	txManager TransactionManager
 	publisher Publisher
 	logger    *slog.Logger
-	config    config.SyncConfig
+
+	mu                sync.RWMutex
+	config            config.SyncConfig
+	enrichers         []Enricher
+	dropOnEnrichError bool
+	publishFilter     PublishFilter
+	failures          FailureStore
+	pauses            PauseStore
+	health            *HealthTracker
+	sequences         SequenceStore
+	running           atomic.Bool
+	paused            atomic.Bool
+
+	checkMu   sync.Mutex
+	checkedAt time.Time // when the upstream was last checked
+	checkErr  error     // and what that check returned
 }
 
+// healthcheckInterval is how long Healthcheck reuses the outcome of an
+// upstream check, so frequent readiness probes don't each send a request.
+const healthcheckInterval = 30 * time.Second
+
 func NewSyncService(
 	source Source,
 	articles ArticleStore,
 	tags TagStore,
 	syncState SyncStateStore,
+	rawStore RawPayloadStore,
 	txManager TransactionManager,
 	publisher Publisher,
 	logger *slog.Logger,
@@ -36,6 +60,7 @@ func NewSyncService(
 		articles:  articles,
 		tags:      tags,
 		syncState: syncState,
+		rawStore:  rawStore,
 		txManager: txManager,
 		publisher: publisher,
 		logger:    logger.With("source", source.ID()),
@@ -43,67 +68,402 @@ func NewSyncService(
 	}
 }
 
+// SourceID returns the ID of the source this service syncs.
+func (s *SyncService) SourceID() string {
+	return s.source.ID()
+}
+
+// SetConfig replaces the sync configuration. It takes effect on the next Sync.
+func (s *SyncService) SetConfig(cfg config.SyncConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = cfg
+}
+
+// SetEnrichers sets the enrichers applied, in order, to every fetched article
+// before it is persisted. If dropOnError is set, an article an enricher fails
+// on is left out of the sync; otherwise the error is logged and the article
+// kept as the previous enrichers left it.
+func (s *SyncService) SetEnrichers(enrichers []Enricher, dropOnError bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enrichers = enrichers
+	s.dropOnEnrichError = dropOnError
+}
+
+// SetPublishFilter sets the filter consulted before publishing each saved
+// article. Nil publishes everything.
+func (s *SyncService) SetPublishFilter(filter PublishFilter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.publishFilter = filter
+}
+
+// SetFailureStore enables quarantining articles that fail to save
+// cfg.QuarantineAfter times. Nil disables it.
+func (s *SyncService) SetFailureStore(failures FailureStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = failures
+}
+
+// SetPauseStore persists pausing and resuming the source. Nil keeps the pause
+// state in memory only.
+func (s *SyncService) SetPauseStore(pauses PauseStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pauses = pauses
+}
+
+// SetSequenceStore numbers the published messages of the source, in publish
+// order. Nil leaves them unnumbered.
+func (s *SyncService) SetSequenceStore(sequences SequenceStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sequences = sequences
+}
+
+// RestorePaused pauses the service if the pause store records the source as
+// paused, e.g. by a previous process.
+func (s *SyncService) RestorePaused(ctx context.Context) error {
+	s.mu.RLock()
+	pauses := s.pauses
+	s.mu.RUnlock()
+	if pauses == nil {
+		return nil
+	}
+
+	paused, err := pauses.IsPaused(ctx, s.source.ID())
+	if err != nil {
+		return &StoreError{Op: "get pause state", Err: err}
+	}
+	s.paused.Store(paused)
+	if paused {
+		s.logger.Warn("source is paused")
+	}
+	return nil
+}
+
+// Pause makes Sync fail with ErrSourcePaused, without fetching, until Resume
+// is called. It takes effect even if it can't be persisted, in which case the
+// error is returned.
+func (s *SyncService) Pause(ctx context.Context) error {
+	s.paused.Store(true)
+	s.logger.Warn("source paused")
+	return s.persistPaused(ctx, true)
+}
+
+// Resume undoes Pause.
+func (s *SyncService) Resume(ctx context.Context) error {
+	s.paused.Store(false)
+	s.logger.Info("source resumed")
+	return s.persistPaused(ctx, false)
+}
+
+// Paused reports whether the source is paused.
+func (s *SyncService) Paused() bool {
+	return s.paused.Load()
+}
+
+func (s *SyncService) persistPaused(ctx context.Context, paused bool) error {
+	s.mu.RLock()
+	pauses := s.pauses
+	s.mu.RUnlock()
+	if pauses == nil {
+		return nil
+	}
+
+	if err := pauses.SetPaused(ctx, s.source.ID(), paused); err != nil {
+		return &StoreError{Op: "set pause state", Err: err}
+	}
+	return nil
+}
+
+// SetHealthTracker records the outcome of every sync in health. Nil disables
+// it.
+func (s *SyncService) SetHealthTracker(health *HealthTracker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = health
+}
+
+func (s *SyncService) syncConfig() config.SyncConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.config
+}
+
+// SyncOptions adjusts what a sync pass reports.
+type SyncOptions struct {
+	// CollectChanges lists every saved article in the result. Off by default
+	// so that large syncs don't accumulate the list.
+	CollectChanges bool
+	// DryRun fetches and compares the articles with the stored ones, then
+	// stops short of saving, publishing or recording anything. The result
+	// lists the articles the sync would create and update, the latter with
+	// the fields it would change.
+	DryRun bool
+}
+
+// Sync runs a single sync pass. Only one pass runs at a time; concurrent calls
+// fail with ErrSyncInProgress. While the source is paused it fails with
+// ErrSourcePaused.
 func (s *SyncService) Sync(ctx context.Context) (*domain.SyncStats, error) {
+	result, err := s.SyncWithOptions(ctx, SyncOptions{})
+	if result == nil {
+		return nil, err
+	}
+	return &result.SyncStats, err
+}
+
+// Healthcheck checks the source's upstream is reachable, if the source
+// implements HealthChecker, and records the outcome in the health tracker.
+// Sources that don't are reported healthy and nothing is recorded. The
+// outcome is reused for healthcheckInterval, unless ctx cut the check short.
+func (s *SyncService) Healthcheck(ctx context.Context) error {
+	checker, ok := s.source.(HealthChecker)
+	if !ok {
+		return nil
+	}
+
+	s.checkMu.Lock()
+	defer s.checkMu.Unlock()
+	if !s.checkedAt.IsZero() && time.Since(s.checkedAt) < healthcheckInterval {
+		return s.checkErr
+	}
+	err := checker.Healthcheck(ctx)
+	if ctx.Err() == nil {
+		s.checkedAt, s.checkErr = time.Now(), err
+	}
+
+	s.mu.RLock()
+	health := s.health
+	s.mu.RUnlock()
+	if health != nil {
+		health.RecordCheck(ctx, s.source.ID(), err)
+	}
+	return err
+}
+
+// SyncWithOptions is Sync with options, returning the stats and whatever else
+// opts asks for.
+func (s *SyncService) SyncWithOptions(ctx context.Context, opts SyncOptions) (*domain.SyncResult, error) {
+	ctx = domain.WithSourceID(ctx, s.source.ID())
+	if s.paused.Load() {
+		s.logger.Info("source paused, skipping sync")
+		return nil, ErrSourcePaused
+	}
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, ErrSyncInProgress
+	}
+	defer s.running.Store(false)
+
+	startTime := time.Now()
+	result, err := s.sync(ctx, opts)
+
+	s.mu.RLock()
+	health := s.health
+	s.mu.RUnlock()
+	if health != nil && !opts.DryRun {
+		health.Record(ctx, s.source.ID(), time.Since(startTime), err)
+	}
+	return result, err
+}
+
+// filterByDate drops the articles published more than cfg.MaxHistoricalDays
+// before now, unless cfg.DisableDateFilter is set.
+func filterByDate(articles domain.Articles, cfg config.SyncConfig, now time.Time) domain.Articles {
+	if cfg.DisableDateFilter {
+		return articles
+	}
+	return articles.FilterAfter(now.AddDate(0, 0, -cfg.MaxHistoricalDays))
+}
+
+// sync runs a sync pass for SyncWithOptions.
+func (s *SyncService) sync(ctx context.Context, opts SyncOptions) (*domain.SyncResult, error) {
 	startTime := time.Now()
+	cfg := s.syncConfig()
+	s.mu.RLock()
+	publishFilter, failures := s.publishFilter, s.failures
+	s.mu.RUnlock()
 	s.logger.Info("starting sync",
 		"source_name", s.source.Name(),
-		"max_pages", s.config.MaxPagesPerSync,
-		"max_historical_days", s.config.MaxHistoricalDays,
+		"max_pages", cfg.MaxPagesPerSync,
+		"max_historical_days", cfg.MaxHistoricalDays,
+		"incremental", cfg.Incremental,
 	)
 
+	var modifiedSince time.Time
+	if cfg.Incremental {
+		since, err := s.modifiedSince(ctx)
+		if err != nil {
+			return nil, &StoreError{Op: "get sync state", Err: err}
+		}
+		modifiedSince = since
+	}
+
 	// Fetch articles from source (already transformed to domain)
-	articles, err := s.source.FetchArticles(ctx, s.config.MaxPagesPerSync)
+	fetchStart := time.Now()
+	fetched, err := s.source.FetchArticles(ctx, cfg.MaxPagesPerSync, modifiedSince)
+	fetchDuration := time.Since(fetchStart)
 	if err != nil {
-		return nil, fmt.Errorf("fetch articles: %w", err)
+		return nil, &FetchError{SourceID: s.source.ID(), Err: err}
 	}
+	articles := domain.Articles(fetched)
 
 	s.logger.Info("fetched articles from source", "count", len(articles))
 
+	// Drop duplicates (upstream paging can return an article twice)
+	deduped := articles.Dedup()
+	if dropped := len(articles) - len(deduped); dropped > 0 {
+		s.logger.Debug("dropped duplicate articles", "count", dropped)
+	}
+	articles = deduped
+
 	// Filter by date
-	cutoffDate := time.Now().AddDate(0, 0, -s.config.MaxHistoricalDays)
-	articles = s.filterByDate(articles, cutoffDate)
+	articles = filterByDate(articles, cfg, time.Now())
 	s.logger.Debug("filtered by date", "remaining", len(articles))
+	fetchedCount := len(articles)
+
+	// Leave out quarantined articles; failing tracks the ones with failed saves
+	var failing map[string]bool
+	if failures != nil {
+		failing, err = failures.Failing(ctx, s.source.ID())
+		if err != nil {
+			return nil, &StoreError{Op: "get failing articles", Err: err}
+		}
+	}
+	articles, quarantined := excludeQuarantined(articles, failing)
+
+	// Enrich before filtering, so the content hash covers the enriched fields
+	articles, dropped := s.enrich(ctx, articles)
 
 	// Filter for sync (new or updated)
-	toSync, err := s.filterForSync(ctx, articles)
+	toSync, existing, err := s.filterForSync(ctx, articles, modifiedSince)
 	if err != nil {
-		return nil, fmt.Errorf("filter for sync: %w", err)
+		return nil, &StoreError{Op: "filter for sync", Err: err}
 	}
 
 	s.logger.Info("articles to sync", "count", len(toSync))
 
-	stats := &domain.SyncStats{
-		SourceID: s.source.ID(),
-		Fetched:  len(articles),
-		Skipped:  len(articles) - len(toSync),
+	// Timeline order, so consumers receive events in the order they happened
+	toSync.SortByPublishedAt(cfg.Order == config.OrderNewestFirst)
+
+	result := &domain.SyncResult{SyncStats: domain.SyncStats{
+		SourceID:      s.source.ID(),
+		Fetched:       fetchedCount,
+		Skipped:       len(articles) - len(toSync),
+		Errors:        dropped,
+		Quarantined:   quarantined,
+		FetchDuration: fetchDuration,
+	}}
+	stats := &result.SyncStats
+
+	// Bound the run; the deferred articles are still new or updated next time
+	if cfg.MaxArticlesPerSync > 0 && len(toSync) > cfg.MaxArticlesPerSync {
+		stats.Deferred = len(toSync) - cfg.MaxArticlesPerSync
+		toSync = toSync[:cfg.MaxArticlesPerSync]
+		s.logger.Warn("article cap reached, deferring the rest to the next sync",
+			"max_articles", cfg.MaxArticlesPerSync,
+			"deferred", stats.Deferred,
+		)
+	}
+
+	if opts.DryRun {
+		s.dryRun(ctx, result, toSync, existing)
+		stats.Duration = time.Since(startTime)
+		return result, nil
 	}
 
+	// lastPublished is the newest PublishedAt of the saved articles
+	var lastPublished time.Time
 	for i := range toSync {
+		select {
+		case <-ctx.Done():
+			// The rest is left for the next sync, like articles over the cap
+			stats.Deferred += len(toSync) - i
+			stats.Duration = time.Since(startTime)
+			s.logger.Warn("sync interrupted",
+				"processed", i,
+				"remaining", len(toSync)-i,
+				"error", ctx.Err(),
+			)
+			if err := s.updateSyncState(ctx, stats, lastPublished); err != nil {
+				s.logger.Error("failed to update sync state", "error", err)
+			}
+			return result, ctx.Err()
+		default:
+		}
+
 		article := &toSync[i]
-		isNew, err := s.saveArticle(ctx, article)
+		stored, exists := existing[article.Key()]
+		isNew := !exists
+		var update *domain.ExistingArticle
+		if exists {
+			update = &stored
+		}
+		persistStart := time.Now()
+		articleID, tagsFailed, err := s.saveArticle(ctx, article, update, cfg.TolerateTagErrors)
+		stats.PersistDuration += time.Since(persistStart)
 		if err != nil {
+			s.logger.Error("failed to save article", "external_id", article.ExternalID, "error", err)
 			stats.Errors++
+			if failures != nil && ctx.Err() == nil {
+				s.recordFailure(ctx, failures, article, err, cfg.QuarantineAfter)
+			}
 			continue
 		}
+		if tagsFailed {
+			stats.TagErrors++
+		}
+		if article.PublishedAt.After(lastPublished) {
+			lastPublished = article.PublishedAt
+		}
+		if _, ok := failing[article.Key()]; ok {
+			if err := failures.Clear(ctx, s.source.ID(), article.Key()); err != nil {
+				s.logger.Warn("failed to clear article failures", "external_id", article.ExternalID, "error", err)
+			}
+		}
 
-		if s.publisher != nil {
-			if err := s.publisher.Publish(ctx, article, isNew); err != nil {
+		// A pending update stays pending until it is published or
+		// suppressed, so a failed publish is retried by the next sync.
+		outcome := s.publishSaved(ctx, article, isNew, publishFilter, cfg.QuietPeriod, stats)
+		switch {
+		case (outcome == publishDone || outcome == publishSuppressed) && stored.PublishPending:
+			if err := s.articles.SetPublishPending(ctx, articleID, false); err != nil {
+				s.logger.Warn("failed to clear pending publish", "external_id", article.ExternalID, "error", err)
+			}
+		case outcome == publishDeferred && !stored.PublishPending:
+			if err := s.articles.SetPublishPending(ctx, articleID, true); err != nil {
+				s.logger.Error("failed to defer publish", "external_id", article.ExternalID, "error", err)
 				stats.Errors++
-			} else {
-				stats.Published++
 			}
 		}
 
+		action := domain.ActionUpdated
 		if isNew {
 			stats.New++
+			action = domain.ActionCreated
 		} else {
 			stats.Updated++
 		}
+		if opts.CollectChanges {
+			result.Changes = append(result.Changes, domain.ArticleChange{
+				ID:         articleID,
+				ExternalID: article.ExternalID,
+				Action:     action,
+			})
+		}
 	}
 
-	if err := s.updateSyncState(ctx, stats); err != nil {
-		return stats, fmt.Errorf("update sync state: %w", err)
+	s.publishPending(ctx, articles, publishFilter, cfg.QuietPeriod, stats)
+
+	if err := s.updateSyncState(ctx, stats, lastPublished); err != nil {
+		return result, &StoreError{Op: "update sync state", Err: err}
 	}
+	// Only a completed sync counts as a success, not one persisted after it
+	// was interrupted.
+	metrics.LastSuccessTimestamp.WithLabelValues(s.source.ID()).SetToCurrentTime()
 
 	stats.Duration = time.Since(startTime)
 
@@ -113,94 +473,455 @@ func (s *SyncService) Sync(ctx context.Context) (*domain.SyncStats, error) {
 		"skipped", stats.Skipped,
 		"errors", stats.Errors,
 		"published", stats.Published,
+		"suppressed", stats.Suppressed,
+		"deferred", stats.Deferred,
+		"quarantined", stats.Quarantined,
+		"tag_errors", stats.TagErrors,
+		"publish_deferred", stats.PublishDeferred,
 		"duration", stats.Duration,
+		"fetch_duration", stats.FetchDuration,
+		"persist_duration", stats.PersistDuration,
+		"publish_duration", stats.PublishDuration,
 	)
+	observeStageDurations(stats)
+
+	if p, ok := s.publisher.(SyncCompletedPublisher); ok {
+		// The articles are stored and published either way, so a failed
+		// announcement doesn't fail the sync.
+		if err := p.PublishSyncCompleted(ctx, stats); err != nil {
+			s.logger.Error("failed to publish sync completed", "error", err)
+		}
+	}
 
-	return stats, nil
+	return result, nil
 }
 
-func (s *SyncService) filterByDate(articles []domain.Article, cutoff time.Time) []domain.Article {
-	var filtered []domain.Article
-	for _, a := range articles {
-		if a.PublishedAt.After(cutoff) {
-			filtered = append(filtered, a)
+// dryRun lists in result the articles of toSync the sync would create, and
+// those it would update with the fields that would change, without saving
+// anything. An update whose stored version can't be loaded is counted as an
+// error and left out.
+func (s *SyncService) dryRun(ctx context.Context, result *domain.SyncResult, toSync domain.Articles, existing map[string]domain.ExistingArticle) {
+	stats := &result.SyncStats
+	for i := range toSync {
+		article := &toSync[i]
+		change := domain.ArticleChange{ExternalID: article.ExternalID, Action: domain.ActionCreated}
+		if stored, ok := existing[article.Key()]; ok {
+			current, err := s.articles.GetByID(ctx, stored.ID)
+			if err != nil {
+				s.logger.Error("failed to load stored article", "external_id", article.ExternalID, "error", err)
+				stats.Errors++
+				continue
+			}
+			change.ID = stored.ID
+			change.Action = domain.ActionUpdated
+			change.Diff = current.Diff(article)
+			stats.Updated++
+		} else {
+			stats.New++
 		}
+		result.Changes = append(result.Changes, change)
 	}
-	return filtered
+
+	s.logger.Info("dry run completed",
+		"new", stats.New,
+		"updated", stats.Updated,
+		"skipped", stats.Skipped,
+		"errors", stats.Errors,
+		"deferred", stats.Deferred,
+	)
 }
 
-func (s *SyncService) filterForSync(ctx context.Context, articles []domain.Article) ([]domain.Article, error) {
-	if len(articles) == 0 {
-		return nil, nil
+// observeStageDurations records the stage timings of a completed sync.
+func observeStageDurations(stats *domain.SyncStats) {
+	metrics.SyncStageDuration.WithLabelValues(stats.SourceID, "fetch").Observe(stats.FetchDuration.Seconds())
+	metrics.SyncStageDuration.WithLabelValues(stats.SourceID, "persist").Observe(stats.PersistDuration.Seconds())
+	metrics.SyncStageDuration.WithLabelValues(stats.SourceID, "publish").Observe(stats.PublishDuration.Seconds())
+}
+
+// incrementalOverlap is subtracted from the last sync time so that articles
+// modified while that sync was running are fetched again rather than missed.
+const incrementalOverlap = 5 * time.Minute
+
+// modifiedSince returns the lower bound for an incremental fetch, or the zero
+// time if the source has never been synced.
+func (s *SyncService) modifiedSince(ctx context.Context) (time.Time, error) {
+	state, err := s.syncState.Get(ctx, s.source.ID())
+	if err != nil {
+		return time.Time{}, err
+	}
+	if state.LastSyncedAt.IsZero() {
+		return time.Time{}, nil
 	}
+	return state.LastSyncedAt.Add(-incrementalOverlap), nil
+}
+
+// enrich runs the enrichers on every article. It returns the articles to keep
+// and how many were dropped because an enricher failed.
+func (s *SyncService) enrich(ctx context.Context, articles domain.Articles) (domain.Articles, int) {
+	s.mu.RLock()
+	enrichers, dropOnError := s.enrichers, s.dropOnEnrichError
+	s.mu.RUnlock()
 
-	externalIDs := make([]int64, len(articles))
-	for i, a := range articles {
-		externalIDs[i] = a.ExternalID
+	if len(enrichers) == 0 {
+		return articles, 0
 	}
 
-	existing, err := s.articles.GetExistingBySourceAndExternalIDs(ctx, s.source.ID(), externalIDs)
+	kept := make(domain.Articles, 0, len(articles))
+	for i := range articles {
+		article := &articles[i]
+		if err := s.enrichArticle(ctx, enrichers, article); err != nil {
+			if dropOnError {
+				s.logger.Error("dropping article", "external_id", article.ExternalID, "error", err)
+				continue
+			}
+			s.logger.Warn("failed to enrich article", "external_id", article.ExternalID, "error", err)
+		}
+		kept = append(kept, *article)
+	}
+	return kept, len(articles) - len(kept)
+}
+
+// enrichArticle applies the enrichers in order, stopping at the first error.
+func (s *SyncService) enrichArticle(ctx context.Context, enrichers []Enricher, article *domain.Article) error {
+	for _, e := range enrichers {
+		if err := e.Enrich(ctx, article); err != nil {
+			return &EnrichError{ExternalID: article.ExternalID, Err: err}
+		}
+	}
+	return nil
+}
+
+// filterForSync returns the articles that are new or updated, and the stored
+// versions of the fetched articles keyed by key. An article with a
+// newer LastModified but the same content hash is unchanged and skipped.
+// A non-zero since is the incremental fetch's lower bound; see getExisting.
+func (s *SyncService) filterForSync(ctx context.Context, articles domain.Articles, since time.Time) (domain.Articles, map[string]domain.ExistingArticle, error) {
+	if len(articles) == 0 {
+		return nil, nil, nil
+	}
+
+	existing, err := s.getExisting(ctx, articles.Keys(), since)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	var toSync []domain.Article
+	var toSync domain.Articles
 	for _, article := range articles {
-		existingLastMod, exists := existing[article.ExternalID]
+		stored, exists := existing[article.Key()]
 
-		if !exists {
+		if !exists || stored.PublishPending {
+			// An update deferred by the quiet period is synced again until
+			// it is published.
 			toSync = append(toSync, article)
-		} else if article.LastModified.After(existingLastMod) {
+		} else if article.LastModified.After(stored.LastModified) {
+			if stored.ContentHash != "" && stored.ContentHash == article.ContentHash() {
+				s.logger.Debug("skipping unchanged article", "external_id", article.ExternalID)
+				continue
+			}
 			toSync = append(toSync, article)
 		}
 	}
 
-	return toSync, nil
+	return toSync, existing, nil
 }
 
-func (s *SyncService) saveArticle(ctx context.Context, article *domain.Article) (bool, error) {
-	existing, err := s.articles.GetExistingBySourceAndExternalIDs(ctx, s.source.ID(), []int64{article.ExternalID})
+// getExisting returns the stored versions of the articles with the given
+// keys. With a non-zero since, most fetched articles were stored
+// since then too, so they are looked up with that bound first; only the
+// ones it doesn't find, new articles and updates of articles stored before
+// since, are looked up without it.
+func (s *SyncService) getExisting(ctx context.Context, keys []string, since time.Time) (map[string]domain.ExistingArticle, error) {
+	if since.IsZero() {
+		return s.articles.GetExisting(ctx, s.source.ID(), keys)
+	}
+
+	existing, err := s.articles.GetExistingSince(ctx, s.source.ID(), keys, since)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	var missing []string
+	for _, key := range keys {
+		if _, ok := existing[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return existing, nil
+	}
+
+	older, err := s.articles.GetExisting(ctx, s.source.ID(), missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, stored := range older {
+		existing[key] = stored
+	}
+	return existing, nil
+}
+
+// excludeQuarantined drops the articles failing marks as quarantined and
+// returns the rest and how many were dropped.
+func excludeQuarantined(articles domain.Articles, failing map[string]bool) (domain.Articles, int) {
+	if len(failing) == 0 {
+		return articles, 0
+	}
+
+	kept := make(domain.Articles, 0, len(articles))
+	for _, article := range articles {
+		if !failing[article.Key()] {
+			kept = append(kept, article)
+		}
+	}
+	return kept, len(articles) - len(kept)
+}
+
+// recordFailure counts a failed save of article, logging it if that
+// quarantines the article.
+func (s *SyncService) recordFailure(ctx context.Context, failures FailureStore, article *domain.Article, cause error, maxAttempts int) {
+	quarantined, err := failures.RecordFailure(ctx, article, cause, maxAttempts)
+	if err != nil {
+		s.logger.Warn("failed to record article failure", "external_id", article.ExternalID, "error", err)
+		return
+	}
+	if quarantined {
+		s.logger.Warn("article quarantined",
+			"external_id", article.ExternalID,
+			"attempts", maxAttempts,
+			"error", cause,
+		)
+	}
+}
+
+// saveArticle stores the article with its tags and raw payload in one
+// transaction and returns its ID. If tolerateTagErrors is set, a failure to
+// store or link the tags only rolls back the tags, in a savepoint: the article
+// is kept, linked to the tags it had before, and tagsFailed is set. Otherwise
+// it rolls back and fails the whole article.
+// stored is the version the existing-articles lookup found, nil for a new
+// article; an update is logged at debug level, see logUpdate.
+func (s *SyncService) saveArticle(ctx context.Context, article *domain.Article, stored *domain.ExistingArticle, tolerateTagErrors bool) (articleID int64, tagsFailed bool, err error) {
+	// Hashed before Upsert reads the protected columns back into article.
+	logUpdate := stored != nil && s.logger.Enabled(ctx, slog.LevelDebug)
+	var hash string
+	if logUpdate {
+		hash = article.ContentHash()
 	}
-	isNew := len(existing) == 0
 
 	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
-		articleID, err := s.articles.Upsert(txCtx, article)
+		var err error
+		articleID, err = s.articles.Upsert(txCtx, article)
 		if err != nil {
 			return fmt.Errorf("upsert article: %w", err)
 		}
 
 		if len(article.Tags) > 0 {
-			if err := s.tags.UpsertBatch(txCtx, article.Tags); err != nil {
-				return fmt.Errorf("upsert tags: %w", err)
-			}
-
-			tagIDs := make([]int64, len(article.Tags))
-			for i, tag := range article.Tags {
-				tagIDs[i] = tag.ID
+			if !tolerateTagErrors {
+				if err := s.saveTags(txCtx, articleID, article); err != nil {
+					return err
+				}
+			} else if err := s.txManager.WithTransaction(txCtx, func(tagCtx context.Context) error {
+				return s.saveTags(tagCtx, articleID, article)
+			}); err != nil {
+				s.logger.Warn("failed to save tags, keeping article without them",
+					"external_id", article.ExternalID,
+					"error", err,
+				)
+				tagsFailed = true
 			}
+		}
 
-			if err := s.tags.LinkToArticle(txCtx, articleID, tagIDs); err != nil {
-				return fmt.Errorf("link tags: %w", err)
+		if len(article.Raw) > 0 {
+			if err := s.rawStore.Save(txCtx, article); err != nil {
+				return fmt.Errorf("save raw payload: %w", err)
 			}
 		}
 
 		return nil
 	})
+	if err != nil {
+		return 0, false, &StoreError{Op: "save article", Err: err}
+	}
+
+	if logUpdate {
+		s.logUpdate(article, hash, *stored)
+	}
+	return articleID, tagsFailed, nil
+}
+
+// logUpdate logs how an updated article, with content hash hash, differs from
+// its stored version, to tell why an article keeps being synced. The lookup
+// only has the stored last_modified and content hash, so changed lists which
+// of those differ; neither does for an update synced again only because its
+// publish is pending.
+func (s *SyncService) logUpdate(article *domain.Article, hash string, stored domain.ExistingArticle) {
+	var changed []string
+	if !article.LastModified.Equal(stored.LastModified) {
+		changed = append(changed, "last_modified")
+	}
+	if hash != stored.ContentHash {
+		changed = append(changed, "content_hash")
+	}
+	s.logger.Debug("article updated",
+		"external_id", article.ExternalID,
+		"id", stored.ID,
+		"old_last_modified", stored.LastModified,
+		"new_last_modified", article.LastModified,
+		"old_content_hash", stored.ContentHash,
+		"new_content_hash", hash,
+		"changed", changed,
+		"publish_pending", stored.PublishPending,
+	)
+}
 
-	return isNew, err
+// saveTags upserts the article's tags and links them to the stored article.
+func (s *SyncService) saveTags(ctx context.Context, articleID int64, article *domain.Article) error {
+	if err := s.tags.UpsertBatch(ctx, article.Tags); err != nil {
+		return fmt.Errorf("upsert tags: %w", err)
+	}
+	if err := s.tags.LinkToArticle(ctx, articleID, article.TagIDs()); err != nil {
+		return fmt.Errorf("link tags: %w", err)
+	}
+	return nil
 }
 
-func (s *SyncService) updateSyncState(ctx context.Context, stats *domain.SyncStats) error {
+// publishOutcome is what became of the publish of a saved article.
+type publishOutcome int
+
+const (
+	publishDone publishOutcome = iota
+	publishSuppressed
+	publishDeferred
+	publishFailed
+)
+
+// publishSaved publishes a saved article unless the publish filter suppresses
+// it or, for an update, it was modified within the quiet period. It counts
+// the outcome in stats and returns it.
+func (s *SyncService) publishSaved(ctx context.Context, article *domain.Article, isNew bool, publishFilter PublishFilter, quietPeriod time.Duration, stats *domain.SyncStats) publishOutcome {
+	if !isNew && quietPeriod > 0 && time.Since(article.LastModified) < quietPeriod {
+		s.logger.Debug("article modified within quiet period, deferring publish",
+			"external_id", article.ExternalID,
+			"last_modified", article.LastModified,
+		)
+		stats.PublishDeferred++
+		return publishDeferred
+	}
+
+	if publishFilter != nil && !publishFilter(article) {
+		s.logger.Debug("publish suppressed", "external_id", article.ExternalID)
+		stats.Suppressed++
+		return publishSuppressed
+	}
+
+	publishStart := time.Now()
+	err := s.publish(ctx, article, isNew)
+	stats.PublishDuration += time.Since(publishStart)
+	if err != nil {
+		s.logger.Error("failed to publish article", "external_id", article.ExternalID, "error", err)
+		stats.Errors++
+		return publishFailed
+	}
+	if d, ok := s.publisher.(Discarder); !ok || !d.Discards() {
+		stats.Published++
+	}
+	return publishDone
+}
+
+// publishPending publishes the stored updates deferred by the quiet period
+// that weren't fetched this run, e.g. because an incremental fetch leaves
+// out articles not modified since, once their quiet period is over. Fetched
+// ones were handled with the rest. Failures are logged and counted in stats,
+// and the update is tried again next run.
+func (s *SyncService) publishPending(ctx context.Context, fetched domain.Articles, publishFilter PublishFilter, quietPeriod time.Duration, stats *domain.SyncStats) {
+	pending, err := s.articles.ListPublishPending(ctx, s.source.ID())
+	if err != nil {
+		s.logger.Error("failed to list pending publishes", "error", err)
+		stats.Errors++
+		return
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(fetched))
+	for _, key := range fetched.Keys() {
+		seen[key] = true
+	}
+	for i := range pending {
+		article := &pending[i]
+		if seen[article.Key()] || ctx.Err() != nil {
+			continue
+		}
+		outcome := s.publishSaved(ctx, article, false, publishFilter, quietPeriod, stats)
+		if outcome != publishDone && outcome != publishSuppressed {
+			continue
+		}
+		if err := s.articles.SetPublishPending(ctx, article.ID, false); err != nil {
+			s.logger.Warn("failed to clear pending publish", "external_id", article.ExternalID, "error", err)
+		}
+	}
+}
+
+func (s *SyncService) publish(ctx context.Context, article *domain.Article, isNew bool) error {
+	s.mu.RLock()
+	sequences := s.sequences
+	s.mu.RUnlock()
+
+	if err := PublishNumbered(ctx, s.txManager, sequences, s.publisher, article, isNew); err != nil {
+		return &PublishError{ExternalID: article.ExternalID, Err: err}
+	}
+	return nil
+}
+
+// syncStateTimeout bounds the sync state update, which runs even if the sync
+// itself was cancelled.
+const syncStateTimeout = 5 * time.Second
+
+// updateSyncState records the run, in which the newest article saved was
+// published at lastPublished (zero if none was saved). It uses a context
+// detached from ctx so that progress is persisted even when the sync was
+// cancelled, e.g. on shutdown.
+func (s *SyncService) updateSyncState(ctx context.Context, stats *domain.SyncStats, lastPublished time.Time) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), syncStateTimeout)
+	defer cancel()
+
+	if err := s.saveSyncState(ctx, stats, time.Now(), lastPublished); err != nil {
+		return err
+	}
+
+	if !lastPublished.IsZero() {
+		metrics.LastPublishedTimestamp.WithLabelValues(s.source.ID()).Set(float64(lastPublished.Unix()))
+	}
+	return nil
+}
+
+func (s *SyncService) saveSyncState(ctx context.Context, stats *domain.SyncStats, now, lastPublished time.Time) error {
+	// A sync that changed and deferred nothing only moves the sync time, so
+	// frequent no-op syncs don't rewrite the whole state. If it failed to
+	// save something, not even that; see below.
+	if stats.New+stats.Updated == 0 && stats.Deferred == 0 {
+		if stats.Errors > 0 {
+			return nil
+		}
+		return s.syncState.TouchLastSynced(ctx, s.source.ID(), now)
+	}
+
 	state, err := s.syncState.Get(ctx, s.source.ID())
 	if err != nil {
 		return err
 	}
 
 	state.SourceID = s.source.ID()
-	state.LastSyncedAt = time.Now()
+	// With deferred or failed articles, keep the previous sync time so an
+	// incremental fetch still reaches them next run, and a failing one is
+	// retried until it is quarantined.
+	if stats.Deferred == 0 && stats.Errors == 0 {
+		state.LastSyncedAt = now
+	}
 	state.TotalSynced += int64(stats.New + stats.Updated)
+	if !lastPublished.IsZero() && (state.LastPublishedAt == nil || lastPublished.After(*state.LastPublishedAt)) {
+		state.LastPublishedAt = &lastPublished
+	}
 
 	return s.syncState.Update(ctx, state)
 }
@@ -8,12 +8,16 @@ import (
 	"testing"
 	"time"
 
+	promtest "github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
 
 	"news_fetcher/internal/config"
 	"news_fetcher/internal/domain"
+	"news_fetcher/internal/metrics"
+	"news_fetcher/internal/publisher"
 	"news_fetcher/internal/service/mocks"
+	"news_fetcher/testdata/testutil"
 )
 
 type SyncServiceTestSuite struct {
@@ -24,12 +28,15 @@ type SyncServiceTestSuite struct {
 	articles    *mocks.MockArticleStore
 	tags        *mocks.MockTagStore
 	syncState   *mocks.MockSyncStateStore
+	rawStore    *mocks.MockRawPayloadStore
 	txManager   *mocks.MockTransactionManager
 	publisher   *mocks.MockPublisher
 
 	service *SyncService
 	cfg     config.SyncConfig
 	logger  *slog.Logger
+	// pending are the stored articles with a pending update.
+	pending []domain.Article
 }
 
 func (s *SyncServiceTestSuite) SetupTest() {
@@ -39,6 +46,7 @@ func (s *SyncServiceTestSuite) SetupTest() {
 	s.articles = mocks.NewMockArticleStore(s.ctrl)
 	s.tags = mocks.NewMockTagStore(s.ctrl)
 	s.syncState = mocks.NewMockSyncStateStore(s.ctrl)
+	s.rawStore = mocks.NewMockRawPayloadStore(s.ctrl)
 	s.txManager = mocks.NewMockTransactionManager(s.ctrl)
 	s.publisher = mocks.NewMockPublisher(s.ctrl)
 
@@ -52,12 +60,20 @@ func (s *SyncServiceTestSuite) SetupTest() {
 
 	s.source.EXPECT().ID().Return("test-source").AnyTimes()
 	s.source.EXPECT().Name().Return("Test Source").AnyTimes()
+	// Every sync looks for pending updates it didn't fetch; see s.pending.
+	s.pending = nil
+	s.articles.EXPECT().ListPublishPending(gomock.Any(), "test-source").DoAndReturn(
+		func(context.Context, string) ([]domain.Article, error) {
+			return s.pending, nil
+		},
+	).AnyTimes()
 
 	s.service = NewSyncService(
 		s.source,
 		s.articles,
 		s.tags,
 		s.syncState,
+		s.rawStore,
 		s.txManager,
 		s.publisher,
 		s.logger,
@@ -73,8 +89,14 @@ func TestSyncServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(SyncServiceTestSuite))
 }
 
+// syncContext returns a context carrying the source ID, as SyncService passes
+// it on to the stores, the source and the publisher.
+func syncContext() context.Context {
+	return domain.WithSourceID(context.Background(), "test-source")
+}
+
 func (s *SyncServiceTestSuite) TestSync_NewArticles() {
-	ctx := context.Background()
+	ctx := syncContext()
 	now := time.Now()
 
 	articles := []domain.Article{
@@ -88,11 +110,9 @@ func (s *SyncServiceTestSuite) TestSync_NewArticles() {
 		},
 	}
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(articles, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
 
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(map[int64]time.Time{}, nil)
-
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(map[int64]time.Time{}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
 
 	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
 		func(ctx context.Context, fn func(context.Context) error) error {
@@ -107,8 +127,8 @@ func (s *SyncServiceTestSuite) TestSync_NewArticles() {
 
 	s.publisher.EXPECT().Publish(ctx, &articles[0], true).Return(nil)
 
-	s.syncState.EXPECT().Get(ctx, "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
-	s.syncState.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 	stats, err := s.service.Sync(ctx)
 
@@ -120,8 +140,63 @@ func (s *SyncServiceTestSuite) TestSync_NewArticles() {
 	s.Equal(1, stats.Published)
 }
 
+// expectTagLinkFailure sets up a sync of one tagged article whose tags fail to
+// link, expecting txCalls transactions (nested ones included).
+func (s *SyncServiceTestSuite) expectTagLinkFailure(ctx context.Context, txCalls int) domain.Article {
+	article := s.timelineArticles()[0]
+	article.Tags = []domain.Tag{{ID: 1, Label: "tag"}}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return([]domain.Article{article}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(txCalls)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(100), nil)
+	s.tags.EXPECT().UpsertBatch(ctx, article.Tags).Return(nil)
+	s.tags.EXPECT().LinkToArticle(ctx, int64(100), []int64{1}).Return(errors.New("foreign key violation"))
+
+	return article
+}
+
+func (s *SyncServiceTestSuite) TestSync_TagLinkFailureAbortsArticle() {
+	ctx := syncContext()
+	s.expectTagLinkFailure(ctx, 1)
+	// Nothing was saved, so the sync state is left alone.
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.Errors)
+	s.Equal(0, stats.New)
+	s.Equal(0, stats.TagErrors)
+	s.Equal(0, stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_TagLinkFailureTolerated() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.TolerateTagErrors = true
+	s.service.SetConfig(cfg)
+
+	// The tags are saved in a nested transaction, so only they roll back.
+	s.expectTagLinkFailure(ctx, 2)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(0, stats.Errors)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.TagErrors)
+	s.Equal(1, stats.Published)
+}
+
 func (s *SyncServiceTestSuite) TestSync_UpdatedArticles() {
-	ctx := context.Background()
+	ctx := syncContext()
 	now := time.Now()
 	oldTime := now.Add(-1 * time.Hour)
 
@@ -135,14 +210,10 @@ func (s *SyncServiceTestSuite) TestSync_UpdatedArticles() {
 		},
 	}
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(articles, nil)
-
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(
-		map[int64]time.Time{1: oldTime}, nil,
-	)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
 
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(
-		map[int64]time.Time{1: oldTime}, nil,
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(
+		map[string]domain.ExistingArticle{"1": {ID: 100, LastModified: oldTime}}, nil,
 	)
 
 	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
@@ -155,8 +226,8 @@ func (s *SyncServiceTestSuite) TestSync_UpdatedArticles() {
 
 	s.publisher.EXPECT().Publish(ctx, &articles[0], false).Return(nil)
 
-	s.syncState.EXPECT().Get(ctx, "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
-	s.syncState.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 	stats, err := s.service.Sync(ctx)
 
@@ -167,8 +238,233 @@ func (s *SyncServiceTestSuite) TestSync_UpdatedArticles() {
 	s.Equal(0, stats.Skipped)
 }
 
+// recordingHandler is a slog handler keeping the records it handles.
+type recordingHandler struct {
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(string) slog.Handler      { return h }
+
+// find returns the attributes of the first record with the message.
+func (h *recordingHandler) find(msg string) (map[string]any, bool) {
+	for _, r := range h.records {
+		if r.Message != msg {
+			continue
+		}
+		attrs := make(map[string]any)
+		r.Attrs(func(a slog.Attr) bool {
+			attrs[a.Key] = a.Value.Any()
+			return true
+		})
+		return attrs, true
+	}
+	return nil, false
+}
+
+// expectUpdate sets up a sync updating article, stored as stored.
+func (s *SyncServiceTestSuite) expectUpdate(ctx context.Context, article *domain.Article, stored domain.ExistingArticle) {
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return([]domain.Article{*article}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{article.Key()}).Return(
+		map[string]domain.ExistingArticle{article.Key(): stored}, nil,
+	)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(stored.ID, nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), false).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+}
+
+func (s *SyncServiceTestSuite) TestSync_LogsUpdateAtDebug() {
+	ctx := syncContext()
+	now := time.Now().Truncate(time.Second)
+	oldTime := now.Add(-time.Hour)
+	article := &domain.Article{SourceID: "test-source", ExternalID: 1, Title: "updated asd", PublishedAt: now, LastModified: now}
+	s.expectUpdate(ctx, article, domain.ExistingArticle{ID: 100, LastModified: oldTime, ContentHash: "stale"})
+	handler := &recordingHandler{level: slog.LevelDebug}
+	s.service.logger = slog.New(handler)
+
+	_, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	attrs, ok := handler.find("article updated")
+	s.Require().True(ok, "update logged")
+	s.Equal(int64(1), attrs["external_id"])
+	s.Equal(int64(100), attrs["id"])
+	s.Equal(oldTime, attrs["old_last_modified"])
+	s.Equal(now, attrs["new_last_modified"])
+	s.Equal("stale", attrs["old_content_hash"])
+	s.Equal(article.ContentHash(), attrs["new_content_hash"])
+	s.Equal([]string{"last_modified", "content_hash"}, attrs["changed"])
+	s.Equal(false, attrs["publish_pending"])
+}
+
+func (s *SyncServiceTestSuite) TestSync_LogsPendingUpdateUnchanged() {
+	ctx := syncContext()
+	now := time.Now().Add(-time.Hour).Truncate(time.Second)
+	article := &domain.Article{SourceID: "test-source", ExternalID: 1, Title: "asd", PublishedAt: now, LastModified: now}
+	s.expectUpdate(ctx, article, domain.ExistingArticle{ID: 100, LastModified: now, ContentHash: article.ContentHash(), PublishPending: true})
+	s.articles.EXPECT().SetPublishPending(ctx, int64(100), false).Return(nil)
+	handler := &recordingHandler{level: slog.LevelDebug}
+	s.service.logger = slog.New(handler)
+
+	_, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	attrs, ok := handler.find("article updated")
+	s.Require().True(ok)
+	s.Nil(attrs["changed"], "synced again only to publish")
+	s.Equal(true, attrs["publish_pending"])
+}
+
+func (s *SyncServiceTestSuite) TestSync_NoUpdateLogAboveDebug() {
+	ctx := syncContext()
+	now := time.Now()
+	article := &domain.Article{SourceID: "test-source", ExternalID: 1, Title: "updated asd", PublishedAt: now, LastModified: now}
+	s.expectUpdate(ctx, article, domain.ExistingArticle{ID: 100, LastModified: now.Add(-time.Hour)})
+	handler := &recordingHandler{level: slog.LevelInfo}
+	s.service.logger = slog.New(handler)
+
+	_, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	_, ok := handler.find("article updated")
+	s.False(ok)
+}
+
+func (s *SyncServiceTestSuite) TestSyncWithOptions_CollectsChanges() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "new", PublishedAt: now.Add(-2 * time.Hour), LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, Title: "updated", PublishedAt: now.Add(-time.Hour), LastModified: now},
+		{SourceID: "test-source", ExternalID: 3, Title: "broken", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2", "3"}).Return(
+		map[string]domain.ExistingArticle{"2": {ID: 200, LastModified: now.Add(-time.Hour)}}, nil,
+	)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(3)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(100), nil)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(200), nil)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(0), errors.New("constraint violation"))
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.service.SyncWithOptions(ctx, SyncOptions{CollectChanges: true})
+
+	s.NoError(err)
+	s.Equal(1, result.New)
+	s.Equal(1, result.Updated)
+	s.Equal(1, result.Errors)
+	s.Equal([]domain.ArticleChange{
+		{ID: 100, ExternalID: 1, Action: domain.ActionCreated},
+		{ID: 200, ExternalID: 2, Action: domain.ActionUpdated},
+	}, result.Changes)
+}
+
+func (s *SyncServiceTestSuite) TestSyncWithOptions_ChangesOffByDefault() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{{SourceID: "test-source", ExternalID: 1, Title: "new", PublishedAt: now, LastModified: now}}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(100), nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := s.service.SyncWithOptions(ctx, SyncOptions{})
+
+	s.NoError(err)
+	s.Equal(1, result.New)
+	s.Nil(result.Changes)
+}
+
+func (s *SyncServiceTestSuite) TestSyncWithOptions_DryRunDiffsUpdates() {
+	ctx := syncContext()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "new", PublishedAt: now.Add(-time.Hour), LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, Title: "New title", PublishedAt: now, LastModified: now},
+	}
+	stored := &domain.Article{
+		ID: 200, SourceID: "test-source", ExternalID: 2, Title: "Old title",
+		PublishedAt: now, LastModified: now, Status: domain.StatusPublished,
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2"}).Return(
+		map[string]domain.ExistingArticle{"2": {ID: 200, LastModified: now.Add(-time.Hour)}}, nil,
+	)
+	s.articles.EXPECT().GetByID(ctx, int64(200)).Return(stored, nil)
+	// Nothing is saved, published or recorded: the mocks expect no more calls.
+
+	result, err := s.service.SyncWithOptions(ctx, SyncOptions{DryRun: true})
+
+	s.Require().NoError(err)
+	s.Equal(1, result.New)
+	s.Equal(1, result.Updated)
+	s.Zero(result.Published)
+	s.Equal([]domain.ArticleChange{
+		{ExternalID: 1, Action: domain.ActionCreated},
+		{ID: 200, ExternalID: 2, Action: domain.ActionUpdated, Diff: []domain.FieldChange{
+			{Field: "title", Old: []byte(`"Old title"`), New: []byte(`"New title"`)},
+		}},
+	}, result.Changes)
+}
+
+func (s *SyncServiceTestSuite) TestSyncWithOptions_DryRunCountsUnloadableUpdate() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{{SourceID: "test-source", ExternalID: 2, Title: "New title", PublishedAt: now, LastModified: now}}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"2"}).Return(
+		map[string]domain.ExistingArticle{"2": {ID: 200, LastModified: now.Add(-time.Hour)}}, nil,
+	)
+	s.articles.EXPECT().GetByID(ctx, int64(200)).Return(nil, errors.New("connection reset"))
+
+	result, err := s.service.SyncWithOptions(ctx, SyncOptions{DryRun: true})
+
+	s.Require().NoError(err)
+	s.Equal(1, result.Errors)
+	s.Zero(result.Updated)
+	s.Empty(result.Changes)
+}
+
 func (s *SyncServiceTestSuite) TestSync_SkipsOldArticles() {
-	ctx := context.Background()
+	ctx := syncContext()
 	now := time.Now()
 
 	articles := []domain.Article{
@@ -181,14 +477,13 @@ func (s *SyncServiceTestSuite) TestSync_SkipsOldArticles() {
 		},
 	}
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(articles, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
 
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(
-		map[int64]time.Time{1: now}, nil,
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(
+		map[string]domain.ExistingArticle{"1": {ID: 100, LastModified: now}}, nil,
 	)
 
-	s.syncState.EXPECT().Get(ctx, "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
-	s.syncState.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
 
 	stats, err := s.service.Sync(ctx)
 
@@ -200,7 +495,7 @@ func (s *SyncServiceTestSuite) TestSync_SkipsOldArticles() {
 }
 
 func (s *SyncServiceTestSuite) TestSync_FiltersOutdatedByDate() {
-	ctx := context.Background()
+	ctx := syncContext()
 	now := time.Now()
 	oldDate := now.AddDate(0, 0, -31)
 
@@ -214,10 +509,9 @@ func (s *SyncServiceTestSuite) TestSync_FiltersOutdatedByDate() {
 		},
 	}
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(articles, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
 
-	s.syncState.EXPECT().Get(ctx, "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
-	s.syncState.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
 
 	stats, err := s.service.Sync(ctx)
 
@@ -226,20 +520,318 @@ func (s *SyncServiceTestSuite) TestSync_FiltersOutdatedByDate() {
 	s.Equal(0, stats.New)
 }
 
+func (s *SyncServiceTestSuite) TestFilterByDate() {
+	now := time.Now()
+	articles := domain.Articles{
+		{ExternalID: 1, PublishedAt: now.AddDate(0, 0, -1)},
+		{ExternalID: 2, PublishedAt: now.AddDate(0, 0, -31)},
+		{ExternalID: 3, PublishedAt: now.AddDate(-10, 0, 0)},
+	}
+
+	filtered := filterByDate(articles, s.cfg, now)
+	s.Require().Len(filtered, 1)
+	s.Equal(int64(1), filtered[0].ExternalID)
+
+	// Disabled, nothing is filtered.
+	cfg := s.cfg
+	cfg.DisableDateFilter = true
+	s.Equal(articles, filterByDate(articles, cfg, now))
+}
+
 func (s *SyncServiceTestSuite) TestSync_SourceError() {
-	ctx := context.Background()
+	ctx := syncContext()
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(nil, errors.New("api error"))
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, errors.New("api error"))
 
 	stats, err := s.service.Sync(ctx)
 
 	s.Error(err)
 	s.Nil(stats)
 	s.Contains(err.Error(), "fetch articles")
+
+	var fetchErr *FetchError
+	s.Require().ErrorAs(err, &fetchErr)
+	s.Equal("test-source", fetchErr.SourceID)
 }
 
-func (s *SyncServiceTestSuite) TestSync_PublisherNil() {
-	ctx := context.Background()
+func (s *SyncServiceTestSuite) TestSync_ContextCarriesSourceID() {
+	var fetchSourceID, stateSourceID string
+	s.source.EXPECT().FetchArticles(gomock.Any(), s.cfg.MaxPagesPerSync, time.Time{}).DoAndReturn(
+		func(ctx context.Context, _ int, _ time.Time) ([]domain.Article, error) {
+			fetchSourceID = domain.SourceIDFromContext(ctx)
+			return nil, nil
+		},
+	)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, _ time.Time) error {
+			stateSourceID = domain.SourceIDFromContext(ctx)
+			return nil
+		},
+	)
+
+	_, err := s.service.Sync(context.Background())
+
+	s.NoError(err)
+	s.Equal("test-source", fetchSourceID)
+	s.Equal("test-source", stateSourceID)
+}
+
+func (s *SyncServiceTestSuite) TestSync_NoChangesOnlyTouchesLastSynced() {
+	ctx := syncContext()
+	start := time.Now()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil)
+	// Neither Get nor Update: a no-op sync doesn't rewrite the state.
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Cond(func(x any) bool {
+		t, ok := x.(time.Time)
+		return ok && !t.Before(start)
+	})).Return(nil)
+
+	_, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+}
+
+func (s *SyncServiceTestSuite) TestSync_InProgress() {
+	s.service.running.Store(true)
+
+	stats, err := s.service.Sync(context.Background())
+
+	s.Nil(stats)
+	s.ErrorIs(err, ErrSyncInProgress)
+}
+
+func (s *SyncServiceTestSuite) TestSync_SkipsUnchangedContent() {
+	ctx := syncContext()
+	now := time.Now()
+
+	// Re-sent with a newer lastModified, but the content is the same.
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "asd", PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, Title: "new title", PublishedAt: now, LastModified: now},
+	}
+	stored := articles[1]
+	stored.Title = "old title"
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2"}).Return(map[string]domain.ExistingArticle{
+		"1": {ID: 100, LastModified: now.Add(-time.Hour), ContentHash: articles[0].ContentHash()},
+		"2": {ID: 101, LastModified: now.Add(-time.Hour), ContentHash: stored.ContentHash()},
+	}, nil)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, &domain.Article{
+		SourceID: "test-source", ExternalID: 2, Title: "new title", PublishedAt: now, LastModified: now,
+	}).Return(int64(101), nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), false).Return(nil)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.Updated)
+	s.Equal(1, stats.Skipped)
+}
+
+func (s *SyncServiceTestSuite) TestSync_FilterStoreError() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "asd", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(nil, errors.New("db down"))
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Nil(stats)
+	var storeErr *StoreError
+	s.Require().ErrorAs(err, &storeErr)
+	s.Equal("filter for sync", storeErr.Op)
+}
+
+func (s *SyncServiceTestSuite) TestSync_UpdateSyncStateError() {
+	ctx := syncContext()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(errors.New("db down"))
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NotNil(stats)
+	var storeErr *StoreError
+	s.Require().ErrorAs(err, &storeErr)
+	s.Equal("update sync state", storeErr.Op)
+}
+
+func (s *SyncServiceTestSuite) TestSync_LastSuccessTimestamp() {
+	ctx := syncContext()
+	metrics.LastSuccessTimestamp.Reset()
+	gauge := metrics.LastSuccessTimestamp.WithLabelValues("test-source")
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil).Times(2)
+
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(errors.New("db down"))
+	_, err := s.service.Sync(ctx)
+	s.Error(err)
+	s.Zero(promtest.ToFloat64(gauge))
+
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+	_, err = s.service.Sync(ctx)
+	s.NoError(err)
+	s.InDelta(float64(time.Now().Unix()), promtest.ToFloat64(gauge), 2)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishErrorCounted() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "asd", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, &articles[0]).Return(int64(100), nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[0], true).Return(errors.New("broker down"))
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.Errors)
+	s.Equal(0, stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestPublish_ReturnsPublishError() {
+	ctx := syncContext()
+	article := &domain.Article{SourceID: "test-source", ExternalID: 42}
+
+	s.publisher.EXPECT().Publish(ctx, article, false).Return(errors.New("broker down"))
+
+	err := s.service.publish(ctx, article, false)
+
+	var publishErr *PublishError
+	s.Require().ErrorAs(err, &publishErr)
+	s.Equal(int64(42), publishErr.ExternalID)
+	s.EqualError(publishErr.Err, "broker down")
+}
+
+// memorySequences is a SequenceStore that keeps the sequence numbers in memory.
+type memorySequences map[string]int64
+
+func (m memorySequences) Next(_ context.Context, sourceID string) (int64, error) {
+	m[sourceID]++
+	return m[sourceID], nil
+}
+
+func (s *SyncServiceTestSuite) TestSync_StampsSequenceNumbers() {
+	sequences := memorySequences{}
+	s.service.SetSequenceStore(sequences)
+
+	var stamped []int64
+	publish := func(articles []domain.Article) {
+		ctx := syncContext()
+		s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+		s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+		s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(ctx)
+			},
+		).Times(2 * len(articles)) // saving and numbering each
+		s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil).Times(len(articles))
+		s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+			func(ctx context.Context, a *domain.Article, isNew bool) error {
+				stamped = append(stamped, a.Sequence)
+				return nil
+			},
+		).Times(len(articles))
+		s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+		s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := s.service.Sync(ctx)
+		s.Require().NoError(err)
+	}
+
+	publish(s.timelineArticles())
+	s.Equal([]int64{1, 2, 3}, stamped)
+
+	// The next run continues the numbering.
+	now := time.Now()
+	publish([]domain.Article{{SourceID: "test-source", ExternalID: 4, PublishedAt: now, LastModified: now}})
+	s.Equal([]int64{1, 2, 3, 4}, stamped)
+	s.Equal(int64(4), sequences["test-source"])
+}
+
+func (s *SyncServiceTestSuite) TestPublish_SequenceError() {
+	ctx := syncContext()
+	article := &domain.Article{SourceID: "test-source", ExternalID: 42}
+	sequences := mocks.NewMockSequenceStore(s.ctrl)
+	s.service.SetSequenceStore(sequences)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	sequences.EXPECT().Next(ctx, "test-source").Return(int64(0), errors.New("connection refused"))
+
+	err := s.service.publish(ctx, article, false)
+
+	var publishErr *PublishError
+	s.Require().ErrorAs(err, &publishErr)
+	s.Equal(int64(42), publishErr.ExternalID)
+	s.ErrorContains(err, "next sequence number: connection refused")
+	s.Zero(article.Sequence)
+}
+
+func (s *SyncServiceTestSuite) TestPublish_FailureRollsBackSequence() {
+	ctx := syncContext()
+	txCtx := context.WithValue(ctx, struct{}{}, "tx")
+	article := &domain.Article{SourceID: "test-source", ExternalID: 42}
+	sequences := mocks.NewMockSequenceStore(s.ctrl)
+	s.service.SetSequenceStore(sequences)
+
+	var txErr error
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			txErr = fn(txCtx)
+			return txErr
+		},
+	)
+	// Taken in the transaction, which the failed publish rolls back.
+	sequences.EXPECT().Next(txCtx, "test-source").Return(int64(7), nil)
+	s.publisher.EXPECT().Publish(ctx, article, false).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			s.Equal(int64(7), a.Sequence)
+			return errors.New("broker down")
+		},
+	)
+
+	err := s.service.publish(ctx, article, false)
+
+	s.ErrorContains(err, "broker down")
+	s.EqualError(txErr, "broker down")
+	s.Zero(article.Sequence)
+}
+
+func (s *SyncServiceTestSuite) TestSync_NullPublisher() {
+	ctx := syncContext()
 	now := time.Now()
 
 	service := NewSyncService(
@@ -247,8 +839,9 @@ func (s *SyncServiceTestSuite) TestSync_PublisherNil() {
 		s.articles,
 		s.tags,
 		s.syncState,
+		s.rawStore,
 		s.txManager,
-		nil,
+		publisher.NewNull(s.logger),
 		s.logger,
 		s.cfg,
 	)
@@ -263,9 +856,8 @@ func (s *SyncServiceTestSuite) TestSync_PublisherNil() {
 		},
 	}
 
-	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync).Return(articles, nil)
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(map[int64]time.Time{}, nil)
-	s.articles.EXPECT().GetExistingBySourceAndExternalIDs(ctx, "test-source", []int64{1}).Return(map[int64]time.Time{}, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
 
 	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
 		func(ctx context.Context, fn func(context.Context) error) error {
@@ -275,12 +867,1303 @@ func (s *SyncServiceTestSuite) TestSync_PublisherNil() {
 
 	s.articles.EXPECT().Upsert(ctx, &articles[0]).Return(int64(100), nil)
 
-	s.syncState.EXPECT().Get(ctx, "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
-	s.syncState.EXPECT().Update(ctx, gomock.Any()).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
 
 	stats, err := service.Sync(ctx)
 
 	s.NoError(err)
 	s.Equal(1, stats.New)
 	s.Equal(0, stats.Published)
-}
\ No newline at end of file
+}
+func (s *SyncServiceTestSuite) TestSync_SourceOverrides() {
+	ctx := syncContext()
+	published := time.Now().AddDate(0, 0, -45)
+
+	cfg := config.Config{
+		Sync: s.cfg,
+		Sources: []config.SourceConfig{
+			{ID: "test-source", MaxPagesPerSync: 12, MaxHistoricalDays: 60},
+		},
+	}
+	service := NewSyncService(
+		s.source,
+		s.articles,
+		s.tags,
+		s.syncState,
+		s.rawStore,
+		s.txManager,
+		s.publisher,
+		s.logger,
+		cfg.SyncFor("test-source"),
+	)
+
+	// Older than the global 30 days but within the source's 60.
+	articles := []domain.Article{
+		{
+			SourceID:     "test-source",
+			ExternalID:   1,
+			Title:        "asd",
+			PublishedAt:  published,
+			LastModified: published,
+		},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, 12, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+
+	s.articles.EXPECT().Upsert(ctx, &articles[0]).Return(int64(100), nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[0], true).Return(nil)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.Fetched)
+	s.Equal(1, stats.New)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental() {
+	ctx := syncContext()
+	lastSynced := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, lastSynced.Add(-incrementalOverlap)).Return(nil, nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+
+	_, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_FirstRun() {
+	ctx := syncContext()
+
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+
+	_, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_SyncStateError() {
+	ctx := syncContext()
+
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(nil, errors.New("db error"))
+
+	_, err := s.service.Sync(ctx)
+
+	var storeErr *StoreError
+	s.Require().ErrorAs(err, &storeErr)
+	s.Equal("get sync state", storeErr.Op)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_LooksUpExistingSince() {
+	ctx := syncContext()
+	now := time.Now()
+	lastSynced := now.Add(-time.Hour)
+	since := lastSynced.Add(-incrementalOverlap)
+
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	// 1 was stored since the last sync and is unchanged, 2 was stored before
+	// it and has been updated, 3 is new.
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, PublishedAt: now, LastModified: lastSynced},
+		{SourceID: "test-source", ExternalID: 2, PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 3, PublishedAt: now, LastModified: now},
+	}
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil).Times(2)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, since).Return(articles, nil)
+	s.articles.EXPECT().GetExistingSince(ctx, "test-source", []string{"1", "2", "3"}, since).Return(
+		map[string]domain.ExistingArticle{"1": {ID: 10, LastModified: lastSynced}}, nil,
+	)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"2", "3"}).Return(
+		map[string]domain.ExistingArticle{"2": {ID: 20, LastModified: now.Add(-48 * time.Hour)}}, nil,
+	)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(20), nil)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(30), nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), false).Return(nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.Updated)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_AllFoundSince() {
+	ctx := syncContext()
+	now := time.Now()
+	lastSynced := now.Add(-time.Hour)
+	since := lastSynced.Add(-incrementalOverlap)
+
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	articles := []domain.Article{{SourceID: "test-source", ExternalID: 1, PublishedAt: now, LastModified: lastSynced}}
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, since).Return(articles, nil)
+	// Nothing is left to look up without the bound.
+	s.articles.EXPECT().GetExistingSince(ctx, "test-source", []string{"1"}, since).Return(
+		map[string]domain.ExistingArticle{"1": {ID: 10, LastModified: lastSynced}}, nil,
+	)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.Skipped)
+}
+
+func (s *SyncServiceTestSuite) TestSync_DedupesBatch() {
+	ctx := syncContext()
+	now := time.Now()
+
+	// Article 1 appears on two pages; the second copy is newer.
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "old title", PublishedAt: now, LastModified: now.Add(-time.Hour)},
+		{SourceID: "test-source", ExternalID: 2, Title: "other", PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 1, Title: "new title", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2"}).Return(map[string]domain.ExistingArticle{}, nil)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+
+	var saved []string
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, a *domain.Article) (int64, error) {
+			saved = append(saved, a.Title)
+			return a.ExternalID, nil
+		},
+	).Times(2)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil).Times(2)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(2, stats.Fetched)
+	s.Equal(2, stats.New)
+	s.Equal([]string{"new title", "other"}, saved)
+}
+
+func (s *SyncServiceTestSuite) expectPublishOrder(articles []domain.Article) []int64 {
+	ctx := syncContext()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(len(articles))
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil).Times(len(articles))
+
+	var published []int64
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			published = append(published, a.ExternalID)
+			return nil
+		},
+	).Times(len(articles))
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := s.service.Sync(ctx)
+	s.Require().NoError(err)
+
+	return published
+}
+
+func (s *SyncServiceTestSuite) timelineArticles() []domain.Article {
+	now := time.Now()
+
+	// Fetch order as returned by the API: newest first.
+	return []domain.Article{
+		{SourceID: "test-source", ExternalID: 3, PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, PublishedAt: now.Add(-time.Hour), LastModified: now},
+		{SourceID: "test-source", ExternalID: 1, PublishedAt: now.Add(-2 * time.Hour), LastModified: now},
+	}
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishesOldestFirst() {
+	cfg := s.cfg
+	cfg.Order = config.OrderOldestFirst
+	s.service.SetConfig(cfg)
+
+	published := s.expectPublishOrder(s.timelineArticles())
+
+	s.Equal([]int64{1, 2, 3}, published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishesNewestFirst() {
+	articles := s.timelineArticles()
+	articles[0], articles[2] = articles[2], articles[0]
+
+	cfg := s.cfg
+	cfg.Order = config.OrderNewestFirst
+	s.service.SetConfig(cfg)
+
+	published := s.expectPublishOrder(articles)
+
+	s.Equal([]int64{3, 2, 1}, published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishedSequence() {
+	ctx := syncContext()
+	now := time.Now()
+	recorder := testutil.NewPublisher()
+	service := NewSyncService(
+		s.source,
+		s.articles,
+		s.tags,
+		s.syncState,
+		s.rawStore,
+		s.txManager,
+		recorder,
+		s.logger,
+		s.cfg,
+	)
+
+	// Fetched newest first; article 2 is already stored and has changed.
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 3, Title: "third", PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, Title: "second, edited", PublishedAt: now.Add(-time.Hour), LastModified: now},
+		{SourceID: "test-source", ExternalID: 1, Title: "first", PublishedAt: now.Add(-2 * time.Hour), LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(
+		map[string]domain.ExistingArticle{"2": {ID: 20, LastModified: now.Add(-time.Hour)}}, nil,
+	)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(3)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, a *domain.Article) (int64, error) {
+			return a.ExternalID * 10, nil
+		},
+	).Times(3)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(3, stats.Published)
+	s.Equal([]int64{1, 2, 3}, recorder.ExternalIDs())
+	published := recorder.Published()
+	s.Require().Len(published, 3)
+	s.Equal(testutil.Published{Article: articles[2], IsNew: true}, published[0])
+	s.Equal(testutil.Published{Article: articles[1], IsNew: false}, published[1])
+	s.Equal(testutil.Published{Article: articles[0], IsNew: true}, published[2])
+}
+
+func (s *SyncServiceTestSuite) TestSync_CapsArticlesPerSync() {
+	ctx := syncContext()
+	lastSynced := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	cfg := s.cfg
+	cfg.Order = config.OrderOldestFirst
+	cfg.MaxArticlesPerSync = 2
+	s.service.SetConfig(cfg)
+
+	articles := s.timelineArticles()
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil).Times(2)
+
+	var published []int64
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			published = append(published, a.ExternalID)
+			return nil
+		},
+	).Times(2)
+
+	// The sync time isn't advanced, so the deferred article is fetched again.
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, state *domain.SyncState) error {
+			s.Equal(lastSynced, state.LastSyncedAt)
+			s.Equal(int64(2), state.TotalSynced)
+			// The newest of the saved articles, not of the deferred one.
+			s.Require().NotNil(state.LastPublishedAt)
+			s.Equal(articles[1].PublishedAt, *state.LastPublishedAt)
+			return nil
+		},
+	)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal([]int64{1, 2}, published)
+	s.Equal(2, stats.New)
+	s.Equal(1, stats.Deferred)
+	s.Equal(0, stats.Skipped)
+}
+
+func (s *SyncServiceTestSuite) TestSync_StopsWhenCancelled() {
+	ctx, cancel := context.WithCancel(syncContext())
+	defer cancel()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(s.timelineArticles(), nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil)
+
+	// Cancelled while the first article is being published.
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			cancel()
+			return nil
+		},
+	)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.ErrorIs(err, context.Canceled)
+	s.Require().NotNil(stats)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.Published)
+	s.Equal(2, stats.Deferred)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PersistsSyncStateWhenCancelled() {
+	ctx, cancel := context.WithCancel(syncContext())
+	defer cancel()
+	metrics.LastSuccessTimestamp.Reset()
+	lastSynced := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(s.timelineArticles(), nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			cancel()
+			return nil
+		},
+	)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").DoAndReturn(
+		func(ctx context.Context, sourceID string) (*domain.SyncState, error) {
+			s.NoError(ctx.Err())
+			return &domain.SyncState{SourceID: sourceID, LastSyncedAt: lastSynced, TotalSynced: 10}, nil
+		},
+	)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, state *domain.SyncState) error {
+			s.NoError(ctx.Err())
+			s.Equal(int64(11), state.TotalSynced)
+			// Not advanced: the remaining articles still need fetching.
+			s.Equal(lastSynced, state.LastSyncedAt)
+			return nil
+		},
+	)
+
+	_, err := s.service.Sync(ctx)
+
+	s.ErrorIs(err, context.Canceled)
+	s.Zero(promtest.ToFloat64(metrics.LastSuccessTimestamp.WithLabelValues("test-source")), "an interrupted sync isn't a success")
+}
+
+func (s *SyncServiceTestSuite) TestSync_LastPublishedAtNeverGoesBackwards() {
+	ctx := syncContext()
+	newer := time.Now().Add(time.Hour)
+	articles := s.timelineArticles()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(3)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(1), nil).Times(3)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil).Times(3)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastPublishedAt: &newer}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, state *domain.SyncState) error {
+			s.Require().NotNil(state.LastPublishedAt)
+			s.Equal(newer, *state.LastPublishedAt)
+			return nil
+		},
+	)
+
+	_, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+}
+
+// expectSaveAll expects every article to be saved and published as new, and
+// returns the articles as they were upserted.
+func (s *SyncServiceTestSuite) expectSaveAll(ctx context.Context, articles []domain.Article, count int) *[]domain.Article {
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(count)
+
+	var saved []domain.Article
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, a *domain.Article) (int64, error) {
+			saved = append(saved, *a)
+			return a.ExternalID, nil
+		},
+	).Times(count)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil).Times(count)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	return &saved
+}
+
+func (s *SyncServiceTestSuite) TestSync_AppliesEnrichersInOrder() {
+	ctx := syncContext()
+	first := mocks.NewMockEnricher(s.ctrl)
+	second := mocks.NewMockEnricher(s.ctrl)
+	s.service.SetEnrichers([]Enricher{first, second}, false)
+
+	gomock.InOrder(
+		first.EXPECT().Enrich(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, a *domain.Article) error {
+			a.Category = "first"
+			return nil
+		}),
+		second.EXPECT().Enrich(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, a *domain.Article) error {
+			a.Category += ",second"
+			return nil
+		}),
+	)
+
+	saved := s.expectSaveAll(ctx, s.timelineArticles()[:1], 1)
+
+	_, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Require().Len(*saved, 1)
+	s.Equal("first,second", (*saved)[0].Category)
+}
+
+func (s *SyncServiceTestSuite) TestSync_EnrichErrorLogged() {
+	ctx := syncContext()
+	failing := mocks.NewMockEnricher(s.ctrl)
+	next := mocks.NewMockEnricher(s.ctrl)
+	s.service.SetEnrichers([]Enricher{failing, next}, false)
+
+	failing.EXPECT().Enrich(ctx, gomock.Any()).Return(errors.New("classifier down")).Times(3)
+
+	saved := s.expectSaveAll(ctx, s.timelineArticles(), 3)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Len(*saved, 3)
+	s.Equal(3, stats.New)
+	s.Equal(0, stats.Errors)
+}
+
+func (s *SyncServiceTestSuite) TestSync_EnrichErrorDrops() {
+	ctx := syncContext()
+	enricher := mocks.NewMockEnricher(s.ctrl)
+	s.service.SetEnrichers([]Enricher{enricher}, true)
+
+	enricher.EXPECT().Enrich(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, a *domain.Article) error {
+		if a.ExternalID == 2 {
+			return errors.New("unsupported")
+		}
+		return nil
+	}).Times(3)
+
+	saved := s.expectSaveAll(ctx, s.timelineArticles(), 2)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 3}, domain.Articles(*saved).ExternalIDs())
+	s.Equal(3, stats.Fetched)
+	s.Equal(2, stats.New)
+	s.Equal(1, stats.Errors)
+	s.Equal(0, stats.Skipped)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishFilterSuppresses() {
+	ctx := syncContext()
+	s.service.SetPublishFilter(func(a *domain.Article) bool {
+		return a.ExternalID != 2
+	})
+
+	articles := s.timelineArticles()
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", gomock.Any()).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(3)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, a *domain.Article) (int64, error) {
+			return a.ExternalID, nil
+		},
+	).Times(3)
+
+	var published []int64
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			published = append(published, a.ExternalID)
+			return nil
+		},
+	).Times(2)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 3}, published)
+	s.Equal(3, stats.New)
+	s.Equal(2, stats.Published)
+	s.Equal(1, stats.Suppressed)
+}
+
+func (s *SyncServiceTestSuite) withFailureStore(quarantineAfter int) *mocks.MockFailureStore {
+	cfg := s.cfg
+	cfg.QuarantineAfter = quarantineAfter
+	s.service.SetConfig(cfg)
+
+	failures := mocks.NewMockFailureStore(s.ctrl)
+	s.service.SetFailureStore(failures)
+	return failures
+}
+
+func (s *SyncServiceTestSuite) TestSync_RecordsFailedSave() {
+	ctx := syncContext()
+	failures := s.withFailureStore(3)
+	articles := s.timelineArticles()[:1]
+	saveErr := errors.New("check constraint violated")
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	failures.EXPECT().Failing(ctx, "test-source").Return(map[string]bool{}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).Return(saveErr)
+	failures.EXPECT().RecordFailure(ctx, gomock.Any(), gomock.Any(), 3).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, cause error, maxAttempts int) (bool, error) {
+			s.Equal(int64(3), a.ExternalID)
+			s.ErrorIs(cause, saveErr)
+			return false, nil
+		},
+	)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.Errors)
+	s.Equal(0, stats.Quarantined)
+}
+
+func (s *SyncServiceTestSuite) TestSync_SkipsQuarantined() {
+	ctx := syncContext()
+	failures := s.withFailureStore(3)
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(s.timelineArticles(), nil)
+	failures.EXPECT().Failing(ctx, "test-source").Return(map[string]bool{"2": true, "42": true}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3", "1"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+
+	var saved []int64
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, a *domain.Article) (int64, error) {
+			saved = append(saved, a.ExternalID)
+			return a.ExternalID, nil
+		},
+	).Times(2)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil).Times(2)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal([]int64{1, 3}, saved)
+	s.Equal(3, stats.Fetched)
+	s.Equal(1, stats.Quarantined)
+	s.Equal(2, stats.New)
+}
+
+func (s *SyncServiceTestSuite) TestSync_ClearsFailuresAfterSave() {
+	ctx := syncContext()
+	failures := s.withFailureStore(3)
+
+	saved := s.expectSaveAll(ctx, s.timelineArticles(), 3)
+	failures.EXPECT().Failing(ctx, "test-source").Return(map[string]bool{"2": false}, nil)
+	failures.EXPECT().Clear(ctx, "test-source", "2").Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Len(*saved, 3)
+	s.Equal(0, stats.Quarantined)
+}
+
+func (s *SyncServiceTestSuite) TestSync_RetriesUntilQuarantined() {
+	ctx := syncContext()
+	failures := s.withFailureStore(2)
+	articles := s.timelineArticles()[:1]
+	saveErr := errors.New("check constraint violated")
+
+	// Stands in for the failed_articles table.
+	attempts := 0
+	failures.EXPECT().Failing(ctx, "test-source").DoAndReturn(
+		func(ctx context.Context, sourceID string) (map[string]bool, error) {
+			if attempts == 0 {
+				return map[string]bool{}, nil
+			}
+			return map[string]bool{"3": attempts >= 2}, nil
+		},
+	).Times(3)
+	failures.EXPECT().RecordFailure(ctx, gomock.Any(), gomock.Any(), 2).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, cause error, maxAttempts int) (bool, error) {
+			attempts++
+			return attempts >= maxAttempts, nil
+		},
+	).Times(2)
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil).Times(3)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil).Times(2)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).Return(saveErr).Times(2)
+	// Only the sync skipping the quarantined article moves the sync time.
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+
+	for i := 0; i < 2; i++ {
+		stats, err := s.service.Sync(ctx)
+		s.Require().NoError(err)
+		s.Equal(1, stats.Errors)
+	}
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(0, stats.Errors)
+	s.Equal(1, stats.Quarantined)
+}
+
+func (s *SyncServiceTestSuite) TestSync_StageDurations() {
+	ctx := syncContext()
+	articles := s.timelineArticles()[:1]
+	const delay = 5 * time.Millisecond
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).DoAndReturn(
+		func(ctx context.Context, maxPages int, since time.Time) ([]domain.Article, error) {
+			time.Sleep(delay)
+			return articles, nil
+		},
+	)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			time.Sleep(delay)
+			return nil
+		},
+	)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).DoAndReturn(
+		func(ctx context.Context, a *domain.Article, isNew bool) error {
+			time.Sleep(delay)
+			return nil
+		},
+	)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.GreaterOrEqual(stats.FetchDuration, delay)
+	s.GreaterOrEqual(stats.PersistDuration, delay)
+	s.GreaterOrEqual(stats.PublishDuration, delay)
+	s.GreaterOrEqual(stats.Duration, stats.FetchDuration+stats.PersistDuration+stats.PublishDuration)
+}
+
+func (s *SyncServiceTestSuite) TestSync_SavesRawPayload() {
+	ctx := syncContext()
+	now := time.Now()
+
+	articles := []domain.Article{
+		{
+			SourceID:     "test-source",
+			ExternalID:   1,
+			Title:        "asd",
+			PublishedAt:  now,
+			LastModified: now,
+			Raw:          []byte(`{"id":1}`),
+		},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{}, nil)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+
+	s.articles.EXPECT().Upsert(ctx, &articles[0]).Return(int64(100), nil)
+	s.rawStore.EXPECT().Save(ctx, &articles[0]).Return(nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[0], true).Return(nil)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.New)
+	s.Equal(0, stats.Errors)
+}
+
+func (s *SyncServiceTestSuite) TestSync_KeyedArticlesWithSameExternalID() {
+	ctx := syncContext()
+	now := time.Now()
+
+	// Two keys deriving the same ExternalID are still different articles.
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: -1, ExternalKey: "item-1", Title: "stored", PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: -1, ExternalKey: "item-2", Title: "new", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"item-1", "item-2"}).Return(map[string]domain.ExistingArticle{
+		"item-1": {ID: 100, LastModified: now, ContentHash: articles[0].ContentHash()},
+	}, nil)
+
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, &articles[1]).Return(int64(101), nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[1], true).Return(nil)
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(2, stats.Fetched)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.Skipped)
+}
+
+// announcingPublisher is a publisher that also announces completed syncs.
+type announcingPublisher struct {
+	*mocks.MockPublisher
+	*mocks.MockSyncCompletedPublisher
+}
+
+// withAnnouncingPublisher rebuilds the service around a publisher that
+// announces completed syncs and returns its announcement mock.
+func (s *SyncServiceTestSuite) withAnnouncingPublisher() *mocks.MockSyncCompletedPublisher {
+	announcer := mocks.NewMockSyncCompletedPublisher(s.ctrl)
+	s.service = NewSyncService(
+		s.source,
+		s.articles,
+		s.tags,
+		s.syncState,
+		s.rawStore,
+		s.txManager,
+		announcingPublisher{s.publisher, announcer},
+		s.logger,
+		s.cfg,
+	)
+	return announcer
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishesSyncCompleted() {
+	ctx := syncContext()
+	announcer := s.withAnnouncingPublisher()
+	articles := s.timelineArticles()[:1]
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"3"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).Return(nil)
+	s.publisher.EXPECT().Publish(ctx, gomock.Any(), true).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	var announced *domain.SyncStats
+	announcer.EXPECT().PublishSyncCompleted(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, stats *domain.SyncStats) error {
+			announced = stats
+			return errors.New("broker unavailable")
+		},
+	)
+
+	stats, err := s.service.Sync(ctx)
+
+	// A failed announcement doesn't fail the sync.
+	s.Require().NoError(err)
+	s.Equal(stats, announced)
+	s.Equal(1, announced.New)
+}
+
+func (s *SyncServiceTestSuite) TestSync_NoSyncCompletedOnFailure() {
+	ctx := syncContext()
+	s.withAnnouncingPublisher()
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, errors.New("api down"))
+
+	_, err := s.service.Sync(ctx)
+
+	s.Error(err)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PausedSourceIsNotFetched() {
+	ctx := syncContext()
+	pauses := mocks.NewMockPauseStore(s.ctrl)
+	s.service.SetPauseStore(pauses)
+
+	pauses.EXPECT().SetPaused(ctx, "test-source", true).Return(nil)
+	s.Require().NoError(s.service.Pause(ctx))
+	s.True(s.service.Paused())
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Nil(stats)
+	s.ErrorIs(err, ErrSourcePaused)
+
+	pauses.EXPECT().SetPaused(ctx, "test-source", false).Return(nil)
+	s.Require().NoError(s.service.Resume(ctx))
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil)
+
+	_, err = s.service.Sync(ctx)
+	s.NoError(err)
+}
+
+func (s *SyncServiceTestSuite) TestPause_PersistErrorStillPauses() {
+	ctx := syncContext()
+	pauses := mocks.NewMockPauseStore(s.ctrl)
+	s.service.SetPauseStore(pauses)
+
+	pauses.EXPECT().SetPaused(ctx, "test-source", true).Return(errors.New("db down"))
+
+	var storeErr *StoreError
+	s.ErrorAs(s.service.Pause(ctx), &storeErr)
+	s.True(s.service.Paused())
+}
+
+func (s *SyncServiceTestSuite) TestRestorePaused() {
+	ctx := syncContext()
+	pauses := mocks.NewMockPauseStore(s.ctrl)
+	s.service.SetPauseStore(pauses)
+
+	pauses.EXPECT().IsPaused(ctx, "test-source").Return(true, nil)
+
+	s.Require().NoError(s.service.RestorePaused(ctx))
+	s.True(s.service.Paused())
+
+	_, err := s.service.Sync(ctx)
+	s.ErrorIs(err, ErrSourcePaused)
+}
+
+func (s *SyncServiceTestSuite) TestSync_RecordsHealth() {
+	ctx := syncContext()
+	health := NewHealthTracker(nil, s.logger)
+	s.service.SetHealthTracker(health)
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, errors.New("api down"))
+	_, err := s.service.Sync(ctx)
+	s.Error(err)
+
+	h, ok := health.Get("test-source")
+	s.Require().True(ok)
+	s.Equal(1, h.ConsecutiveFailures)
+	s.Contains(h.LastError, "api down")
+
+	// A paused source isn't synced, so it isn't recorded either.
+	s.Require().NoError(s.service.Pause(ctx))
+	_, err = s.service.Sync(ctx)
+	s.ErrorIs(err, ErrSourcePaused)
+	h, _ = health.Get("test-source")
+	s.Equal(1, h.Runs)
+}
+
+// checkedSource is a source that implements HealthChecker, failing with err.
+type checkedSource struct {
+	*mocks.MockSource
+	err    error
+	checks int
+}
+
+func (c *checkedSource) Healthcheck(ctx context.Context) error {
+	c.checks++
+	return c.err
+}
+
+func (s *SyncServiceTestSuite) TestHealthcheck_RecordsOutcome() {
+	ctx := context.Background()
+	source := &checkedSource{MockSource: s.source}
+	service := NewSyncService(source, s.articles, s.tags, s.syncState, s.rawStore, s.txManager, s.publisher, s.logger, s.cfg)
+	health := NewHealthTracker(nil, s.logger)
+	service.SetHealthTracker(health)
+
+	s.NoError(service.Healthcheck(ctx))
+	h, ok := health.Get("test-source")
+	s.Require().True(ok)
+	s.Require().NotNil(h.LastCheckAt)
+	s.Empty(h.LastCheckError)
+
+	source.err = errors.New("api down")
+	service.checkedAt = time.Now().Add(-healthcheckInterval)
+	s.EqualError(service.Healthcheck(ctx), "api down")
+	h, _ = health.Get("test-source")
+	s.Equal("api down", h.LastCheckError)
+	// Checks aren't syncs.
+	s.Zero(h.Runs)
+}
+
+func (s *SyncServiceTestSuite) TestHealthcheck_ReusesRecentOutcome() {
+	ctx := context.Background()
+	source := &checkedSource{MockSource: s.source, err: errors.New("api down")}
+	service := NewSyncService(source, s.articles, s.tags, s.syncState, s.rawStore, s.txManager, s.publisher, s.logger, s.cfg)
+
+	for range 3 {
+		s.EqualError(service.Healthcheck(ctx), "api down")
+	}
+	s.Equal(1, source.checks)
+
+	// Once it is healthcheckInterval old, the upstream is checked again.
+	source.err = nil
+	service.checkedAt = time.Now().Add(-healthcheckInterval)
+	s.NoError(service.Healthcheck(ctx))
+	s.Equal(2, source.checks)
+}
+
+func (s *SyncServiceTestSuite) TestHealthcheck_CancelledCheckNotReused() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	source := &checkedSource{MockSource: s.source, err: context.Canceled}
+	service := NewSyncService(source, s.articles, s.tags, s.syncState, s.rawStore, s.txManager, s.publisher, s.logger, s.cfg)
+
+	s.ErrorIs(service.Healthcheck(ctx), context.Canceled)
+	source.err = nil
+	s.NoError(service.Healthcheck(context.Background()))
+	s.Equal(2, source.checks)
+}
+
+func (s *SyncServiceTestSuite) TestHealthcheck_SourceWithoutCheckIsHealthy() {
+	health := NewHealthTracker(nil, s.logger)
+	s.service.SetHealthTracker(health)
+
+	s.NoError(s.service.Healthcheck(context.Background()))
+	_, ok := health.Get("test-source")
+	s.False(ok)
+}
+
+func (s *SyncServiceTestSuite) TestSync_QuietPeriodDefersRecentUpdates() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	now := time.Now()
+	oldTime := now.Add(-time.Hour)
+	articles := []domain.Article{
+		// Updates just inside and just outside the window.
+		{SourceID: "test-source", ExternalID: 1, Title: "flapping", PublishedAt: oldTime, LastModified: now.Add(-50 * time.Second)},
+		{SourceID: "test-source", ExternalID: 2, Title: "settled", PublishedAt: oldTime, LastModified: now.Add(-70 * time.Second)},
+		// New articles are published right away.
+		{SourceID: "test-source", ExternalID: 3, Title: "new", PublishedAt: oldTime, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2", "3"}).Return(map[string]domain.ExistingArticle{
+		"1": {ID: 101, LastModified: oldTime},
+		"2": {ID: 102, LastModified: oldTime},
+	}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(3)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(101), nil)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(102), nil)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(103), nil)
+	s.articles.EXPECT().SetPublishPending(ctx, int64(101), true).Return(nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[1], false).Return(nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[2], true).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.PublishDeferred)
+	s.Equal(2, stats.Published)
+	s.Equal(2, stats.Updated)
+	s.Equal(1, stats.New)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PublishesPendingUpdateOnceSettled() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	modified := time.Now().Add(-2 * time.Minute)
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "flapping", PublishedAt: modified, LastModified: modified},
+	}
+
+	// Unchanged since the sync that deferred it, but still pending.
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{
+		"1": {ID: 101, LastModified: modified, ContentHash: articles[0].ContentHash(), PublishPending: true},
+	}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(101), nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[0], false).Return(nil)
+	s.articles.EXPECT().SetPublishPending(ctx, int64(101), false).Return(nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Zero(stats.PublishDeferred)
+	s.Equal(1, stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PendingUpdateStaysPendingWhenPublishFails() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	modified := time.Now().Add(-2 * time.Minute)
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "flapping", PublishedAt: modified, LastModified: modified},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{
+		"1": {ID: 101, LastModified: modified, ContentHash: articles[0].ContentHash(), PublishPending: true},
+	}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(101), nil)
+	s.publisher.EXPECT().Publish(ctx, &articles[0], false).Return(errors.New("broker down"))
+	// No SetPublishPending: the next sync retries the publish.
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.Errors)
+	s.Zero(stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PendingUpdateStillWithinQuietPeriod() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	now := time.Now()
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "flapping again", PublishedAt: now, LastModified: now},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(articles, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1"}).Return(map[string]domain.ExistingArticle{
+		"1": {ID: 101, LastModified: now.Add(-30 * time.Second), PublishPending: true},
+	}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	)
+	// Already pending, so neither published nor marked again.
+	s.articles.EXPECT().Upsert(ctx, gomock.Any()).Return(int64(101), nil)
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil)
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.NoError(err)
+	s.Equal(1, stats.PublishDeferred)
+	s.Zero(stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_PublishesPendingUpdateNotFetched() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.Incremental = true
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	now := time.Now()
+	lastSynced := now.Add(-time.Minute)
+	// Deferred by the last sync; not modified since, so not fetched again.
+	s.pending = []domain.Article{
+		{ID: 101, SourceID: "test-source", ExternalID: 1, Title: "settled", PublishedAt: now, LastModified: now.Add(-2 * time.Minute)},
+		{ID: 102, SourceID: "test-source", ExternalID: 2, Title: "still flapping", PublishedAt: now, LastModified: now.Add(-30 * time.Second)},
+	}
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil).AnyTimes()
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, lastSynced.Add(-incrementalOverlap)).Return(nil, nil)
+	s.publisher.EXPECT().Publish(ctx, &s.pending[0], false).Return(nil)
+	s.articles.EXPECT().SetPublishPending(ctx, int64(101), false).Return(nil)
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil).AnyTimes()
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.Published)
+	s.Equal(1, stats.PublishDeferred)
+}
+
+func (s *SyncServiceTestSuite) TestSync_PendingUpdatePublishFailsStaysPending() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.QuietPeriod = time.Minute
+	s.service.SetConfig(cfg)
+
+	now := time.Now()
+	s.pending = []domain.Article{
+		{ID: 101, SourceID: "test-source", ExternalID: 1, Title: "settled", PublishedAt: now, LastModified: now.Add(-2 * time.Minute)},
+	}
+
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, time.Time{}).Return(nil, nil)
+	// Not cleared, so the next sync tries again.
+	s.publisher.EXPECT().Publish(ctx, &s.pending[0], false).Return(errors.New("connection reset"))
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source"}, nil).AnyTimes()
+	s.syncState.EXPECT().TouchLastSynced(gomock.Any(), "test-source", gomock.Any()).Return(nil).AnyTimes()
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.Errors)
+	s.Zero(stats.Published)
+}
+
+func (s *SyncServiceTestSuite) TestSync_Incremental_FailedSaveKeepsLastSynced() {
+	ctx := syncContext()
+	cfg := s.cfg
+	cfg.Incremental = true
+	s.service.SetConfig(cfg)
+
+	now := time.Now()
+	lastSynced := now.Add(-time.Hour)
+	articles := []domain.Article{
+		{SourceID: "test-source", ExternalID: 1, Title: "saved", PublishedAt: now, LastModified: now},
+		{SourceID: "test-source", ExternalID: 2, Title: "failing", PublishedAt: now, LastModified: now},
+	}
+
+	s.syncState.EXPECT().Get(gomock.Any(), "test-source").Return(&domain.SyncState{SourceID: "test-source", LastSyncedAt: lastSynced}, nil).Times(2)
+	s.source.EXPECT().FetchArticles(ctx, s.cfg.MaxPagesPerSync, lastSynced.Add(-incrementalOverlap)).Return(articles, nil)
+	s.articles.EXPECT().GetExistingSince(ctx, "test-source", []string{"1", "2"}, lastSynced.Add(-incrementalOverlap)).Return(map[string]domain.ExistingArticle{}, nil)
+	s.articles.EXPECT().GetExisting(ctx, "test-source", []string{"1", "2"}).Return(map[string]domain.ExistingArticle{}, nil)
+	s.txManager.EXPECT().WithTransaction(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		},
+	).Times(2)
+	s.articles.EXPECT().Upsert(ctx, &articles[0]).Return(int64(1), nil)
+	s.articles.EXPECT().Upsert(ctx, &articles[1]).Return(int64(0), errors.New("check constraint violated"))
+	s.publisher.EXPECT().Publish(ctx, &articles[0], true).Return(nil)
+
+	// The sync time isn't advanced, so the failed article is fetched again.
+	s.syncState.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, state *domain.SyncState) error {
+			s.Equal(lastSynced, state.LastSyncedAt)
+			s.Equal(int64(1), state.TotalSynced)
+			return nil
+		},
+	)
+
+	stats, err := s.service.Sync(ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, stats.New)
+	s.Equal(1, stats.Errors)
+}
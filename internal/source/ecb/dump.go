@@ -0,0 +1,23 @@
+package ecb
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// dumpResponse writes a fetched response body to a new file in the dump
+// directory, named after the time it was fetched. Failing to is only logged,
+// as the dump is a debugging aid.
+func (s *Source) dumpResponse(url string, body []byte) {
+	n := s.dumped.Add(1)
+	name := fmt.Sprintf("%s-%s-%d.json", SourceID, time.Now().UTC().Format("20060102T150405.000000000Z"), n)
+	path := filepath.Join(s.dumpDir, name)
+
+	if err := os.WriteFile(path, body, 0o644); err != nil {
+		s.logger.Warn("failed to dump response", "url", url, "error", err)
+		return
+	}
+	s.logger.Debug("dumped response", "url", url, "path", path)
+}
@@ -1,9 +1,16 @@
 package ecb
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // APIResponse represents the ECB API response structure.
 type APIResponse struct {
 	PageInfo PageInfo  `json:"pageInfo"`
 	Content  []Content `json:"content"`
+	// NextCursor is set by cursor-paginated APIs; empty on the last page.
+	NextCursor string `json:"nextCursor"`
 }
 
 type PageInfo struct {
@@ -26,6 +33,30 @@ type Content struct {
 	Author       *string    `json:"author"`
 	Duration     int        `json:"duration"`
 	LastModified int64      `json:"lastModified"`
+
+	raw json.RawMessage
+}
+
+// lastModifiedAt returns the modification time. The API omits lastModified for
+// some content; the publication date is used instead, so such articles compare
+// sensibly with stored versions rather than looking modified in 1970.
+func (c Content) lastModifiedAt(loc *time.Location) time.Time {
+	if c.LastModified > 0 {
+		return time.UnixMilli(c.LastModified).UTC()
+	}
+	publishedAt, _ := parseContentDate(c.Date, loc)
+	return publishedAt
+}
+
+// UnmarshalJSON decodes the content and keeps its raw JSON so it can be stored
+// and re-transformed later.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	type content Content
+	if err := json.Unmarshal(data, (*content)(c)); err != nil {
+		return err
+	}
+	c.raw = append(json.RawMessage(nil), data...)
+	return nil
 }
 
 type APITag struct {
@@ -34,5 +65,13 @@ type APITag struct {
 }
 
 type LeadMedia struct {
-	ImageURL string `json:"imageUrl"`
+	ImageURL string         `json:"imageUrl"`
+	Variants []ImageVariant `json:"variants"`
+}
+
+// ImageVariant is one rendition of the lead image.
+type ImageVariant struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }
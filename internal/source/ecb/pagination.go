@@ -0,0 +1,115 @@
+package ecb
+
+import (
+	"log/slog"
+	"net/url"
+	"strconv"
+)
+
+const (
+	// PaginationPage requests numbered pages and stops using PageInfo.
+	PaginationPage = "page"
+	// PaginationCursor passes each response's nextCursor to the next request
+	// and stops when it is empty.
+	PaginationCursor = "cursor"
+
+	defaultCursorParam = "cursor"
+)
+
+// paginator decides which page to request next. A new one is used for every
+// fetch.
+type paginator interface {
+	// first returns the query of the first page.
+	first() url.Values
+	// next returns the query of the page after resp, the fetched-th page of
+	// this fetch (counting from 0), or false if resp was the last page.
+	next(resp *APIResponse, fetched int) (url.Values, bool)
+}
+
+func (s *Source) newPaginator() paginator {
+	if s.pagination == PaginationCursor {
+		return &cursorPaginator{pageSize: s.pageSize, param: s.cursorParam}
+	}
+	return &pagePaginator{
+		pageSize:  s.pageSize,
+		startPage: s.startPage,
+		pageStep:  s.pageStep,
+		logger:    s.logger,
+	}
+}
+
+// pagePaginator requests pages from startPage in steps of pageStep. NumPages is
+// counted from the first page requested.
+type pagePaginator struct {
+	pageSize  int
+	startPage int
+	pageStep  int
+	logger    *slog.Logger
+
+	warned bool
+}
+
+func (p *pagePaginator) first() url.Values {
+	return p.query(p.startPage)
+}
+
+func (p *pagePaginator) next(resp *APIResponse, fetched int) (url.Values, bool) {
+	if !p.warned && !pageInfoConsistent(resp.PageInfo, len(resp.Content)) {
+		p.warned = true
+		p.logger.Warn("inconsistent page info",
+			"page", p.startPage+fetched*p.pageStep,
+			"num_pages", resp.PageInfo.NumPages,
+			"num_entries", resp.PageInfo.NumEntries,
+			"page_size", resp.PageInfo.PageSize,
+			"content", len(resp.Content),
+		)
+	}
+
+	if numPages := resp.PageInfo.NumPages; numPages > 0 && fetched >= numPages-1 {
+		return nil, false
+	}
+	return p.query(p.startPage + (fetched+1)*p.pageStep), true
+}
+
+func (p *pagePaginator) query(page int) url.Values {
+	return url.Values{
+		"pageSize": {strconv.Itoa(p.pageSize)},
+		"page":     {strconv.Itoa(page)},
+	}
+}
+
+// pageInfoConsistent reports whether info agrees with itself and with the
+// number of contents on the page.
+func pageInfoConsistent(info PageInfo, contents int) bool {
+	if info.NumPages <= 0 {
+		// An empty result may legitimately report no pages.
+		return info.NumPages == 0 && info.NumEntries == 0 && contents == 0
+	}
+	if contents == 0 && info.NumEntries > 0 {
+		return false
+	}
+	if info.PageSize > 0 && info.NumEntries > 0 {
+		return info.NumPages == (info.NumEntries+info.PageSize-1)/info.PageSize
+	}
+	return true
+}
+
+// cursorPaginator passes the nextCursor of each response as the param query
+// parameter of the next request.
+type cursorPaginator struct {
+	pageSize int
+	param    string
+}
+
+func (p *cursorPaginator) first() url.Values {
+	return url.Values{"pageSize": {strconv.Itoa(p.pageSize)}}
+}
+
+func (p *cursorPaginator) next(resp *APIResponse, _ int) (url.Values, bool) {
+	if resp.NextCursor == "" {
+		return nil, false
+	}
+	query := p.first()
+	query.Set(p.param, resp.NextCursor)
+	return query, true
+}
@@ -1,11 +1,18 @@
 package ecb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"news_fetcher/internal/domain"
@@ -14,8 +21,21 @@ import (
 const (
 	SourceID   = "ecb"
 	SourceName = "ECB Cricket"
+
+	// ZeroIDTagsSkip drops tags without an ID, ZeroIDTagsHash gives them
+	// domain.SyntheticTagID of their label.
+	ZeroIDTagsSkip = "skip"
+	ZeroIDTagsHash = "hash"
+
+	mediaTypeImage = "image"
+
+	defaultMaxResponseBytes = 10 << 20
 )
 
+// ErrResponseTooLarge is returned when a response body exceeds the
+// configured maximum size.
+var ErrResponseTooLarge = errors.New("response too large")
+
 // Config holds ECB source configuration.
 type Config struct {
 	BaseURL        string
@@ -25,33 +45,134 @@ type Config struct {
 	MaxAttempts    int
 	InitialBackoff time.Duration
 	MaxBackoff     time.Duration
+	// Pagination is PaginationPage (default) or PaginationCursor.
+	Pagination string
+	// StartPage is the first page requested (0 by default). PageStep is added
+	// for each following page (1 by default). Page pagination only.
+	StartPage int
+	PageStep  int
+	// CursorParam is the query parameter carrying the cursor ("cursor" by
+	// default). Cursor pagination only.
+	CursorParam string
+	// MaxZeroIDRatio and MaxEmptyTitleRatio are the share of records on a page
+	// that may decode without an ID or a title before the page is rejected.
+	// Nil means the default of 0.5; 0 rejects any such record and 1 disables
+	// the check.
+	MaxZeroIDRatio     *float64
+	MaxEmptyTitleRatio *float64
+	// CategoryTags maps tag labels to article categories. A matching tag takes
+	// precedence over the category derived from the content.
+	CategoryTags map[string]string
+	// Location is used for dates without an offset. Nil means UTC.
+	Location *time.Location
+	// AcceptLanguage, if set, is sent as the Accept-Language header, and its
+	// first language is recorded as the language of the fetched articles.
+	AcceptLanguage string
+	// FetchConcurrency is how many pages are fetched at once once the first
+	// page reports how many there are. 0 or 1 fetches them one by one. Page
+	// pagination only.
+	FetchConcurrency int
+	// ZeroIDTags is what happens to tags sent with ID 0 or none:
+	// ZeroIDTagsSkip (default) or ZeroIDTagsHash.
+	ZeroIDTags string
+	// MaxResponseBytes caps the size of a response body; larger responses
+	// fail with ErrResponseTooLarge. Zero means 10MB.
+	MaxResponseBytes int64
+	// DumpDir, if set, is a directory every fetched response body is written
+	// to, for debugging.
+	DumpDir string
 }
 
 // Source implements source.Source for ECB Cricket API.
 type Source struct {
-	httpClient     *http.Client
-	baseURL        string
-	pageSize       int
-	pageDelay      time.Duration
-	maxAttempts    int
-	initialBackoff time.Duration
-	maxBackoff     time.Duration
-	logger         *slog.Logger
+	httpClient         *http.Client
+	baseURL            string
+	pageSize           int
+	pageDelay          time.Duration
+	pagination         string
+	startPage          int
+	pageStep           int
+	cursorParam        string
+	maxZeroIDRatio     float64
+	maxEmptyTitleRatio float64
+	maxAttempts        int
+	initialBackoff     time.Duration
+	maxBackoff         time.Duration
+	categoryTags       map[string]string
+	location           *time.Location
+	acceptLanguage     string
+	language           string
+	fetchConcurrency   int
+	zeroIDTags         string
+	maxResponseBytes   int64
+	dumpDir            string
+	dumped             atomic.Int64 // responses dumped, numbering the files
+	logger             *slog.Logger
 }
 
 // New creates a new ECB source.
 func New(cfg Config, logger *slog.Logger) *Source {
+	location := cfg.Location
+	if location == nil {
+		location = time.UTC
+	}
+	pageStep := cfg.PageStep
+	if pageStep <= 0 {
+		pageStep = 1
+	}
+	cursorParam := cfg.CursorParam
+	if cursorParam == "" {
+		cursorParam = defaultCursorParam
+	}
+	maxZeroIDRatio := defaultMaxZeroIDRatio
+	if cfg.MaxZeroIDRatio != nil {
+		maxZeroIDRatio = *cfg.MaxZeroIDRatio
+	}
+	maxEmptyTitleRatio := defaultMaxEmptyTitleRatio
+	if cfg.MaxEmptyTitleRatio != nil {
+		maxEmptyTitleRatio = *cfg.MaxEmptyTitleRatio
+	}
+	maxResponseBytes := cfg.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = defaultMaxResponseBytes
+	}
+
+	switch cfg.Pagination {
+	case "", PaginationPage, PaginationCursor:
+	default:
+		logger.Warn("unknown pagination, using page", "pagination", cfg.Pagination)
+	}
+	switch cfg.ZeroIDTags {
+	case "", ZeroIDTagsSkip, ZeroIDTagsHash:
+	default:
+		logger.Warn("unknown zero-id tag handling, skipping them", "zero_id_tags", cfg.ZeroIDTags)
+	}
+
 	return &Source{
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout,
 		},
-		baseURL:        cfg.BaseURL,
-		pageSize:       cfg.PageSize,
-		pageDelay:      cfg.PageDelay,
-		maxAttempts:    cfg.MaxAttempts,
-		initialBackoff: cfg.InitialBackoff,
-		maxBackoff:     cfg.MaxBackoff,
-		logger:         logger.With("source", SourceID),
+		baseURL:            cfg.BaseURL,
+		pageSize:           cfg.PageSize,
+		pageDelay:          cfg.PageDelay,
+		pagination:         cfg.Pagination,
+		startPage:          cfg.StartPage,
+		pageStep:           pageStep,
+		cursorParam:        cursorParam,
+		maxZeroIDRatio:     maxZeroIDRatio,
+		maxEmptyTitleRatio: maxEmptyTitleRatio,
+		maxAttempts:        cfg.MaxAttempts,
+		initialBackoff:     cfg.InitialBackoff,
+		maxBackoff:         cfg.MaxBackoff,
+		categoryTags:       cfg.CategoryTags,
+		location:           location,
+		acceptLanguage:     cfg.AcceptLanguage,
+		language:           primaryLanguage(cfg.AcceptLanguage),
+		fetchConcurrency:   cfg.FetchConcurrency,
+		zeroIDTags:         cfg.ZeroIDTags,
+		maxResponseBytes:   maxResponseBytes,
+		dumpDir:            cfg.DumpDir,
+		logger:             logger.With("source", SourceID),
 	}
 }
 
@@ -66,8 +187,23 @@ func (s *Source) Name() string {
 }
 
 // FetchArticles fetches articles from ECB API.
-func (s *Source) FetchArticles(ctx context.Context, maxPages int) ([]domain.Article, error) {
+//
+// The API has no modified-since filter, so the hint is applied client-side:
+// older articles are dropped from each page. Pages are ordered by publication
+// date, not modification time, so an old article edited since can turn up on
+// any page: paging goes on up to maxPages rather than stopping at a page
+// without modified articles.
+//
+// Which page comes next is up to the configured pagination. Paging also stops
+// at the first empty page, so a missing or bogus NumPages or cursor can't keep
+// it running to maxPages.
+//
+// With FetchConcurrency above 1 and page pagination, the pages after the first
+// are fetched concurrently once it reports NumPages, and returned in page order.
+func (s *Source) FetchArticles(ctx context.Context, maxPages int, modifiedSince time.Time) ([]domain.Article, error) {
 	var fetchedContent []Content
+	pages := s.newPaginator()
+	query := pages.first()
 
 	for page := 0; page < maxPages; page++ {
 		if page > 0 && s.pageDelay > 0 {
@@ -78,29 +214,220 @@ func (s *Source) FetchArticles(ctx context.Context, maxPages int) ([]domain.Arti
 			}
 		}
 
-		pageResp, err := s.fetchPage(ctx, page)
+		pageResp, err := s.fetchPage(ctx, query)
 		if err != nil {
 			return s.transform(fetchedContent), fmt.Errorf("fetch page %d: %w", page, err)
 		}
 
-		fetchedContent = append(fetchedContent, pageResp.Content...)
+		pageContent, err := s.pageContents(pageResp, page, modifiedSince)
+		if err != nil {
+			return s.transform(fetchedContent), err
+		}
+		fetchedContent = append(fetchedContent, pageContent...)
 
 		s.logger.Debug("fetched page",
 			"page", page,
+			"query", query.Encode(),
 			"articles", len(pageResp.Content),
 			"total", len(fetchedContent),
 		)
 
-		if page >= pageResp.PageInfo.NumPages-1 {
+		next, ok := pages.next(pageResp, page)
+		if !ok || len(pageResp.Content) == 0 {
 			break
 		}
+
+		if page == 0 {
+			if rest := s.remainingQueries(pages, pageResp, maxPages); len(rest) > 0 {
+				more, err := s.fetchConcurrently(ctx, rest, modifiedSince)
+				fetchedContent = append(fetchedContent, more...)
+				return s.transform(fetchedContent), err
+			}
+		}
+
+		query = next
 	}
 
 	return s.transform(fetchedContent), nil
 }
 
-func (s *Source) fetchPage(ctx context.Context, page int) (*APIResponse, error) {
-	url := fmt.Sprintf("%s?pageSize=%d&page=%d", s.baseURL, s.pageSize, page)
+// pageContents validates a fetched page and returns its contents modified
+// after modifiedSince.
+func (s *Source) pageContents(resp *APIResponse, page int, modifiedSince time.Time) ([]Content, error) {
+	if err := s.validatePage(resp.Content); err != nil {
+		return nil, fmt.Errorf("validate page %d: %w", page, err)
+	}
+	return filterModifiedSince(resp.Content, modifiedSince, s.location), nil
+}
+
+// remainingQueries returns the queries of the pages after the first, up to
+// maxPages, if they can be fetched concurrently: FetchConcurrency is above 1,
+// pagination is by page and the first page reported NumPages.
+func (s *Source) remainingQueries(pages paginator, first *APIResponse, maxPages int) []url.Values {
+	if s.fetchConcurrency <= 1 || first.PageInfo.NumPages <= 0 {
+		return nil
+	}
+	if _, ok := pages.(*pagePaginator); !ok {
+		return nil
+	}
+
+	var queries []url.Values
+	for page := 1; page < maxPages; page++ {
+		query, ok := pages.next(first, page-1)
+		if !ok {
+			break
+		}
+		queries = append(queries, query)
+	}
+	return queries
+}
+
+// fetchConcurrently fetches the pages after the first with up to
+// FetchConcurrency requests at a time, starting them at least PageDelay apart,
+// and returns their contents in page order. Like the sequential fetch, it
+// stops at the first failed or empty page; later pages are discarded.
+func (s *Source) fetchConcurrently(ctx context.Context, queries []url.Values, modifiedSince time.Time) ([]Content, error) {
+	type result struct {
+		resp *APIResponse
+		err  error
+	}
+	results := make([]result, len(queries))
+
+	// failed stops the dispatch of further pages after an error.
+	failed := make(chan struct{})
+	var failOnce sync.Once
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(s.fetchConcurrency, len(queries)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				resp, err := s.fetchPage(ctx, queries[i])
+				results[i] = result{resp: resp, err: err}
+				if err != nil {
+					failOnce.Do(func() { close(failed) })
+				}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range queries {
+		if s.pageDelay > 0 {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case <-failed:
+				break dispatch
+			case <-time.After(s.pageDelay):
+			}
+		}
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
+		case <-failed:
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var contents []Content
+	for i, r := range results {
+		page := i + 1
+		if r.err != nil {
+			return contents, fmt.Errorf("fetch page %d: %w", page, r.err)
+		}
+		if r.resp == nil {
+			// Not requested: cancelled, or an earlier page failed.
+			return contents, ctx.Err()
+		}
+
+		pageContent, err := s.pageContents(r.resp, page, modifiedSince)
+		if err != nil {
+			return contents, err
+		}
+		contents = append(contents, pageContent...)
+
+		s.logger.Debug("fetched page",
+			"page", page,
+			"query", queries[i].Encode(),
+			"articles", len(r.resp.Content),
+		)
+
+		if len(r.resp.Content) == 0 {
+			break
+		}
+	}
+	return contents, nil
+}
+
+// filterModifiedSince keeps the contents modified after since. A zero since
+// keeps everything.
+func filterModifiedSince(contents []Content, since time.Time, loc *time.Location) []Content {
+	if since.IsZero() {
+		return contents
+	}
+
+	var filtered []Content
+	for _, c := range contents {
+		if c.lastModifiedAt(loc).After(since) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// TransformRaw maps a stored raw payload to an article without calling the API.
+func (s *Source) TransformRaw(raw []byte) (domain.Article, error) {
+	var c Content
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return domain.Article{}, fmt.Errorf("decode content: %w", err)
+	}
+
+	article, err := transformContent(c, s.location)
+	if err != nil {
+		return domain.Article{}, fmt.Errorf("transform content %d: %w", c.ID, err)
+	}
+	if category, ok := s.mappedCategory(c.Tags); ok {
+		article.Category = category
+	}
+	article.Language = s.language
+	article.Tags = s.resolveZeroIDTags(article.ExternalID, article.Tags)
+	return article, nil
+}
+
+// Healthcheck requests a single record from the API, without retrying, to
+// check it is reachable.
+func (s *Source) Healthcheck(ctx context.Context) error {
+	query := s.newPaginator().first()
+	query.Set("pageSize", "1")
+	if _, err := s.doRequest(ctx, s.baseURL+"?"+query.Encode()); err != nil {
+		return fmt.Errorf("healthcheck: %w", err)
+	}
+	return nil
+}
+
+// TotalEntries returns the number of records the API reports it has
+// (PageInfo.NumEntries), from a single-record request that isn't retried.
+func (s *Source) TotalEntries(ctx context.Context) (int, error) {
+	query := s.newPaginator().first()
+	query.Set("pageSize", "1")
+	resp, err := s.doRequest(ctx, s.baseURL+"?"+query.Encode())
+	if err != nil {
+		return 0, fmt.Errorf("fetch total entries: %w", err)
+	}
+	if resp.PageInfo.NumEntries == 0 && len(resp.Content) > 0 {
+		return 0, errors.New("fetch total entries: response has no page info")
+	}
+	return resp.PageInfo.NumEntries, nil
+}
+
+func (s *Source) fetchPage(ctx context.Context, query url.Values) (*APIResponse, error) {
+	url := s.baseURL + "?" + query.Encode()
 
 	var resp *APIResponse
 	var err error
@@ -140,6 +467,9 @@ func (s *Source) doRequest(ctx context.Context, url string) (*APIResponse, error
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("User-Agent", "NewsFetcher/1.0")
+	if s.acceptLanguage != "" {
+		req.Header.Set("Accept-Language", s.acceptLanguage)
+	}
 
 	resp, err := s.httpClient.Do(req)
 	if err != nil {
@@ -151,8 +481,27 @@ func (s *Source) doRequest(ctx context.Context, url string) (*APIResponse, error
 		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
+	if resp.ContentLength > s.maxResponseBytes {
+		return nil, fmt.Errorf("%w: %d bytes, limit %d", ErrResponseTooLarge, resp.ContentLength, s.maxResponseBytes)
+	}
+
+	// Reading one byte past the limit tells a body that exceeds it from one
+	// that ends right at it.
+	body := &io.LimitedReader{R: resp.Body, N: s.maxResponseBytes + 1}
+	var dumped bytes.Buffer
+	var r io.Reader = body
+	if s.dumpDir != "" {
+		r = io.TeeReader(body, &dumped)
+	}
 	var apiResp APIResponse
-	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+	err = json.NewDecoder(r).Decode(&apiResp)
+	if body.N <= 0 {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, s.maxResponseBytes)
+	}
+	if s.dumpDir != "" {
+		s.dumpResponse(url, dumped.Bytes())
+	}
+	if err != nil {
 		return nil, fmt.Errorf("decode response: %w", err)
 	}
 
@@ -170,48 +519,170 @@ func (s *Source) calculateBackoff(attempt int) time.Duration {
 	return backoff
 }
 
+// transform maps contents to articles, skipping (and logging) the ones that
+// can't be mapped, and applies the configured tag categories.
 func (s *Source) transform(contents []Content) []domain.Article {
 	articles := make([]domain.Article, 0, len(contents))
 
 	for _, c := range contents {
-		publishedAt, err := time.Parse(time.RFC3339, c.Date)
+		article, err := transformContent(c, s.location)
 		if err != nil {
-			s.logger.Warn("failed to parse date",
+			s.logger.Warn("dropping content",
 				"external_id", c.ID,
 				"date", c.Date,
+				"error", err,
 			)
 			continue
 		}
 
-		lastModified := time.UnixMilli(c.LastModified)
+		if category, ok := s.mappedCategory(c.Tags); ok {
+			article.Category = category
+		}
+		article.Language = s.language
+		article.Tags = s.resolveZeroIDTags(article.ExternalID, article.Tags)
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
+
+// dateLayouts are the date formats seen in ECB content, tried in order.
+// Dates without an offset are read in the source's configured location.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// Dates outside this range are treated as corrupt.
+var (
+	minContentDate = time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)
+	maxContentDate = time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC)
+)
 
-		article := domain.Article{
-			SourceID:     SourceID,
-			ExternalID:   c.ID,
-			Title:        c.Title,
-			Description:  c.Description,
-			Summary:      c.Summary,
-			Body:         c.Body,
-			Author:       c.Author,
-			CanonicalURL: c.CanonicalURL,
-			PublishedAt:  publishedAt,
-			LastModified: lastModified,
-			Duration:     c.Duration,
+// parseContentDate parses a content date in any of dateLayouts, reading naive
+// dates in loc, and checks it is within a sane range. The result is in UTC.
+func parseContentDate(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err != nil {
+			continue
 		}
+		if t.Before(minContentDate) || !t.Before(maxContentDate) {
+			return time.Time{}, fmt.Errorf("date %q out of range", value)
+		}
+		return t.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", value)
+}
+
+// transformContent maps one ECB content item to an article, reading dates
+// without an offset in loc. It fails if the publication date can't be parsed.
+func transformContent(c Content, loc *time.Location) (domain.Article, error) {
+	publishedAt, err := parseContentDate(c.Date, loc)
+	if err != nil {
+		return domain.Article{}, err
+	}
+
+	article := domain.Article{
+		SourceID:     SourceID,
+		ExternalID:   c.ID,
+		Title:        c.Title,
+		Description:  c.Description,
+		Summary:      c.Summary,
+		Body:         c.Body,
+		Author:       c.Author,
+		CanonicalURL: c.CanonicalURL,
+		PublishedAt:  publishedAt,
+		LastModified: c.lastModifiedAt(loc),
+		Duration:     c.Duration,
+		Category:     contentCategory(c),
+		Media:        media(c.LeadMedia),
+		Raw:          c.raw,
+	}
+
+	if c.LeadMedia != nil && c.LeadMedia.ImageURL != "" {
+		imageURL := c.LeadMedia.ImageURL
+		article.ImageURL = &imageURL
+	}
+
+	for _, tag := range c.Tags {
+		article.Tags = append(article.Tags, domain.Tag{
+			ID:    tag.ID,
+			Label: tag.Label,
+		})
+	}
+
+	return article, nil
+}
 
-		if c.LeadMedia != nil && c.LeadMedia.ImageURL != "" {
-			article.ImageURL = &c.LeadMedia.ImageURL
+// media maps the lead image and its renditions, primary image first.
+func media(lead *LeadMedia) []domain.MediaItem {
+	if lead == nil {
+		return nil
+	}
+
+	var items []domain.MediaItem
+	if lead.ImageURL != "" {
+		items = append(items, domain.MediaItem{URL: lead.ImageURL, Type: mediaTypeImage})
+	}
+	for _, v := range lead.Variants {
+		if v.URL == "" {
+			continue
 		}
+		items = append(items, domain.MediaItem{
+			URL:    v.URL,
+			Type:   mediaTypeImage,
+			Width:  v.Width,
+			Height: v.Height,
+		})
+	}
+	return items
+}
+
+// primaryLanguage returns the first language of an Accept-Language value,
+// e.g. "en-GB" for "en-GB,en;q=0.8".
+func primaryLanguage(acceptLanguage string) string {
+	first, _, _ := strings.Cut(acceptLanguage, ",")
+	first, _, _ = strings.Cut(first, ";")
+	return strings.TrimSpace(first)
+}
 
-		for _, tag := range c.Tags {
-			article.Tags = append(article.Tags, domain.Tag{
-				ID:    tag.ID,
-				Label: tag.Label,
-			})
+// resolveZeroIDTags drops the tags without an ID or, with ZeroIDTagsHash,
+// gives them a synthetic one. Stored as sent, they would all share the tag
+// row with ID 0 and overwrite each other's label. Tags without a label are
+// always dropped.
+func (s *Source) resolveZeroIDTags(externalID int64, tags []domain.Tag) []domain.Tag {
+	var resolved []domain.Tag
+	for _, tag := range tags {
+		if tag.ID == 0 {
+			if s.zeroIDTags != ZeroIDTagsHash || tag.Label == "" {
+				s.logger.Debug("dropping tag without id", "external_id", externalID, "label", tag.Label)
+				continue
+			}
+			tag.ID = domain.SyntheticTagID(tag.Label)
 		}
+		resolved = append(resolved, tag)
+	}
+	return resolved
+}
 
-		articles = append(articles, article)
+// mappedCategory returns the category of the first tag in CategoryTags.
+func (s *Source) mappedCategory(tags []APITag) (string, bool) {
+	for _, tag := range tags {
+		if category, ok := s.categoryTags[tag.Label]; ok {
+			return category, true
+		}
 	}
+	return "", false
+}
 
-	return articles
+// contentCategory derives the category from the content itself: anything with
+// a duration is a video, everything else an article.
+func contentCategory(c Content) string {
+	if c.Duration > 0 {
+		return domain.CategoryVideo
+	}
+	return domain.CategoryArticle
 }
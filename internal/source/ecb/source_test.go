@@ -0,0 +1,946 @@
+package ecb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"news_fetcher/internal/domain"
+	"news_fetcher/testdata/utils"
+)
+
+type SourceTestSuite struct {
+	suite.Suite
+	source *Source
+}
+
+func (s *SourceTestSuite) SetupTest() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.source = New(Config{
+		CategoryTags: map[string]string{"Match Report": "match_report"},
+	}, logger)
+}
+
+func TestSourceTestSuite(t *testing.T) {
+	suite.Run(t, new(SourceTestSuite))
+}
+
+func (s *SourceTestSuite) transformOne(c Content) domain.Article {
+	c.Date = "2025-01-15T10:00:00Z"
+	articles := s.source.transform([]Content{c})
+	s.Require().Len(articles, 1)
+	return articles[0]
+}
+
+func (s *SourceTestSuite) TestCategory_Article() {
+	article := s.transformOne(Content{ID: 1, Body: utils.Ptr("Body")})
+
+	s.Equal(domain.CategoryArticle, article.Category)
+}
+
+func (s *SourceTestSuite) TestCategory_Video() {
+	article := s.transformOne(Content{ID: 1, Duration: 90})
+
+	s.Equal(domain.CategoryVideo, article.Category)
+}
+
+func (s *SourceTestSuite) TestCategory_TagMappingWins() {
+	article := s.transformOne(Content{
+		ID:       1,
+		Duration: 90,
+		Tags:     []APITag{{ID: 1, Label: "England"}, {ID: 2, Label: "Match Report"}},
+	})
+
+	s.Equal("match_report", article.Category)
+}
+
+func (s *SourceTestSuite) TestCategory_UnmappedTag() {
+	article := s.transformOne(Content{
+		ID:   1,
+		Body: utils.Ptr("Body"),
+		Tags: []APITag{{ID: 1, Label: "England"}},
+	})
+
+	s.Equal(domain.CategoryArticle, article.Category)
+}
+
+func (s *SourceTestSuite) TestTags_ZeroIDSkipped() {
+	article := s.transformOne(Content{
+		ID:   1,
+		Tags: []APITag{{ID: 0, Label: "Ashes"}, {ID: 7, Label: "England"}, {Label: "T20"}},
+	})
+
+	s.Equal([]domain.Tag{{ID: 7, Label: "England"}}, article.Tags)
+}
+
+func (s *SourceTestSuite) TestTags_ZeroIDHashed() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	s.source = New(Config{ZeroIDTags: ZeroIDTagsHash}, logger)
+
+	article := s.transformOne(Content{
+		ID:   1,
+		Tags: []APITag{{ID: 0, Label: "Ashes"}, {ID: 7, Label: "England"}, {Label: "T20"}, {ID: 0}},
+	})
+
+	s.Equal([]domain.Tag{
+		{ID: domain.SyntheticTagID("Ashes"), Label: "Ashes"},
+		{ID: 7, Label: "England"},
+		{ID: domain.SyntheticTagID("T20"), Label: "T20"},
+	}, article.Tags)
+
+	// The same label gets the same ID in every article.
+	other := s.transformOne(Content{ID: 2, Tags: []APITag{{ID: 0, Label: "Ashes"}}})
+	s.Equal(article.Tags[0], other.Tags[0])
+}
+
+func (s *SourceTestSuite) TestMedia_LeadImageAndVariants() {
+	article := s.transformOne(Content{
+		ID: 1,
+		LeadMedia: &LeadMedia{
+			ImageURL: "https://example.com/image.jpg",
+			Variants: []ImageVariant{
+				{URL: "https://example.com/image-640.jpg", Width: 640, Height: 360},
+				{URL: ""},
+			},
+		},
+	})
+
+	s.Require().NotNil(article.ImageURL)
+	s.Equal("https://example.com/image.jpg", *article.ImageURL)
+	s.Equal([]domain.MediaItem{
+		{URL: "https://example.com/image.jpg", Type: "image"},
+		{URL: "https://example.com/image-640.jpg", Type: "image", Width: 640, Height: 360},
+	}, article.Media)
+}
+
+func (s *SourceTestSuite) TestMedia_NoLeadMedia() {
+	article := s.transformOne(Content{ID: 1})
+
+	s.Nil(article.ImageURL)
+	s.Nil(article.Media)
+}
+
+func (s *SourceTestSuite) TestTransformRaw() {
+	raw := []byte(`{"id":42,"title":"Title","date":"2025-01-15T10:00:00Z","canonicalUrl":"https://example.com/a","duration":30,"lastModified":1736935200000}`)
+
+	article, err := s.source.TransformRaw(raw)
+
+	s.Require().NoError(err)
+	s.Equal(int64(42), article.ExternalID)
+	s.Equal("Title", article.Title)
+	s.Equal(domain.CategoryVideo, article.Category)
+	s.JSONEq(string(raw), string(article.Raw))
+}
+
+func (s *SourceTestSuite) TestTransformRaw_Invalid() {
+	_, err := s.source.TransformRaw([]byte(`not json`))
+	s.Error(err)
+
+	_, err = s.source.TransformRaw([]byte(`{"id":1,"date":"yesterday"}`))
+	s.Error(err)
+}
+
+func (s *SourceTestSuite) TestUnmarshal_KeepsRawContent() {
+	var resp APIResponse
+	err := json.Unmarshal([]byte(`{"content":[{"id":1,"title":"A"},{"id":2,"title":"B"}]}`), &resp)
+
+	s.Require().NoError(err)
+	s.Require().Len(resp.Content, 2)
+	s.JSONEq(`{"id":1,"title":"A"}`, string(resp.Content[0].raw))
+	s.JSONEq(`{"id":2,"title":"B"}`, string(resp.Content[1].raw))
+}
+
+func (s *SourceTestSuite) TestTransformContent() {
+	published := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	modified := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		content Content
+		want    domain.Article
+		wantErr bool
+	}{
+		{
+			name: "all fields",
+			content: Content{
+				ID:           1,
+				Title:        "Title",
+				Description:  utils.Ptr("Description"),
+				Summary:      utils.Ptr("Summary"),
+				Body:         utils.Ptr("Body"),
+				Author:       utils.Ptr("Author"),
+				Date:         "2025-01-15T10:00:00Z",
+				CanonicalURL: "https://example.com/1",
+				LastModified: modified.UnixMilli(),
+				Tags:         []APITag{{ID: 10, Label: "England"}, {ID: 11, Label: "Test"}},
+				LeadMedia:    &LeadMedia{ImageURL: "https://example.com/1.jpg"},
+			},
+			want: domain.Article{
+				SourceID:     SourceID,
+				ExternalID:   1,
+				Title:        "Title",
+				Description:  utils.Ptr("Description"),
+				Summary:      utils.Ptr("Summary"),
+				Body:         utils.Ptr("Body"),
+				Author:       utils.Ptr("Author"),
+				CanonicalURL: "https://example.com/1",
+				ImageURL:     utils.Ptr("https://example.com/1.jpg"),
+				PublishedAt:  published,
+				LastModified: modified,
+				Category:     domain.CategoryArticle,
+				Tags:         []domain.Tag{{ID: 10, Label: "England"}, {ID: 11, Label: "Test"}},
+				Media:        []domain.MediaItem{{URL: "https://example.com/1.jpg", Type: "image"}},
+			},
+		},
+		{
+			name: "nil pointers and no tags",
+			content: Content{
+				ID:           2,
+				Title:        "Bare",
+				Date:         "2025-01-15T10:00:00Z",
+				CanonicalURL: "https://example.com/2",
+				LastModified: modified.UnixMilli(),
+			},
+			want: domain.Article{
+				SourceID:     SourceID,
+				ExternalID:   2,
+				Title:        "Bare",
+				CanonicalURL: "https://example.com/2",
+				PublishedAt:  published,
+				LastModified: modified,
+				Category:     domain.CategoryArticle,
+			},
+		},
+		{
+			name: "lead media without image",
+			content: Content{
+				ID:           3,
+				Date:         "2025-01-15T10:00:00Z",
+				LastModified: modified.UnixMilli(),
+				Duration:     120,
+				LeadMedia:    &LeadMedia{},
+			},
+			want: domain.Article{
+				SourceID:     SourceID,
+				ExternalID:   3,
+				PublishedAt:  published,
+				LastModified: modified,
+				Duration:     120,
+				Category:     domain.CategoryVideo,
+			},
+		},
+		{
+			name:    "bad date",
+			content: Content{ID: 4, Date: "15/01/2025"},
+			wantErr: true,
+		},
+		{
+			name:    "missing date",
+			content: Content{ID: 5},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			got, err := transformContent(tt.content, time.UTC)
+			if tt.wantErr {
+				s.Error(err)
+				return
+			}
+			s.Require().NoError(err)
+			s.Equal(tt.want.PublishedAt.UnixMilli(), got.PublishedAt.UnixMilli())
+			s.Equal(tt.want.LastModified.UnixMilli(), got.LastModified.UnixMilli())
+			tt.want.PublishedAt, got.PublishedAt = time.Time{}, time.Time{}
+			tt.want.LastModified, got.LastModified = time.Time{}, time.Time{}
+			s.Equal(tt.want, got)
+		})
+	}
+}
+
+func (s *SourceTestSuite) TestTransformContent_ZeroLastModified() {
+	article, err := transformContent(Content{ID: 1, Date: "2025-01-15T10:00:00Z"}, time.UTC)
+
+	s.Require().NoError(err)
+	s.True(article.LastModified.Equal(time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)))
+}
+
+func (s *SourceTestSuite) TestFilterModifiedSince_ZeroLastModified() {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	contents := []Content{
+		{ID: 1, Date: "2025-01-15T10:00:00Z"},
+		{ID: 2, Date: "2024-12-15T10:00:00Z"},
+	}
+
+	filtered := filterModifiedSince(contents, since, time.UTC)
+
+	s.Require().Len(filtered, 1)
+	s.Equal(int64(1), filtered[0].ID)
+}
+
+func (s *SourceTestSuite) TestParseContentDate() {
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "2025-01-15T10:00:00Z", want: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{value: "2025-01-15T10:00:00+01:00", want: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{value: "2025-01-15T10:00:00.123Z", want: time.Date(2025, 1, 15, 10, 0, 0, 123000000, time.UTC)},
+		{value: "2025-01-15T10:00:00", want: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{value: "2025-01-15T10:00:00.5", want: time.Date(2025, 1, 15, 10, 0, 0, 500000000, time.UTC)},
+		{value: "2025-01-15", want: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{value: "", wantErr: true},
+		{value: "15/01/2025", wantErr: true},
+		{value: "Jan 15 2025", wantErr: true},
+		{value: "0001-01-01T00:00:00Z", wantErr: true},
+		{value: "2200-01-01", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.value, func() {
+			got, err := parseContentDate(tt.value, time.UTC)
+			if tt.wantErr {
+				s.Error(err)
+				return
+			}
+			s.Require().NoError(err)
+			s.True(tt.want.Equal(got), "got %s", got)
+		})
+	}
+}
+
+func (s *SourceTestSuite) TestParseContentDate_Location() {
+	london, err := time.LoadLocation("Europe/London")
+	s.Require().NoError(err)
+
+	// Naive timestamps are read in the location (BST is UTC+1 in July).
+	got, err := parseContentDate("2025-07-01T10:00:00", london)
+	s.Require().NoError(err)
+	s.Equal(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), got)
+	s.Equal(time.UTC, got.Location())
+
+	// An explicit offset wins over the location.
+	got, err = parseContentDate("2025-07-01T10:00:00Z", london)
+	s.Require().NoError(err)
+	s.Equal(time.Date(2025, 7, 1, 10, 0, 0, 0, time.UTC), got)
+}
+
+func (s *SourceTestSuite) TestTransform_UsesConfiguredLocation() {
+	london, err := time.LoadLocation("Europe/London")
+	s.Require().NoError(err)
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	source := New(Config{Location: london}, logger)
+
+	articles := source.transform([]Content{{ID: 1, Date: "2025-07-01T10:00:00"}})
+
+	s.Require().Len(articles, 1)
+	s.Equal(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), articles[0].PublishedAt)
+	s.Equal(time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC), articles[0].LastModified)
+}
+
+// serve starts a fake API answering each page with pages[page] (an empty page
+// if missing) and returns a source pointed at it and the requested pages.
+func (s *SourceTestSuite) serve(cfg Config, pages map[int]APIResponse) (*Source, func() []int) {
+	source, requested := s.serveBy(cfg, "page", func(value string) APIResponse {
+		page, err := strconv.Atoi(value)
+		s.Require().NoError(err)
+		return pages[page]
+	})
+
+	return source, func() []int {
+		var numbers []int
+		for _, value := range requested() {
+			page, _ := strconv.Atoi(value)
+			numbers = append(numbers, page)
+		}
+		return numbers
+	}
+}
+
+// serveBy starts a fake API answering with respond(the param query parameter)
+// and returns a source pointed at it and the requested param values.
+func (s *SourceTestSuite) serveBy(cfg Config, param string, respond func(string) APIResponse) (*Source, func() []string) {
+	var mu sync.Mutex
+	var requested []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.URL.Query().Get(param)
+
+		mu.Lock()
+		requested = append(requested, value)
+		mu.Unlock()
+
+		_ = json.NewEncoder(w).Encode(respond(value))
+	}))
+	s.T().Cleanup(srv.Close)
+
+	return s.newTestSource(srv.URL, cfg), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), requested...)
+	}
+}
+
+// newTestSource returns a source for the API at baseURL with small pages and
+// no retries.
+func (s *SourceTestSuite) newTestSource(baseURL string, cfg Config) *Source {
+	cfg.BaseURL = baseURL
+	cfg.PageSize = 2
+	cfg.Timeout = time.Second
+	cfg.MaxAttempts = 1
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	return New(cfg, logger)
+}
+
+func pageOf(info PageInfo, ids ...int64) APIResponse {
+	resp := APIResponse{PageInfo: info}
+	for _, id := range ids {
+		resp.Content = append(resp.Content, Content{
+			ID:    id,
+			Title: fmt.Sprintf("Article %d", id),
+			Date:  "2025-01-15T10:00:00Z",
+		})
+	}
+	return resp
+}
+
+func (s *SourceTestSuite) TestFetchArticles_PageInfo() {
+	tests := []struct {
+		name      string
+		pages     map[int]APIResponse
+		wantPages []int
+		wantIDs   []int64
+	}{
+		{
+			name: "consistent",
+			pages: map[int]APIResponse{
+				0: pageOf(PageInfo{NumPages: 2, PageSize: 2, NumEntries: 3}, 1, 2),
+				1: pageOf(PageInfo{Page: 1, NumPages: 2, PageSize: 2, NumEntries: 3}, 3),
+			},
+			wantPages: []int{0, 1},
+			wantIDs:   []int64{1, 2, 3},
+		},
+		{
+			name: "zero num pages stops at empty page",
+			pages: map[int]APIResponse{
+				0: pageOf(PageInfo{}, 1, 2),
+				1: pageOf(PageInfo{}, 3),
+			},
+			wantPages: []int{0, 1, 2},
+			wantIDs:   []int64{1, 2, 3},
+		},
+		{
+			name: "negative num pages stops at empty page",
+			pages: map[int]APIResponse{
+				0: pageOf(PageInfo{NumPages: -1}, 1),
+			},
+			wantPages: []int{0, 1},
+			wantIDs:   []int64{1},
+		},
+		{
+			name: "huge num pages stops at empty page",
+			pages: map[int]APIResponse{
+				0: pageOf(PageInfo{NumPages: 1 << 30, PageSize: 2, NumEntries: 3}, 1, 2),
+				1: pageOf(PageInfo{Page: 1, NumPages: 1 << 30, PageSize: 2, NumEntries: 3}, 3),
+			},
+			wantPages: []int{0, 1, 2},
+			wantIDs:   []int64{1, 2, 3},
+		},
+		{
+			name: "empty first page",
+			pages: map[int]APIResponse{
+				0: pageOf(PageInfo{NumPages: 3, PageSize: 2, NumEntries: 5}),
+			},
+			wantPages: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			source, requested := s.serve(Config{}, tt.pages)
+
+			articles, err := source.FetchArticles(context.Background(), 5, time.Time{})
+
+			s.Require().NoError(err)
+			s.Equal(tt.wantPages, requested())
+			var ids []int64
+			for _, a := range articles {
+				ids = append(ids, a.ExternalID)
+			}
+			s.Equal(tt.wantIDs, ids)
+		})
+	}
+}
+
+func (s *SourceTestSuite) TestFetchArticles_OneIndexed() {
+	source, requested := s.serve(Config{StartPage: 1}, map[int]APIResponse{
+		1: pageOf(PageInfo{Page: 1, NumPages: 3, PageSize: 2, NumEntries: 5}, 1, 2),
+		2: pageOf(PageInfo{Page: 2, NumPages: 3, PageSize: 2, NumEntries: 5}, 3, 4),
+		3: pageOf(PageInfo{Page: 3, NumPages: 3, PageSize: 2, NumEntries: 5}, 5),
+	})
+
+	articles, err := source.FetchArticles(context.Background(), 5, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]int{1, 2, 3}, requested())
+	s.Len(articles, 5)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_PageStep() {
+	source, requested := s.serve(Config{StartPage: 4, PageStep: 2}, map[int]APIResponse{
+		4: pageOf(PageInfo{NumPages: 3, PageSize: 2, NumEntries: 6}, 1, 2),
+		6: pageOf(PageInfo{NumPages: 3, PageSize: 2, NumEntries: 6}, 3, 4),
+	})
+
+	_, err := source.FetchArticles(context.Background(), 2, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]int{4, 6}, requested())
+}
+
+func (s *SourceTestSuite) TestPageInfoConsistent() {
+	tests := []struct {
+		name     string
+		info     PageInfo
+		contents int
+		want     bool
+	}{
+		{"consistent", PageInfo{NumPages: 3, PageSize: 20, NumEntries: 45}, 20, true},
+		{"empty result", PageInfo{}, 0, true},
+		{"zero pages with content", PageInfo{}, 20, false},
+		{"negative pages", PageInfo{NumPages: -1}, 20, false},
+		{"too many pages", PageInfo{NumPages: 1000, PageSize: 20, NumEntries: 45}, 20, false},
+		{"empty page with entries", PageInfo{NumPages: 3, PageSize: 20, NumEntries: 45}, 0, false},
+		{"no entry count", PageInfo{NumPages: 3}, 20, true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			s.Equal(tt.want, pageInfoConsistent(tt.info, tt.contents))
+		})
+	}
+}
+
+// cursorPages serves pages keyed by cursor, chained through nextCursor.
+func cursorPages(pages map[string]APIResponse) func(string) APIResponse {
+	return func(cursor string) APIResponse {
+		return pages[cursor]
+	}
+}
+
+func withCursor(resp APIResponse, next string) APIResponse {
+	resp.NextCursor = next
+	return resp
+}
+
+func (s *SourceTestSuite) TestFetchArticles_Cursor() {
+	source, requested := s.serveBy(Config{Pagination: PaginationCursor}, "cursor", cursorPages(map[string]APIResponse{
+		"":    withCursor(pageOf(PageInfo{}, 1, 2), "abc"),
+		"abc": withCursor(pageOf(PageInfo{}, 3, 4), "def"),
+		"def": pageOf(PageInfo{}, 5),
+	}))
+
+	articles, err := source.FetchArticles(context.Background(), 10, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"", "abc", "def"}, requested())
+	s.Len(articles, 5)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_CursorParam() {
+	source, requested := s.serveBy(Config{Pagination: PaginationCursor, CursorParam: "after"}, "after", cursorPages(map[string]APIResponse{
+		"":   withCursor(pageOf(PageInfo{}, 1, 2), "c1"),
+		"c1": pageOf(PageInfo{}, 3),
+	}))
+
+	articles, err := source.FetchArticles(context.Background(), 10, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"", "c1"}, requested())
+	s.Len(articles, 3)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_CursorMaxPages() {
+	// Every page points to another one; maxPages bounds the fetch.
+	source, requested := s.serveBy(Config{Pagination: PaginationCursor}, "cursor", func(cursor string) APIResponse {
+		return withCursor(pageOf(PageInfo{}, 1), cursor+"x")
+	})
+
+	_, err := source.FetchArticles(context.Background(), 3, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"", "x", "xx"}, requested())
+}
+
+func (s *SourceTestSuite) TestFetchArticles_CursorStopsAtEmptyPage() {
+	source, requested := s.serveBy(Config{Pagination: PaginationCursor}, "cursor", cursorPages(map[string]APIResponse{
+		"":    withCursor(pageOf(PageInfo{}, 1), "abc"),
+		"abc": withCursor(pageOf(PageInfo{}), "def"),
+	}))
+
+	articles, err := source.FetchArticles(context.Background(), 10, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal([]string{"", "abc"}, requested())
+	s.Len(articles, 1)
+}
+
+func (s *SourceTestSuite) TestValidatePage() {
+	valid := Content{ID: 1, Title: "Title"}
+	tests := []struct {
+		name     string
+		cfg      Config
+		contents []Content
+		wantErr  bool
+	}{
+		{"empty page", Config{}, nil, false},
+		{"all valid", Config{}, []Content{valid, valid}, false},
+		{"half without id", Config{}, []Content{valid, {Title: "Title"}}, false},
+		{"most without id", Config{}, []Content{valid, {Title: "Title"}, {Title: "Title"}}, true},
+		{"most without title", Config{}, []Content{valid, {ID: 2}, {ID: 3}}, true},
+		{"strict threshold", Config{MaxZeroIDRatio: utils.Ptr(0.1)}, []Content{valid, {Title: "Title"}}, true},
+		{"no tolerance", Config{MaxZeroIDRatio: utils.Ptr(0.0)}, []Content{valid, valid, valid, {Title: "Title"}}, true},
+		{"check disabled", Config{MaxZeroIDRatio: utils.Ptr(1.0), MaxEmptyTitleRatio: utils.Ptr(1.0)}, []Content{{}, {}}, false},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := New(tt.cfg, logger).validatePage(tt.contents)
+
+			if tt.wantErr {
+				s.ErrorIs(err, ErrUnexpectedSchema)
+			} else {
+				s.NoError(err)
+			}
+		})
+	}
+}
+
+func (s *SourceTestSuite) TestFetchArticles_RejectsUnexpectedSchema() {
+	// The API renamed its fields: every record decodes with zero values.
+	garbage := APIResponse{
+		PageInfo: PageInfo{NumPages: 1, PageSize: 2, NumEntries: 2},
+		Content:  []Content{{Date: "2025-01-15T10:00:00Z"}, {Date: "2025-01-15T10:00:00Z"}},
+	}
+	source, _ := s.serve(Config{}, map[int]APIResponse{0: garbage})
+
+	_, err := source.FetchArticles(context.Background(), 5, time.Time{})
+
+	s.ErrorIs(err, ErrUnexpectedSchema)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_SendsAcceptLanguage() {
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Accept-Language")
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{NumPages: 1, PageSize: 2, NumEntries: 1}, 1))
+	}))
+	defer srv.Close()
+
+	source := s.newTestSource(srv.URL, Config{AcceptLanguage: "en-GB,en;q=0.8"})
+
+	articles, err := source.FetchArticles(context.Background(), 1, time.Time{})
+
+	s.Require().NoError(err)
+	s.Equal("en-GB,en;q=0.8", header)
+	s.Require().Len(articles, 1)
+	s.Equal("en-GB", articles[0].Language)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_NoAcceptLanguage() {
+	header := "unset"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Accept-Language")
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{NumPages: 1, PageSize: 2, NumEntries: 1}, 1))
+	}))
+	defer srv.Close()
+
+	source := s.newTestSource(srv.URL, Config{})
+
+	articles, err := source.FetchArticles(context.Background(), 1, time.Time{})
+
+	s.Require().NoError(err)
+	s.Empty(header)
+	s.Require().Len(articles, 1)
+	s.Empty(articles[0].Language)
+}
+
+func (s *SourceTestSuite) TestHealthcheck_RequestsOneRecord() {
+	var requests int
+	var pageSize string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		pageSize = r.URL.Query().Get("pageSize")
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{NumPages: 1, PageSize: 1, NumEntries: 1}, 1))
+	}))
+	defer srv.Close()
+
+	err := s.newTestSource(srv.URL, Config{}).Healthcheck(context.Background())
+
+	s.NoError(err)
+	s.Equal(1, requests)
+	s.Equal("1", pageSize)
+}
+
+func (s *SourceTestSuite) TestHealthcheck_Unhealthy() {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	// Not retried, however many attempts a fetch makes.
+	err := s.newTestSource(srv.URL, Config{MaxAttempts: 3}).Healthcheck(context.Background())
+
+	s.ErrorContains(err, "unexpected status: 502")
+	s.Equal(1, requests)
+}
+
+func (s *SourceTestSuite) TestTotalEntries() {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{NumPages: 42, PageSize: 1, NumEntries: 42}, 1))
+	}))
+	defer srv.Close()
+
+	total, err := s.newTestSource(srv.URL, Config{}).TotalEntries(context.Background())
+
+	s.NoError(err)
+	s.Equal(42, total)
+	s.Equal(1, requests)
+}
+
+func (s *SourceTestSuite) TestTotalEntries_Empty() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{}))
+	}))
+	defer srv.Close()
+
+	total, err := s.newTestSource(srv.URL, Config{}).TotalEntries(context.Background())
+
+	s.NoError(err)
+	s.Zero(total)
+}
+
+func (s *SourceTestSuite) TestTotalEntries_NoPageInfo() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(pageOf(PageInfo{}, 1))
+	}))
+	defer srv.Close()
+
+	_, err := s.newTestSource(srv.URL, Config{}).TotalEntries(context.Background())
+
+	s.ErrorContains(err, "no page info")
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ResponseTooLarge() {
+	page, err := json.Marshal(pageOf(PageInfo{NumPages: 1, PageSize: 2, NumEntries: 2}, 1, 2))
+	s.Require().NoError(err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Streamed, without a Content-Length.
+		w.(http.Flusher).Flush()
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	source := s.newTestSource(srv.URL, Config{MaxResponseBytes: int64(len(page)) - 1})
+
+	_, err = source.FetchArticles(context.Background(), 1, time.Time{})
+
+	s.ErrorIs(err, ErrResponseTooLarge)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ResponseTooLargeByContentLength() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "2048")
+		_, _ = w.Write(make([]byte, 2048))
+	}))
+	defer srv.Close()
+
+	source := s.newTestSource(srv.URL, Config{MaxResponseBytes: 1024})
+
+	_, err := source.FetchArticles(context.Background(), 1, time.Time{})
+
+	s.ErrorIs(err, ErrResponseTooLarge)
+	s.ErrorContains(err, "2048 bytes, limit 1024")
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ResponseAtLimit() {
+	page, err := json.Marshal(pageOf(PageInfo{NumPages: 1, PageSize: 2, NumEntries: 2}, 1, 2))
+	s.Require().NoError(err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	source := s.newTestSource(srv.URL, Config{MaxResponseBytes: int64(len(page))})
+
+	articles, err := source.FetchArticles(context.Background(), 1, time.Time{})
+
+	s.NoError(err)
+	s.Len(articles, 2)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_DumpsResponses() {
+	pages := make(map[string][]byte)
+	for page, resp := range []APIResponse{
+		pageOf(PageInfo{NumPages: 2, PageSize: 2, NumEntries: 3}, 1, 2),
+		pageOf(PageInfo{NumPages: 2, PageSize: 2, NumEntries: 3}, 3),
+	} {
+		body, err := json.Marshal(resp)
+		s.Require().NoError(err)
+		pages[strconv.Itoa(page)] = body
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pages[r.URL.Query().Get("page")])
+	}))
+	defer srv.Close()
+
+	dir := s.T().TempDir()
+	source := s.newTestSource(srv.URL, Config{DumpDir: dir})
+
+	_, err := source.FetchArticles(context.Background(), 2, time.Time{})
+	s.Require().NoError(err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "ecb-*.json"))
+	s.Require().NoError(err)
+	s.Require().Len(files, 2)
+	sort.Strings(files)
+	for i, file := range files {
+		data, err := os.ReadFile(file)
+		s.Require().NoError(err)
+		s.Equal(pages[strconv.Itoa(i)], data)
+	}
+}
+
+// serveSlow starts a fake API that takes delay to answer each page request,
+// serving pages (later pages with status 500 if listed in failing), and
+// returns a source pointed at it.
+func (s *SourceTestSuite) serveSlow(cfg Config, delay time.Duration, pages map[int]APIResponse, failing ...int) *Source {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		for _, f := range failing {
+			if page == f {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		_ = json.NewEncoder(w).Encode(pages[page])
+	}))
+	s.T().Cleanup(srv.Close)
+	return s.newTestSource(srv.URL, cfg)
+}
+
+func fivePages() map[int]APIResponse {
+	info := PageInfo{NumPages: 5, PageSize: 2, NumEntries: 10}
+	pages := make(map[int]APIResponse, 5)
+	for page := 0; page < 5; page++ {
+		pages[page] = pageOf(info, int64(page*2+1), int64(page*2+2))
+	}
+	return pages
+}
+
+// editedOnLastPage returns fivePages with only article 9, on the last page,
+// modified after since.
+func editedOnLastPage(since time.Time) map[int]APIResponse {
+	pages := fivePages()
+	pages[4].Content[0].LastModified = since.Add(time.Hour).UnixMilli()
+	return pages
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ModifiedSinceKeepsPaging() {
+	since := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	source, requested := s.serve(Config{}, editedOnLastPage(since))
+
+	articles, err := source.FetchArticles(context.Background(), 5, since)
+
+	s.Require().NoError(err)
+	s.Equal([]int{0, 1, 2, 3, 4}, requested())
+	s.Require().Len(articles, 1)
+	s.Equal(int64(9), articles[0].ExternalID)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ConcurrentModifiedSinceKeepsPaging() {
+	since := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	source, requested := s.serve(Config{FetchConcurrency: 4}, editedOnLastPage(since))
+
+	articles, err := source.FetchArticles(context.Background(), 5, since)
+
+	s.Require().NoError(err)
+	s.ElementsMatch([]int{0, 1, 2, 3, 4}, requested())
+	s.Require().Len(articles, 1)
+	s.Equal(int64(9), articles[0].ExternalID)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_Concurrent() {
+	const delay = 100 * time.Millisecond
+	source := s.serveSlow(Config{FetchConcurrency: 4}, delay, fivePages())
+
+	start := time.Now()
+	articles, err := source.FetchArticles(context.Background(), 5, time.Time{})
+	elapsed := time.Since(start)
+
+	s.Require().NoError(err)
+	// The first page, then the other four at once, rather than 5 in a row.
+	s.Less(elapsed, 4*delay)
+	var ids []int64
+	for _, a := range articles {
+		ids = append(ids, a.ExternalID)
+	}
+	s.Equal([]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, ids)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ConcurrentRespectsMaxPages() {
+	source, requested := s.serve(Config{FetchConcurrency: 4}, fivePages())
+
+	articles, err := source.FetchArticles(context.Background(), 3, time.Time{})
+
+	s.Require().NoError(err)
+	s.ElementsMatch([]int{0, 1, 2}, requested())
+	s.Len(articles, 6)
+}
+
+func (s *SourceTestSuite) TestFetchArticles_ConcurrentStopsAtFailedPage() {
+	source := s.serveSlow(Config{FetchConcurrency: 2}, time.Millisecond, fivePages(), 2)
+
+	articles, err := source.FetchArticles(context.Background(), 5, time.Time{})
+
+	s.ErrorContains(err, "fetch page 2")
+	var ids []int64
+	for _, a := range articles {
+		ids = append(ids, a.ExternalID)
+	}
+	s.Equal([]int64{1, 2, 3, 4}, ids)
+}
+
+func (s *SourceTestSuite) TestPrimaryLanguage() {
+	tests := map[string]string{
+		"":                  "",
+		"en-GB":             "en-GB",
+		"en-GB,en;q=0.8":    "en-GB",
+		"hi;q=0.9, en":      "hi",
+		" fr-FR , en;q=0.5": "fr-FR",
+	}
+
+	for value, want := range tests {
+		s.Equal(want, primaryLanguage(value), value)
+	}
+}
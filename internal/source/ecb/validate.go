@@ -0,0 +1,43 @@
+package ecb
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnexpectedSchema is returned when a page looks like the API changed its
+// response shape: too many records decode without an ID or a title.
+var ErrUnexpectedSchema = errors.New("unexpected response schema")
+
+// Default share of records on a page that may lack an ID or a title.
+const (
+	defaultMaxZeroIDRatio     = 0.5
+	defaultMaxEmptyTitleRatio = 0.5
+)
+
+// validatePage fails if the share of records with a zero ID or an empty title
+// exceeds the configured ratio. Empty pages are valid.
+func (s *Source) validatePage(contents []Content) error {
+	if len(contents) == 0 {
+		return nil
+	}
+
+	var zeroIDs, emptyTitles int
+	for _, c := range contents {
+		if c.ID == 0 {
+			zeroIDs++
+		}
+		if c.Title == "" {
+			emptyTitles++
+		}
+	}
+
+	total := float64(len(contents))
+	if ratio := float64(zeroIDs) / total; ratio > s.maxZeroIDRatio {
+		return fmt.Errorf("%w: %d of %d records have no id", ErrUnexpectedSchema, zeroIDs, len(contents))
+	}
+	if ratio := float64(emptyTitles) / total; ratio > s.maxEmptyTitleRatio {
+		return fmt.Errorf("%w: %d of %d records have no title", ErrUnexpectedSchema, emptyTitles, len(contents))
+	}
+	return nil
+}
@@ -3,6 +3,12 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -13,35 +19,194 @@ import (
 
 type ArticleStore struct {
 	db *sqlx.DB
+	// protected are the columns a conflicting upsert leaves alone; see Protect.
+	protected []string
+	// updatable, if set, are the only protectable columns a conflicting
+	// upsert overwrites; see UpdateOnly.
+	updatable []string
+	// tagsByLabel compares recorded and linked tags by label; see
+	// SetTagIdentity.
+	tagsByLabel bool
+	// normalizeTagLabels compares labels normalized; see NormalizeTagLabels.
+	normalizeTagLabels bool
+	// keepRevisions records the prior version of updated articles; see
+	// KeepRevisions.
+	keepRevisions bool
 }
 
 func NewArticleStore(db *sqlx.DB) *ArticleStore {
 	return &ArticleStore{db: db}
 }
 
+// updatedColumns are the columns an upsert overwrites when the article is
+// already stored, in the order they are set.
+var updatedColumns = []string{
+	"title", "description", "summary", "body", "author", "canonical_url", "image_url",
+	"last_modified", "duration", "category", "media", "language", "content_hash",
+	"reading_time", "tags",
+}
+
+// protectableColumns maps the columns Protect accepts to the article field
+// their stored value is read back into. last_modified and content_hash track
+// the upstream version and the tags are linked separately, so they can't be
+// protected.
+var protectableColumns = map[string]func(a *domain.Article) any{
+	"title":         func(a *domain.Article) any { return &a.Title },
+	"description":   func(a *domain.Article) any { return &a.Description },
+	"summary":       func(a *domain.Article) any { return &a.Summary },
+	"body":          func(a *domain.Article) any { return &a.Body },
+	"author":        func(a *domain.Article) any { return &a.Author },
+	"canonical_url": func(a *domain.Article) any { return &a.CanonicalURL },
+	"image_url":     func(a *domain.Article) any { return &a.ImageURL },
+	"duration":      func(a *domain.Article) any { return &a.Duration },
+	"category":      func(a *domain.Article) any { return &a.Category },
+	"language":      func(a *domain.Article) any { return &a.Language },
+	"reading_time":  func(a *domain.Article) any { return &a.ReadingTime },
+}
+
+// Protect keeps Upsert and Replace from overwriting the given columns of a
+// stored article, e.g. a title curated by hand: they are only set when the
+// article is inserted, and the article passed in gets their stored values.
+// It returns an error, and protects nothing, if a column can't be protected.
+func (s *ArticleStore) Protect(columns ...string) error {
+	for _, c := range columns {
+		if _, ok := protectableColumns[c]; !ok {
+			return fmt.Errorf("column %q can't be protected", c)
+		}
+	}
+	s.protected = append([]string(nil), columns...)
+	return nil
+}
+
+// UpdateOnly restricts the columns Upsert and Replace overwrite in a stored
+// article to the given ones: every other column Protect accepts is protected
+// as well. last_modified, content_hash, media and tags are always updated,
+// listed or not. Without columns, all of them are updated again. It returns
+// an error, and changes nothing, if a column isn't one an upsert updates.
+func (s *ArticleStore) UpdateOnly(columns ...string) error {
+	for _, c := range columns {
+		if !slices.Contains(updatedColumns, c) {
+			return fmt.Errorf("column %q isn't updated by an upsert", c)
+		}
+	}
+	if len(columns) == 0 {
+		s.updatable = nil
+		return nil
+	}
+	s.updatable = append([]string(nil), columns...)
+	return nil
+}
+
+// SetTagIdentity tells ListTagMismatches how the tag store keys tags (see
+// TagStore.SetIdentity). Keyed by label, linked tags have other IDs than the
+// recorded ones, which are as the source sent them, so they are compared by
+// label.
+func (s *ArticleStore) SetTagIdentity(identity string) {
+	s.tagsByLabel = identity == TagIdentitySourceLabel || identity == TagIdentityLabel
+}
+
+// NormalizeTagLabels tells ListTagMismatches that the tag store keys tags by
+// their normalized labels (see TagStore.NormalizeLabels), so recorded labels
+// are normalized before they are compared.
+func (s *ArticleStore) NormalizeTagLabels(normalize bool) {
+	s.normalizeTagLabels = normalize
+}
+
+// KeepRevisions makes Upsert and Replace record the title, summary and body
+// of an article they update in article_revisions, along with its
+// last_modified, in the same statement as the update. See GetRevisions.
+func (s *ArticleStore) KeepRevisions(keep bool) {
+	s.keepRevisions = keep
+}
+
+// protectedColumns returns the columns Protect was given, followed by the
+// protectable ones UpdateOnly left out, in the order they are set.
+func (s *ArticleStore) protectedColumns() []string {
+	if s.updatable == nil {
+		return s.protected
+	}
+	columns := append([]string(nil), s.protected...)
+	for _, c := range updatedColumns {
+		if _, ok := protectableColumns[c]; !ok {
+			continue
+		}
+		if !slices.Contains(s.updatable, c) && !slices.Contains(columns, c) {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// Upsert inserts the article, or updates it if the stored version is older,
+// except for the protected columns. An article without a status is stored as
+// published, or keeps the stored status, and an archived article stays
+// archived; either way article.Status is set to the stored status.
 func (s *ArticleStore) Upsert(ctx context.Context, article *domain.Article) (int64, error) {
+	return s.upsert(ctx, article, "WHERE articles.last_modified < EXCLUDED.last_modified")
+}
+
+// Replace inserts the article or overwrites the stored one, except for the
+// protected columns, regardless of last_modified. It is used to re-apply a corrected mapping to stored data.
+func (s *ArticleStore) Replace(ctx context.Context, article *domain.Article) (int64, error) {
+	return s.upsert(ctx, article, "")
+}
+
+func (s *ArticleStore) upsert(ctx context.Context, article *domain.Article, updateCond string) (int64, error) {
+	// The stored values of the protected columns are read back into article.
+	protected := s.protectedColumns()
+	returned := strings.Join(append([]string{"id", "status"}, protected...), ", ")
+	var id int64
+	dest := []any{&id, &article.Status}
+	for _, c := range protected {
+		dest = append(dest, protectableColumns[c](article))
+	}
+
 	query := `
 		INSERT INTO articles (
 			source_id, external_id, title, description, summary, body, author,
-			canonical_url, image_url, published_at, last_modified, duration
+			canonical_url, image_url, published_at, last_modified, duration, category, media, language,
+			content_hash, reading_time, tags, status, external_key
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18,
+			COALESCE(NULLIF($19, ''), 'published'), $20
 		)
-		ON CONFLICT (source_id, external_id) DO UPDATE SET
-			title = EXCLUDED.title,
-			description = EXCLUDED.description,
-			summary = EXCLUDED.summary,
-			body = EXCLUDED.body,
-			author = EXCLUDED.author,
-			canonical_url = EXCLUDED.canonical_url,
-			image_url = EXCLUDED.image_url,
-			last_modified = EXCLUDED.last_modified,
-			duration = EXCLUDED.duration
-		WHERE articles.last_modified < EXCLUDED.last_modified
-		RETURNING id`
+		ON CONFLICT (source_id, external_key) DO UPDATE SET
+			` + updateSet(protected) + `,
+			-- An empty status keeps the stored one, and archived articles
+			-- stay archived.
+			status = CASE
+				WHEN $19 = '' OR articles.status = 'archived' THEN articles.status
+				ELSE EXCLUDED.status
+			END
+		` + updateCond + `
+		RETURNING ` + returned
+	if s.keepRevisions {
+		// prior sees the row as it was before the update; it is only
+		// recorded if the update happened.
+		query = `
+			WITH prior AS (
+				SELECT id, title, summary, body, last_modified FROM articles
+				WHERE source_id = $1 AND external_key = $20
+				FOR UPDATE
+			), upserted AS (` + query + `
+			), revision AS (
+				INSERT INTO article_revisions (article_id, title, summary, body, last_modified)
+				SELECT prior.id, prior.title, prior.summary, prior.body, prior.last_modified
+				FROM prior JOIN upserted ON upserted.id = prior.id
+			)
+			SELECT ` + returned + ` FROM upserted`
+	}
 
-	var id int64
-	err := s.db.QueryRowContext(ctx, query,
+	media, err := marshalMedia(article.Media)
+	if err != nil {
+		return 0, err
+	}
+	tags, err := marshalTags(article.Tags)
+	if err != nil {
+		return 0, err
+	}
+
+	err = GetExecutor(ctx, s.db).QueryRowxContext(ctx, query,
 		article.SourceID,
 		article.ExternalID,
 		article.Title,
@@ -54,13 +219,21 @@ func (s *ArticleStore) Upsert(ctx context.Context, article *domain.Article) (int
 		article.PublishedAt,
 		article.LastModified,
 		article.Duration,
-	).Scan(&id)
+		article.Category,
+		media,
+		article.Language,
+		article.ContentHash(),
+		article.ReadingTime,
+		tags,
+		article.Status,
+		article.Key(),
+	).Scan(dest...)
 
 	if err == sql.ErrNoRows {
-		err = s.db.QueryRowContext(ctx,
-			"SELECT id FROM articles WHERE source_id = $1 AND external_id = $2",
-			article.SourceID, article.ExternalID,
-		).Scan(&id)
+		err = GetExecutor(ctx, s.db).QueryRowxContext(ctx,
+			"SELECT "+returned+" FROM articles WHERE source_id = $1 AND external_key = $2",
+			article.SourceID, article.Key(),
+		).Scan(dest...)
 	}
 
 	if err != nil {
@@ -70,28 +243,508 @@ func (s *ArticleStore) Upsert(ctx context.Context, article *domain.Article) (int
 	return id, nil
 }
 
-func (s *ArticleStore) GetExistingBySourceAndExternalIDs(ctx context.Context, sourceID string, ids []int64) (map[int64]time.Time, error) {
-	if len(ids) == 0 {
-		return make(map[int64]time.Time), nil
+// updateSet returns the SET assignments of the columns an upsert overwrites,
+// all but the protected ones. The column names only ever come from
+// updatedColumns.
+func updateSet(protected []string) string {
+	set := make([]string, 0, len(updatedColumns))
+	for _, c := range updatedColumns {
+		if !slices.Contains(protected, c) {
+			set = append(set, c+" = EXCLUDED."+c)
+		}
+	}
+	return strings.Join(set, ",\n\t\t\t")
+}
+
+// existingArticlesQuery runs on every sync; it must use the unique
+// (source_id, external_key) index.
+const existingArticlesQuery = `SELECT external_key, id, last_modified, content_hash, publish_pending FROM articles WHERE source_id = $1 AND external_key = ANY($2)`
+
+// GetExisting returns the stored articles of a source among the given keys
+// (see domain.Article.Key), keyed by key.
+func (s *ArticleStore) GetExisting(ctx context.Context, sourceID string, keys []string) (map[string]domain.ExistingArticle, error) {
+	if len(keys) == 0 {
+		return make(map[string]domain.ExistingArticle), nil
+	}
+
+	return s.queryExisting(ctx, existingArticlesQuery, sourceID, pq.Array(keys))
+}
+
+// GetExistingSince is GetExisting limited to the stored articles last modified
+// at or after since, which lets an incremental sync scan fewer rows. Articles
+// last stored before since are left out even if they are among keys.
+func (s *ArticleStore) GetExistingSince(ctx context.Context, sourceID string, keys []string, since time.Time) (map[string]domain.ExistingArticle, error) {
+	if len(keys) == 0 {
+		return make(map[string]domain.ExistingArticle), nil
 	}
 
-	query := `SELECT external_id, last_modified FROM articles WHERE source_id = $1 AND external_id = ANY($2)`
+	return s.queryExisting(ctx, existingArticlesQuery+" AND last_modified >= $3", sourceID, pq.Array(keys), since)
+}
 
-	rows, err := s.db.QueryContext(ctx, query, sourceID, pq.Array(ids))
+func (s *ArticleStore) queryExisting(ctx context.Context, query string, args ...any) (map[string]domain.ExistingArticle, error) {
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	result := make(map[int64]time.Time)
+	result := make(map[string]domain.ExistingArticle)
 	for rows.Next() {
-		var extID int64
-		var lastMod time.Time
-		if err := rows.Scan(&extID, &lastMod); err != nil {
+		var key string
+		var existing domain.ExistingArticle
+		if err := rows.Scan(&key, &existing.ID, &existing.LastModified, &existing.ContentHash, &existing.PublishPending); err != nil {
 			return nil, err
 		}
-		result[extID] = lastMod
+		result[key] = existing
 	}
 
 	return result, rows.Err()
+}
+
+// GetRevisions returns the recorded prior versions of an article, newest
+// first; see KeepRevisions.
+func (s *ArticleStore) GetRevisions(ctx context.Context, id int64) ([]domain.ArticleRevision, error) {
+	revisions := []domain.ArticleRevision{}
+	err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &revisions, `
+		SELECT id, article_id, title, summary, body, last_modified, created_at
+		FROM article_revisions
+		WHERE article_id = $1
+		ORDER BY id DESC`, id)
+	if err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// SetPublishPending records whether the stored version of an article still
+// has to be published.
+func (s *ArticleStore) SetPublishPending(ctx context.Context, id int64, pending bool) error {
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, "UPDATE articles SET publish_pending = $2 WHERE id = $1", id, pending)
+	return err
+}
+
+// ListPublishPending returns the articles of a source, with their tags, whose
+// last update was stored but not yet published; see SetPublishPending.
+func (s *ArticleStore) ListPublishPending(ctx context.Context, sourceID string) ([]domain.Article, error) {
+	query := "SELECT " + articleColumns + " FROM articles WHERE source_id = $1 AND publish_pending ORDER BY published_at, external_id, id"
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, query, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// SetStatus changes the status of an article. It returns domain.ErrNotFound if
+// there is no article with that id.
+func (s *ArticleStore) SetStatus(ctx context.Context, id int64, status string) error {
+	if !domain.ValidStatus(status) {
+		return fmt.Errorf("invalid article status %q", status)
+	}
+
+	result, err := GetExecutor(ctx, s.db).ExecContext(ctx, "UPDATE articles SET status = $2 WHERE id = $1", id, status)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ErrNoUniqueConstraint is returned by CheckSchema if articles lacks the
+// unique constraint on (source_id, external_key), Upsert's ON CONFLICT target.
+var ErrNoUniqueConstraint = errors.New("articles is missing a unique constraint; apply the migrations")
+
+// uniqueKey are the columns CheckSchema expects a unique constraint on,
+// sorted by name.
+var uniqueKey = []string{"external_key", "source_id"}
+
+// CheckSchema checks that the articles table has what Upsert relies on, so a
+// database that was never fully migrated fails at startup rather than with a
+// Postgres error on the first upsert.
+func (s *ArticleStore) CheckSchema(ctx context.Context) error {
+	// A unique index without a predicate on exactly these two columns, in
+	// either order, can serve as the conflict target.
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_index i
+			WHERE i.indrelid = 'articles'::regclass
+				AND i.indisunique
+				AND i.indpred IS NULL
+				AND i.indnatts = 2
+				AND (
+					SELECT array_agg(a.attname::text ORDER BY a.attname)
+					FROM pg_attribute a
+					WHERE a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+				) = $1::text[]
+		)`
+
+	var ok bool
+	if err := GetExecutor(ctx, s.db).QueryRowxContext(ctx, query, pq.Array(uniqueKey)).Scan(&ok); err != nil {
+		return fmt.Errorf("check articles schema: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: (source_id, external_key)", ErrNoUniqueConstraint)
+	}
+	return nil
+}
+
+// CountBySource returns how many articles of a source are stored.
+func (s *ArticleStore) CountBySource(ctx context.Context, sourceID string) (int64, error) {
+	var n int64
+	err := GetExecutor(ctx, s.db).QueryRowxContext(ctx, "SELECT COUNT(*) FROM articles WHERE source_id = $1", sourceID).Scan(&n)
+	return n, err
+}
+
+// CountByDateRange returns how many articles of a source published at or after
+// from and before to are stored. A zero from or to leaves that end open, as in
+// domain.ArticleFilter.
+func (s *ArticleStore) CountByDateRange(ctx context.Context, sourceID string, from, to time.Time) (int64, error) {
+	conds := []string{"source_id = $1"}
+	args := []interface{}{sourceID}
+	if !from.IsZero() {
+		args = append(args, from)
+		conds = append(conds, "published_at >= $"+itoa(len(args)))
+	}
+	if !to.IsZero() {
+		args = append(args, to)
+		conds = append(conds, "published_at < $"+itoa(len(args)))
+	}
+
+	var n int64
+	err := GetExecutor(ctx, s.db).QueryRowxContext(ctx, "SELECT COUNT(*) FROM articles WHERE "+strings.Join(conds, " AND "), args...).Scan(&n)
+	return n, err
+}
+
+// DeleteOlderThan deletes a source's articles published before cutoff and
+// returns how many it deleted. Their tag links go with them (ON DELETE
+// CASCADE), and so do their raw payloads, which reprocess would otherwise
+// turn back into articles.
+func (s *ArticleStore) DeleteOlderThan(ctx context.Context, sourceID string, cutoff time.Time) (int64, error) {
+	query := `
+		WITH deleted AS (
+			DELETE FROM articles
+			WHERE source_id = $1 AND published_at < $2
+			RETURNING external_key
+		), deleted_payloads AS (
+			DELETE FROM raw_payloads
+			WHERE source_id = $1 AND external_key IN (SELECT external_key FROM deleted)
+		)
+		SELECT COUNT(*) FROM deleted`
+
+	var n int64
+	if err := GetExecutor(ctx, s.db).QueryRowxContext(ctx, query, sourceID, cutoff).Scan(&n); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Analyze refreshes the planner statistics of the article tables, e.g. after
+// DeleteOlderThan removed many rows; with vacuum set it runs VACUUM ANALYZE,
+// which also makes the space of the deleted rows reusable. VACUUM can't run
+// inside a transaction, so Analyze refuses to when ctx carries one. It isn't
+// subject to the configured statement_timeout.
+func (s *ArticleStore) Analyze(ctx context.Context, vacuum bool) error {
+	if GetTxFromContext(ctx) != nil {
+		return errors.New("analyze articles: must not run inside a transaction")
+	}
+
+	command := "ANALYZE"
+	if vacuum {
+		command = "VACUUM ANALYZE"
+	}
+
+	// It runs without the pool's statement_timeout, on a connection of its
+	// own, whose timeout is restored before it goes back to the pool.
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if _, err := conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "RESET statement_timeout"); err != nil {
+			// Not to be reused without its timeout.
+			_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+		}
+	}()
+
+	_, err = conn.ExecContext(ctx, command+" articles, article_tags, raw_payloads")
+	return err
+}
+
+const articleColumns = `id, source_id, external_id, external_key, title, description, summary, body, author,
+	canonical_url, image_url, published_at, last_modified, duration, category, media, language, reading_time, status, created_at, updated_at`
+
+func (s *ArticleStore) GetByID(ctx context.Context, id int64) (*domain.Article, error) {
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, "SELECT "+articleColumns+" FROM articles WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(articles) == 0 {
+		return nil, domain.ErrNotFound
+	}
+
+	if err := s.loadTags(ctx, articles); err != nil {
+		return nil, err
+	}
+	return &articles[0], nil
+}
+
+// List returns articles matching the filter, with their tags, ordered by
+// published_at, external_id and id; see domain.ArticleCursor.
+func (s *ArticleStore) List(ctx context.Context, filter domain.ArticleFilter) ([]domain.Article, error) {
+	var conds []string
+	var args []interface{}
+
+	if filter.SourceID != "" {
+		args = append(args, filter.SourceID)
+		conds = append(conds, "source_id = $"+itoa(len(args)))
+	}
+	if filter.Category != "" {
+		args = append(args, filter.Category)
+		conds = append(conds, "category = $"+itoa(len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conds = append(conds, "published_at >= $"+itoa(len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conds = append(conds, "published_at < $"+itoa(len(args)))
+	}
+
+	if filter.After != nil {
+		args = append(args, filter.After.PublishedAt, filter.After.ExternalID, filter.After.ID)
+		n := len(args)
+		conds = append(conds, "(published_at, external_id, id) > ($"+itoa(n-2)+", $"+itoa(n-1)+", $"+itoa(n)+")")
+	}
+
+	query := "SELECT " + articleColumns + " FROM articles"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	// Ties on published_at are broken by external_id (and id, across sources)
+	// so that listings and their pages are reproducible.
+	query += " ORDER BY published_at, external_id, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + itoa(len(args))
+	}
+
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := scanArticles(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadTags(ctx, articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+func scanArticles(rows *sql.Rows) ([]domain.Article, error) {
+	defer rows.Close()
+
+	var articles []domain.Article
+	for rows.Next() {
+		var a domain.Article
+		var media []byte
+		var key string
+		if err := rows.Scan(
+			&a.ID,
+			&a.SourceID,
+			&a.ExternalID,
+			&key,
+			&a.Title,
+			&a.Description,
+			&a.Summary,
+			&a.Body,
+			&a.Author,
+			&a.CanonicalURL,
+			&a.ImageURL,
+			&a.PublishedAt,
+			&a.LastModified,
+			&a.Duration,
+			&a.Category,
+			&media,
+			&a.Language,
+			&a.ReadingTime,
+			&a.Status,
+			&a.CreatedAt,
+			&a.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(media, &a.Media); err != nil {
+			return nil, fmt.Errorf("decode media of article %d: %w", a.ID, err)
+		}
+		if len(a.Media) == 0 {
+			a.Media = nil
+		}
+		// Articles keyed by their ExternalID have no ExternalKey of their own.
+		if key != a.Key() {
+			a.ExternalKey = key
+		}
+		articles = append(articles, a)
+	}
+
+	return articles, rows.Err()
+}
+
+// marshalMedia encodes media for the JSONB column, which holds [] rather than null.
+func marshalMedia(media []domain.MediaItem) ([]byte, error) {
+	if media == nil {
+		media = []domain.MediaItem{}
+	}
+	data, err := json.Marshal(media)
+	if err != nil {
+		return nil, fmt.Errorf("encode media: %w", err)
+	}
+	return data, nil
+}
+
+// marshalTags encodes the article's tags for the JSONB column, which holds []
+// rather than null.
+func marshalTags(tags []domain.Tag) ([]byte, error) {
+	if tags == nil {
+		tags = []domain.Tag{}
+	}
+	data, err := json.Marshal(tags)
+	if err != nil {
+		return nil, fmt.Errorf("encode tags: %w", err)
+	}
+	return data, nil
+}
+
+// ListTagMismatches returns up to limit articles with an id greater than
+// afterID, ordered by id, whose article_tags links differ from the tags
+// recorded when they were saved. Only ID, SourceID, ExternalID and Tags (the
+// recorded ones) are set.
+func (s *ArticleStore) ListTagMismatches(ctx context.Context, afterID int64, limit int) ([]domain.Article, error) {
+	query := listTagMismatchesByID
+	if s.tagsByLabel {
+		key := "t->>'label'"
+		if s.normalizeTagLabels {
+			// As domain.NormalizeTagLabel.
+			key = `lower(btrim(regexp_replace(t->>'label', '\s+', ' ', 'g')))`
+		}
+		query = fmt.Sprintf(listTagMismatchesByLabel, key, key)
+	}
+
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var articles []domain.Article
+	for rows.Next() {
+		var a domain.Article
+		var tags []byte
+		if err := rows.Scan(&a.ID, &a.SourceID, &a.ExternalID, &tags); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(tags, &a.Tags); err != nil {
+			return nil, fmt.Errorf("decode tags of article %d: %w", a.ID, err)
+		}
+		articles = append(articles, a)
+	}
+
+	return articles, rows.Err()
+}
+
+const (
+	listTagMismatchesByID = `
+		SELECT a.id, a.source_id, a.external_id, a.tags
+		FROM articles a
+		WHERE a.id > $1
+			AND (
+				SELECT array_agg(DISTINCT (t->>'id')::bigint ORDER BY (t->>'id')::bigint)
+				FROM jsonb_array_elements(a.tags) t
+			) IS DISTINCT FROM (
+				SELECT array_agg(at.tag_id ORDER BY at.tag_id)
+				FROM article_tags at
+				WHERE at.article_id = a.id
+			)
+		ORDER BY a.id
+		LIMIT $2`
+
+	// listTagMismatchesByLabel is formatted with the key of a recorded tag t,
+	// twice.
+	listTagMismatchesByLabel = `
+		SELECT a.id, a.source_id, a.external_id, a.tags
+		FROM articles a
+		WHERE a.id > $1
+			AND (
+				SELECT array_agg(DISTINCT %s ORDER BY %s)
+				FROM jsonb_array_elements(a.tags) t
+			) IS DISTINCT FROM (
+				SELECT array_agg(DISTINCT tg.label_key ORDER BY tg.label_key)
+				FROM article_tags at
+				INNER JOIN tags tg ON tg.id = at.tag_id
+				WHERE at.article_id = a.id
+			)
+		ORDER BY a.id
+		LIMIT $2`
+)
+
+func (s *ArticleStore) loadTags(ctx context.Context, articles []domain.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(articles))
+	index := make(map[int64]int, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+		index[a.ID] = i
+	}
+
+	query := `
+		SELECT at.article_id, t.id, t.label
+		FROM article_tags at
+		INNER JOIN tags t ON t.id = at.tag_id
+		WHERE at.article_id = ANY($1)
+		ORDER BY at.article_id, t.id`
+
+	rows, err := GetExecutor(ctx, s.db).QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int64
+		var tag domain.Tag
+		if err := rows.Scan(&articleID, &tag.ID, &tag.Label); err != nil {
+			return err
+		}
+		i := index[articleID]
+		articles[i].Tags = append(articles[i].Tags, tag)
+	}
+
+	return rows.Err()
 }
\ No newline at end of file
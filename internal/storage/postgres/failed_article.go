@@ -0,0 +1,120 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"news_fetcher/internal/domain"
+)
+
+type FailedArticleStore struct {
+	db *sqlx.DB
+}
+
+func NewFailedArticleStore(db *sqlx.DB) *FailedArticleStore {
+	return &FailedArticleStore{db: db}
+}
+
+// Failing returns the keys of a source's articles with recorded failures,
+// mapped to whether they are quarantined.
+func (s *FailedArticleStore) Failing(ctx context.Context, sourceID string) (map[string]bool, error) {
+	query := `
+		SELECT external_key, quarantined_at IS NOT NULL AS quarantined
+		FROM failed_articles
+		WHERE source_id = $1`
+
+	var rows []struct {
+		ExternalKey string `db:"external_key"`
+		Quarantined bool   `db:"quarantined"`
+	}
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &rows, query, sourceID); err != nil {
+		return nil, err
+	}
+
+	failing := make(map[string]bool, len(rows))
+	for _, r := range rows {
+		failing[r.ExternalKey] = r.Quarantined
+	}
+	return failing, nil
+}
+
+// RecordFailure counts a failed save of article and quarantines it once it has
+// failed maxAttempts times. It reports whether the article is quarantined.
+func (s *FailedArticleStore) RecordFailure(ctx context.Context, article *domain.Article, cause error, maxAttempts int) (bool, error) {
+	payload, err := json.Marshal(article)
+	if err != nil {
+		return false, fmt.Errorf("marshal article: %w", err)
+	}
+
+	query := `
+		INSERT INTO failed_articles (source_id, external_id, external_key, attempts, last_error, article, quarantined_at)
+		VALUES ($1, $2, $3, 1, $4, $5, CASE WHEN $6 <= 1 THEN NOW() END)
+		ON CONFLICT (source_id, external_key) DO UPDATE SET
+			attempts = failed_articles.attempts + 1,
+			last_error = EXCLUDED.last_error,
+			article = EXCLUDED.article,
+			last_failed_at = NOW(),
+			quarantined_at = COALESCE(
+				failed_articles.quarantined_at,
+				CASE WHEN failed_articles.attempts + 1 >= $6 THEN NOW() END
+			)
+		RETURNING quarantined_at IS NOT NULL`
+
+	var quarantined bool
+	err = sqlx.GetContext(ctx, GetExecutor(ctx, s.db), &quarantined, query,
+		article.SourceID,
+		article.ExternalID,
+		article.Key(),
+		cause.Error(),
+		payload,
+		maxAttempts,
+	)
+	return quarantined, err
+}
+
+// Clear forgets the failures of the article with the given key, e.g. once it
+// has been saved.
+func (s *FailedArticleStore) Clear(ctx context.Context, sourceID, key string) error {
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx,
+		"DELETE FROM failed_articles WHERE source_id = $1 AND external_key = $2",
+		sourceID, key,
+	)
+	return err
+}
+
+// ListQuarantined returns the quarantined articles of a source, or of every
+// source if sourceID is empty, ordered by when they were quarantined.
+func (s *FailedArticleStore) ListQuarantined(ctx context.Context, sourceID string) ([]domain.FailedArticle, error) {
+	query := `
+		SELECT source_id, external_id, external_key, attempts, last_error, article,
+			first_failed_at, last_failed_at, quarantined_at
+		FROM failed_articles
+		WHERE quarantined_at IS NOT NULL AND ($1 = '' OR source_id = $1)
+		ORDER BY quarantined_at, source_id, external_key`
+
+	var failed []domain.FailedArticle
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &failed, query, sourceID); err != nil {
+		return nil, err
+	}
+	return failed, nil
+}
+
+// ClearQuarantine releases quarantined articles of a source so the next sync
+// retries them: the ones with the given keys, or all of them if there are
+// none. It returns how many were released.
+func (s *FailedArticleStore) ClearQuarantine(ctx context.Context, sourceID string, keys []string) (int64, error) {
+	query := `
+		DELETE FROM failed_articles
+		WHERE source_id = $1 AND quarantined_at IS NOT NULL
+			AND (COALESCE(cardinality($2::text[]), 0) = 0 OR external_key = ANY($2))`
+
+	result, err := GetExecutor(ctx, s.db).ExecContext(ctx, query, sourceID, pq.Array(keys))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -4,18 +4,28 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"maps"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/suite"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/modules/postgres"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"news_fetcher/internal/config"
 	"news_fetcher/internal/domain"
+	"news_fetcher/internal/service"
 	"news_fetcher/testdata/utils"
 )
 
@@ -40,6 +50,31 @@ func (s *PostgresIntegrationSuite) SetupSuite() {
 		postgres.WithInitScripts(
 			filepath.Join(migrationsPath, "001_create_articles.up.sql"),
 			filepath.Join(migrationsPath, "002_add_source_id.up.sql"),
+			filepath.Join(migrationsPath, "003_article_timestamps_not_null.up.sql"),
+			filepath.Join(migrationsPath, "004_add_category.up.sql"),
+			filepath.Join(migrationsPath, "005_add_media.up.sql"),
+			filepath.Join(migrationsPath, "006_create_raw_payloads.up.sql"),
+			filepath.Join(migrationsPath, "007_add_language.up.sql"),
+			filepath.Join(migrationsPath, "008_add_content_hash.up.sql"),
+			filepath.Join(migrationsPath, "009_articles_lookup_index.up.sql"),
+			filepath.Join(migrationsPath, "010_add_reading_time.up.sql"),
+			filepath.Join(migrationsPath, "011_create_failed_articles.up.sql"),
+			filepath.Join(migrationsPath, "012_add_article_tags.up.sql"),
+			filepath.Join(migrationsPath, "013_create_paused_sources.up.sql"),
+			filepath.Join(migrationsPath, "014_create_source_health.up.sql"),
+			filepath.Join(migrationsPath, "015_add_publish_pending.up.sql"),
+			filepath.Join(migrationsPath, "016_add_article_status.up.sql"),
+			filepath.Join(migrationsPath, "017_add_tag_label_scope.up.sql"),
+			filepath.Join(migrationsPath, "018_add_tag_label_key.up.sql"),
+			filepath.Join(migrationsPath, "019_add_source_health_check.up.sql"),
+			filepath.Join(migrationsPath, "020_add_sync_state_last_published.up.sql"),
+			filepath.Join(migrationsPath, "021_articles_source_published_index.up.sql"),
+			filepath.Join(migrationsPath, "022_create_article_revisions.up.sql"),
+			filepath.Join(migrationsPath, "023_create_publish_sequences.up.sql"),
+			filepath.Join(migrationsPath, "024_articles_source_last_modified_index.up.sql"),
+			filepath.Join(migrationsPath, "025_add_article_external_key.up.sql"),
+			filepath.Join(migrationsPath, "026_key_by_external_key.up.sql"),
+			filepath.Join(migrationsPath, "027_articles_publish_pending_index.up.sql"),
 		),
 		testcontainers.WithWaitStrategy(
 			wait.ForLog("database system is ready to accept connections").
@@ -68,10 +103,16 @@ func (s *PostgresIntegrationSuite) TearDownSuite() {
 }
 
 func (s *PostgresIntegrationSuite) SetupTest() {
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM article_revisions")
 	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM article_tags")
 	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM tags")
 	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM articles")
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM raw_payloads")
 	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM sync_state")
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM failed_articles")
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM paused_sources")
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM source_health")
+	_, _ = s.db.ExecContext(s.ctx, "DELETE FROM publish_sequences")
 }
 
 func TestPostgresIntegrationSuite(t *testing.T) {
@@ -168,6 +209,8 @@ func (s *PostgresIntegrationSuite) TestArticleStore_GetExisting_ReturnsCorrectMa
 	store := NewArticleStore(s.db)
 	now := time.Now().Truncate(time.Microsecond)
 
+	ids := make(map[string]int64)
+	hashes := make(map[string]string)
 	for i := int64(1); i <= 3; i++ {
 		article := &domain.Article{
 			SourceID:     "test-source",
@@ -177,17 +220,197 @@ func (s *PostgresIntegrationSuite) TestArticleStore_GetExisting_ReturnsCorrectMa
 			PublishedAt:  now,
 			LastModified: now.Add(time.Duration(i) * time.Hour),
 		}
-		_, err := store.Upsert(s.ctx, article)
+		hashes[article.Key()] = article.ContentHash()
+		id, err := store.Upsert(s.ctx, article)
 		s.NoError(err)
+		ids[article.Key()] = id
 	}
 
-	result, err := store.GetExistingBySourceAndExternalIDs(s.ctx, "test-source", []int64{100, 200, 999})
+	result, err := store.GetExisting(s.ctx, "test-source", []string{"100", "200", "999"})
 	s.NoError(err)
 	s.Len(result, 2)
 
-	s.Contains(result, int64(100))
-	s.Contains(result, int64(200))
-	s.NotContains(result, int64(999))
+	s.Contains(result, "100")
+	s.Contains(result, "200")
+	s.NotContains(result, "999")
+
+	s.Equal(ids["200"], result["200"].ID)
+	s.True(result["200"].LastModified.Equal(now.Add(2 * time.Hour)))
+	s.Equal(hashes["200"], result["200"].ContentHash)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_GetExistingSince() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	since := now.Add(2 * time.Hour)
+
+	for i := int64(1); i <= 4; i++ {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     "test-source",
+			ExternalID:   i * 100,
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  now,
+			LastModified: now.Add(time.Duration(i) * time.Hour),
+		})
+		s.Require().NoError(err)
+	}
+	keys := []string{"100", "200", "300", "400", "999"}
+
+	all, err := store.GetExisting(s.ctx, "test-source", keys)
+	s.Require().NoError(err)
+	bounded, err := store.GetExistingSince(s.ctx, "test-source", keys, since)
+	s.Require().NoError(err)
+
+	// The bounded lookup returns the same stored versions, minus the ones last
+	// modified before since.
+	want := make(map[string]domain.ExistingArticle)
+	for key, existing := range all {
+		if !existing.LastModified.Before(since) {
+			want[key] = existing
+		}
+	}
+	s.Len(all, 4)
+	s.Equal(want, bounded)
+	s.ElementsMatch([]string{"200", "300", "400"}, slices.Collect(maps.Keys(bounded)))
+
+	empty, err := store.GetExistingSince(s.ctx, "test-source", nil, since)
+	s.Require().NoError(err)
+	s.Empty(empty)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_SetPublishPending() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   100,
+		Title:        "Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	existing, err := store.GetExisting(s.ctx, "test-source", []string{"100"})
+	s.Require().NoError(err)
+	s.False(existing["100"].PublishPending)
+
+	s.Require().NoError(store.SetPublishPending(s.ctx, id, true))
+	// Saving another version doesn't clear it.
+	article.LastModified = now.Add(time.Second)
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	existing, err = store.GetExisting(s.ctx, "test-source", []string{"100"})
+	s.Require().NoError(err)
+	s.True(existing["100"].PublishPending)
+
+	pending, err := store.ListPublishPending(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Require().Len(pending, 1)
+	s.Equal(id, pending[0].ID)
+	s.True(pending[0].LastModified.Equal(now.Add(time.Second)))
+	other, err := store.ListPublishPending(s.ctx, "other-source")
+	s.Require().NoError(err)
+	s.Empty(other)
+
+	s.Require().NoError(store.SetPublishPending(s.ctx, id, false))
+	existing, err = store.GetExisting(s.ctx, "test-source", []string{"100"})
+	s.Require().NoError(err)
+	s.False(existing["100"].PublishPending)
+	pending, err = store.ListPublishPending(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Empty(pending)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Status() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   100,
+		Title:        "Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	s.Equal(domain.StatusPublished, article.Status)
+
+	s.Require().NoError(store.SetStatus(s.ctx, id, domain.StatusDraft))
+	stored, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal(domain.StatusDraft, stored.Status)
+
+	// A sync, which doesn't set a status, keeps the one set by hand.
+	update := *article
+	update.Status = ""
+	update.LastModified = now.Add(time.Minute)
+	_, err = store.Upsert(s.ctx, &update)
+	s.Require().NoError(err)
+	s.Equal(domain.StatusDraft, update.Status)
+
+	s.ErrorIs(store.SetStatus(s.ctx, id+1000, domain.StatusArchived), domain.ErrNotFound)
+	s.Error(store.SetStatus(s.ctx, id, "deleted"))
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_UpsertKeepsArchived() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   100,
+		Title:        "Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	s.Require().NoError(store.SetStatus(s.ctx, id, domain.StatusArchived))
+
+	// Neither a sync nor an explicit status clobbers the archived status.
+	for i, status := range []string{"", domain.StatusPublished} {
+		update := *article
+		update.Title = "Updated"
+		update.Status = status
+		update.LastModified = now.Add(time.Duration(i+1) * time.Minute)
+		_, err = store.Upsert(s.ctx, &update)
+		s.Require().NoError(err)
+		s.Equal(domain.StatusArchived, update.Status)
+	}
+
+	replaced := *article
+	replaced.Status = domain.StatusPublished
+	_, err = store.Replace(s.ctx, &replaced)
+	s.Require().NoError(err)
+
+	stored, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal(domain.StatusArchived, stored.Status)
+	s.Equal("Article", stored.Title)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_GetExisting_UsesIndex() {
+	_, err := s.db.ExecContext(s.ctx, `
+		INSERT INTO articles (source_id, external_id, title, canonical_url, published_at, last_modified)
+		SELECT 'source' || (g % 4), g, 'Article', 'https://example.com/article', NOW(), NOW()
+		FROM generate_series(1, 20000) g`)
+	s.Require().NoError(err)
+	_, err = s.db.ExecContext(s.ctx, "ANALYZE articles")
+	s.Require().NoError(err)
+
+	var plan []string
+	err = s.db.SelectContext(s.ctx, &plan, "EXPLAIN "+existingArticlesQuery, "source1", pq.Array([]string{"1", "5", "9", "13"}))
+	s.Require().NoError(err)
+
+	joined := strings.Join(plan, "\n")
+	s.Contains(joined, "articles_source_external_key_unique", joined)
+	s.NotContains(joined, "Seq Scan", joined)
 }
 
 func (s *PostgresIntegrationSuite) TestArticleStore_GetExisting_DifferentSources() {
@@ -216,57 +439,1127 @@ func (s *PostgresIntegrationSuite) TestArticleStore_GetExisting_DifferentSources
 	_, err = store.Upsert(s.ctx, article2)
 	s.NoError(err)
 
-	result, err := store.GetExistingBySourceAndExternalIDs(s.ctx, "source1", []int64{100})
+	result, err := store.GetExisting(s.ctx, "source1", []string{"100"})
 	s.NoError(err)
 	s.Len(result, 1)
 
-	result, err = store.GetExistingBySourceAndExternalIDs(s.ctx, "source2", []int64{100})
+	result, err = store.GetExisting(s.ctx, "source2", []string{"100"})
 	s.NoError(err)
 	s.Len(result, 1)
 
-	result, err = store.GetExistingBySourceAndExternalIDs(s.ctx, "source3", []int64{100})
+	result, err = store.GetExisting(s.ctx, "source3", []string{"100"})
 	s.NoError(err)
 	s.Len(result, 0)
 }
 
 
-func (s *PostgresIntegrationSuite) TestTagStore_UpsertBatch() {
-	store := NewTagStore(s.db)
+func (s *PostgresIntegrationSuite) TestArticleStore_GetByID() {
+	store := NewArticleStore(s.db)
+	tagStore := NewTagStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
 
-	tags := []domain.Tag{
-		{ID: 1, Label: "tag1"},
-		{ID: 2, Label: "tag2"},
-		{ID: 3, Label: "tag3"},
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   123,
+		Title:        "Test Article",
+		Body:         utils.Ptr("Test Body"),
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+		Duration:     60,
 	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
 
-	err := store.UpsertBatch(s.ctx, tags)
+	err = tagStore.UpsertBatch(s.ctx, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}})
+	s.Require().NoError(err)
+	err = tagStore.LinkToArticle(s.ctx, id, []int64{1, 2})
+	s.Require().NoError(err)
+
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal(id, got.ID)
+	s.Equal("test-source", got.SourceID)
+	s.Equal(int64(123), got.ExternalID)
+	s.Equal("Test Article", got.Title)
+	s.Equal("Test Body", *got.Body)
+	s.Nil(got.Description)
+	s.Equal(60, got.Duration)
+	s.WithinDuration(now, got.PublishedAt, time.Second)
+	s.Equal([]domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}}, got.Tags)
+	s.Nil(got.Media)
+
+	_, err = store.GetByID(s.ctx, id+1000)
+	s.ErrorIs(err, domain.ErrNotFound)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Timestamps() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   124,
+		Title:        "Original",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	inserted, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.False(inserted.CreatedAt.IsZero())
+	s.False(inserted.UpdatedAt.IsZero())
+
+	article.Title = "Updated"
+	article.LastModified = now.Add(time.Hour)
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	updated, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Updated", updated.Title)
+	s.Equal(inserted.CreatedAt, updated.CreatedAt)
+	s.True(updated.UpdatedAt.After(inserted.UpdatedAt))
+
+	listed, err := store.List(s.ctx, domain.ArticleFilter{SourceID: "test-source"})
+	s.Require().NoError(err)
+	s.Require().Len(listed, 1)
+	s.Equal(updated.UpdatedAt, listed[0].UpdatedAt)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Media() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	media := []domain.MediaItem{
+		{URL: "https://example.com/image.jpg", Type: "image"},
+		{URL: "https://example.com/image-640.jpg", Type: "image", Width: 640, Height: 360},
+	}
+	id, err := store.Upsert(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   125,
+		Title:        "With Media",
+		CanonicalURL: "https://example.com/article",
+		ImageURL:     utils.Ptr("https://example.com/image.jpg"),
+		PublishedAt:  now,
+		LastModified: now,
+		Media:        media,
+	})
+	s.Require().NoError(err)
+
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal(media, got.Media)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_LanguageAndReadingTime() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	id, err := store.Upsert(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   126,
+		Title:        "Localized",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+		Language:     "en-GB",
+		ReadingTime:  4,
+	})
+	s.Require().NoError(err)
+
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("en-GB", got.Language)
+	s.Equal(4, got.ReadingTime)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_List_Filters() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	seed := []struct {
+		sourceID    string
+		externalID  int64
+		publishedAt time.Time
+	}{
+		{"source1", 1, now.Add(-72 * time.Hour)},
+		{"source1", 2, now.Add(-48 * time.Hour)},
+		{"source1", 3, now.Add(-24 * time.Hour)},
+		{"source2", 4, now.Add(-24 * time.Hour)},
+	}
+	for _, a := range seed {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     a.sourceID,
+			ExternalID:   a.externalID,
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  a.publishedAt,
+			LastModified: a.publishedAt,
+		})
+		s.Require().NoError(err)
+	}
+
+	all, err := store.List(s.ctx, domain.ArticleFilter{})
 	s.NoError(err)
+	s.Len(all, 4)
 
-	var count int
-	err = s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM tags")
+	bySource, err := store.List(s.ctx, domain.ArticleFilter{SourceID: "source1"})
 	s.NoError(err)
-	s.Equal(3, count)
+	s.Len(bySource, 3)
+
+	byRange, err := store.List(s.ctx, domain.ArticleFilter{
+		SourceID: "source1",
+		From:     now.Add(-50 * time.Hour),
+		To:       now.Add(-30 * time.Hour),
+	})
+	s.NoError(err)
+	s.Require().Len(byRange, 1)
+	s.Equal(int64(2), byRange[0].ExternalID)
+
+	page, err := store.List(s.ctx, domain.ArticleFilter{Limit: 2})
+	s.NoError(err)
+	s.Require().Len(page, 2)
+
+	rest, err := store.List(s.ctx, domain.ArticleFilter{After: page[1].Cursor()})
+	s.NoError(err)
+	s.Len(rest, 2)
+	s.Equal(int64(3), rest[0].ExternalID)
 }
 
-func (s *PostgresIntegrationSuite) TestTagStore_UpsertBatch_UpdatesExisting() {
-	store := NewTagStore(s.db)
+func (s *PostgresIntegrationSuite) TestArticleStore_List_TiedPublishedAt() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
 
-	tags := []domain.Tag{
-		{ID: 1, Label: "old-label"},
+	// Inserted out of order, all published at the same time.
+	for _, externalID := range []int64{5, 2, 9, 1, 7} {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     "test-source",
+			ExternalID:   externalID,
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  now,
+			LastModified: now,
+		})
+		s.Require().NoError(err)
 	}
-	err := store.UpsertBatch(s.ctx, tags)
+
+	var ids []int64
+	filter := domain.ArticleFilter{Limit: 2}
+	for {
+		page, err := store.List(s.ctx, filter)
+		s.Require().NoError(err)
+		if len(page) == 0 {
+			break
+		}
+		for _, a := range page {
+			ids = append(ids, a.ExternalID)
+		}
+		filter.After = page[len(page)-1].Cursor()
+	}
+
+	s.Equal([]int64{1, 2, 5, 7, 9}, ids)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_List_Category() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	for i, category := range []string{domain.CategoryArticle, domain.CategoryVideo, domain.CategoryVideo} {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     "test-source",
+			ExternalID:   int64(i + 1),
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  now,
+			LastModified: now,
+			Category:     category,
+		})
+		s.Require().NoError(err)
+	}
+
+	videos, err := store.List(s.ctx, domain.ArticleFilter{Category: domain.CategoryVideo})
 	s.NoError(err)
+	s.Require().Len(videos, 2)
+	s.Equal(domain.CategoryVideo, videos[0].Category)
+	s.Equal(int64(2), videos[0].ExternalID)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Replace() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Original",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	// Same last_modified: Upsert keeps the stored row, Replace overwrites it.
+	article.Title = "Remapped"
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Original", got.Title)
+
+	replacedID, err := store.Replace(s.ctx, article)
+	s.Require().NoError(err)
+	s.Equal(id, replacedID)
+	got, err = store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Remapped", got.Title)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_DeleteOlderThan() {
+	store := NewArticleStore(s.db)
+	tagStore := NewTagStore(s.db)
+	payloads := NewRawPayloadStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	cutoff := now.Add(-30 * 24 * time.Hour)
+
+	s.Require().NoError(tagStore.UpsertBatch(s.ctx, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}}))
+	ids := make(map[string]int64)
+	for _, a := range []struct {
+		name        string
+		sourceID    string
+		externalID  int64
+		publishedAt time.Time
+	}{
+		{"old", "ecb", 1, cutoff.Add(-time.Hour)},
+		{"older", "ecb", 2, cutoff.Add(-48 * time.Hour)},
+		{"recent", "ecb", 3, cutoff.Add(time.Hour)},
+		{"other source", "other", 4, cutoff.Add(-time.Hour)},
+	} {
+		id, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     a.sourceID,
+			ExternalID:   a.externalID,
+			Title:        a.name,
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  a.publishedAt,
+			LastModified: a.publishedAt,
+		})
+		s.Require().NoError(err)
+		s.Require().NoError(tagStore.LinkToArticle(s.ctx, id, []int64{1, 2}))
+		s.Require().NoError(payloads.Save(s.ctx, &domain.Article{SourceID: a.sourceID, ExternalID: a.externalID, Raw: []byte(`{}`)}))
+		ids[a.name] = id
+	}
+
+	deleted, err := store.DeleteOlderThan(s.ctx, "ecb", cutoff)
+	s.Require().NoError(err)
+	s.Equal(int64(2), deleted)
+
+	for name, id := range ids {
+		_, err := store.GetByID(s.ctx, id)
+		if name == "old" || name == "older" {
+			s.ErrorIs(err, domain.ErrNotFound, name)
+		} else {
+			s.NoError(err, name)
+		}
+	}
+
+	// The tag links and raw payloads of the deleted articles went with them.
+	var links []int64
+	s.Require().NoError(s.db.SelectContext(s.ctx, &links, "SELECT DISTINCT article_id FROM article_tags ORDER BY article_id"))
+	s.ElementsMatch([]int64{ids["recent"], ids["other source"]}, links)
+	var externalIDs []int64
+	s.Require().NoError(s.db.SelectContext(s.ctx, &externalIDs, "SELECT external_id FROM raw_payloads ORDER BY external_id"))
+	s.Equal([]int64{3, 4}, externalIDs)
+
+	deleted, err = store.DeleteOlderThan(s.ctx, "ecb", cutoff)
+	s.Require().NoError(err)
+	s.Zero(deleted)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_KeepRevisions() {
+	store := NewArticleStore(s.db)
+	store.KeepRevisions(true)
+	v1 := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	article := &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   1,
+		Title:        "First",
+		Summary:      utils.Ptr("Summary 1"),
+		Body:         utils.Ptr("Body 1"),
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  v1,
+		LastModified: v1,
+	}
+
+	// An insert has no prior version.
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	revisions, err := store.GetRevisions(s.ctx, id)
+	s.Require().NoError(err)
+	s.Empty(revisions)
+
+	article.Title, article.Summary, article.Body = "Second", utils.Ptr("Summary 2"), nil
+	article.LastModified = v1.Add(time.Hour)
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	// An upsert that leaves the stored version alone doesn't record one.
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	article.Title = "Third"
+	_, err = store.Replace(s.ctx, article)
+	s.Require().NoError(err)
+
+	revisions, err = store.GetRevisions(s.ctx, id)
+	s.Require().NoError(err)
+	s.Require().Len(revisions, 2)
+	s.Equal(id, revisions[0].ArticleID)
+	s.Equal("Second", revisions[0].Title)
+	s.Equal(utils.Ptr("Summary 2"), revisions[0].Summary)
+	s.Nil(revisions[0].Body)
+	s.True(v1.Add(time.Hour).Equal(revisions[0].LastModified))
+	s.Equal("First", revisions[1].Title)
+	s.Equal(utils.Ptr("Summary 1"), revisions[1].Summary)
+	s.Equal(utils.Ptr("Body 1"), revisions[1].Body)
+	s.True(v1.Equal(revisions[1].LastModified))
+	s.False(revisions[1].CreatedAt.IsZero())
+
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Third", got.Title)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_RevisionsOff() {
+	store := NewArticleStore(s.db)
+	v1 := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	article := &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   1,
+		Title:        "First",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  v1,
+		LastModified: v1,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	article.Title = "Second"
+	article.LastModified = v1.Add(time.Hour)
+	_, err = store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	revisions, err := store.GetRevisions(s.ctx, id)
+	s.Require().NoError(err)
+	s.Empty(revisions)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_CheckSchema() {
+	store := NewArticleStore(s.db)
+	s.Require().NoError(store.CheckSchema(s.ctx))
+
+	_, err := s.db.ExecContext(s.ctx, "ALTER TABLE articles DROP CONSTRAINT articles_source_external_key_unique")
+	s.Require().NoError(err)
+	defer func() {
+		_, err := s.db.ExecContext(s.ctx, "ALTER TABLE articles ADD CONSTRAINT articles_source_external_key_unique UNIQUE (source_id, external_key)")
+		s.Require().NoError(err)
+	}()
+
+	err = store.CheckSchema(s.ctx)
+	s.ErrorIs(err, ErrNoUniqueConstraint)
+	s.ErrorContains(err, "(source_id, external_key)")
+
+	// A plain index on the same columns isn't enough for ON CONFLICT.
+	_, err = s.db.ExecContext(s.ctx, "CREATE INDEX idx_articles_check_schema ON articles(source_id, external_key)")
+	s.Require().NoError(err)
+	defer func() {
+		_, err := s.db.ExecContext(s.ctx, "DROP INDEX idx_articles_check_schema")
+		s.Require().NoError(err)
+	}()
+	s.ErrorIs(store.CheckSchema(s.ctx), ErrNoUniqueConstraint)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_ExternalKey() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	keyed := &domain.Article{
+		SourceID:     "rss",
+		Title:        "First",
+		CanonicalURL: "https://example.com/feed/item/1",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	keyed.SetExternalKey("https://example.com/feed/item/1")
+	id, err := store.Upsert(s.ctx, keyed)
+	s.Require().NoError(err)
+
+	// The same key updates the article.
+	keyed.Title = "Updated"
+	keyed.LastModified = now.Add(time.Hour)
+	updatedID, err := store.Upsert(s.ctx, keyed)
+	s.Require().NoError(err)
+	s.Equal(id, updatedID)
+
+	got, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("https://example.com/feed/item/1", got.ExternalKey)
+	s.Equal(keyed.ExternalID, got.ExternalID)
+	s.Equal("Updated", got.Title)
+
+	// Articles with numeric IDs are keyed by them and read back without a key.
+	numeric := &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   67890,
+		Title:        "Numeric",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	numericID, err := store.Upsert(s.ctx, numeric)
+	s.Require().NoError(err)
+	got, err = store.GetByID(s.ctx, numericID)
+	s.Require().NoError(err)
+	s.Empty(got.ExternalKey)
+	var key string
+	s.Require().NoError(s.db.GetContext(s.ctx, &key, "SELECT external_key FROM articles WHERE id = $1", numericID))
+	s.Equal("67890", key)
+
+	// Rows inserted without a key, e.g. by hand, are keyed by external_id.
+	_, err = s.db.ExecContext(s.ctx, `
+		INSERT INTO articles (source_id, external_id, title, canonical_url, published_at, last_modified)
+		VALUES ('ecb', 67891, 'Manual', 'https://example.com/article', $1, $1)`, now)
+	s.Require().NoError(err)
+	s.Require().NoError(s.db.GetContext(s.ctx, &key, "SELECT external_key FROM articles WHERE external_id = 67891"))
+	s.Equal("67891", key)
+
+	// Keys are unique within a source.
+	_, err = s.db.ExecContext(s.ctx, `
+		INSERT INTO articles (source_id, external_id, external_key, title, canonical_url, published_at, last_modified)
+		VALUES ('rss', 1, 'https://example.com/feed/item/1', 'Duplicate', 'https://example.com/article', $1, $1)`, now)
+	var pqErr *pq.Error
+	s.Require().ErrorAs(err, &pqErr)
+	s.Equal(pq.ErrorCode("23505"), pqErr.Code) // unique_violation
+}
+
+func (s *PostgresIntegrationSuite) TestExternalKey_SameExternalID() {
+	store := NewArticleStore(s.db)
+	payloads := NewRawPayloadStore(s.db)
+	failures := NewFailedArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	// Two keys deriving the same ExternalID are stored as different articles.
+	var articles [2]*domain.Article
+	for i, key := range []string{"item-1", "item-2"} {
+		articles[i] = &domain.Article{
+			SourceID:     "rss",
+			ExternalID:   -1,
+			ExternalKey:  key,
+			Title:        key,
+			CanonicalURL: "https://example.com/" + key,
+			PublishedAt:  now,
+			LastModified: now,
+			Raw:          []byte(`{"key":"` + key + `"}`),
+		}
+		_, err := store.Upsert(s.ctx, articles[i])
+		s.Require().NoError(err)
+		s.Require().NoError(payloads.Save(s.ctx, articles[i]))
+		_, err = failures.RecordFailure(s.ctx, articles[i], errors.New("boom"), 3)
+		s.Require().NoError(err)
+	}
+
+	existing, err := store.GetExisting(s.ctx, "rss", []string{"item-1", "item-2"})
+	s.Require().NoError(err)
+	s.Len(existing, 2)
+	s.NotEqual(existing["item-1"].ID, existing["item-2"].ID)
+
+	saved, err := payloads.List(s.ctx, "rss", 0, 10)
+	s.Require().NoError(err)
+	s.Require().Len(saved, 2)
+	s.Equal("item-1", saved[0].ExternalKey)
+	s.Equal("item-2", saved[1].ExternalKey)
+
+	s.Require().NoError(failures.Clear(s.ctx, "rss", "item-1"))
+	failing, err := failures.Failing(s.ctx, "rss")
+	s.Require().NoError(err)
+	s.Equal(map[string]bool{"item-2": false}, failing)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_CountBySource() {
+	store := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	n, err := store.CountBySource(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Zero(n)
+
+	for i, sourceID := range []string{"ecb", "ecb", "ecb", "other"} {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     sourceID,
+			ExternalID:   int64(i + 1),
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  now,
+			LastModified: now,
+		})
+		s.Require().NoError(err)
+	}
+	// Upserting an existing article doesn't add to the count.
+	_, err = store.Upsert(s.ctx, &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   1,
+		Title:        "Updated",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now.Add(time.Hour),
+	})
+	s.Require().NoError(err)
+
+	n, err = store.CountBySource(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Equal(int64(3), n)
+
+	n, err = store.CountBySource(s.ctx, "other")
+	s.Require().NoError(err)
+	s.Equal(int64(1), n)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_CountByDateRange() {
+	store := NewArticleStore(s.db)
+	day := time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	for i, a := range []struct {
+		sourceID    string
+		publishedAt time.Time
+	}{
+		{"ecb", day.Add(-24 * time.Hour)},
+		{"ecb", day},
+		{"ecb", day.Add(12 * time.Hour)},
+		{"ecb", day.Add(24 * time.Hour)},
+		{"ecb", day.Add(48 * time.Hour)},
+		{"other", day.Add(12 * time.Hour)},
+	} {
+		_, err := store.Upsert(s.ctx, &domain.Article{
+			SourceID:     a.sourceID,
+			ExternalID:   int64(i + 1),
+			Title:        "Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  a.publishedAt,
+			LastModified: a.publishedAt,
+		})
+		s.Require().NoError(err)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		from, to time.Time
+		want     int64
+	}{
+		{"one day, from inclusive, to exclusive", day, day.Add(24 * time.Hour), 2},
+		{"two days", day, day.Add(48 * time.Hour), 3},
+		{"open start", time.Time{}, day, 1},
+		{"open end", day.Add(24 * time.Hour), time.Time{}, 2},
+		{"unbounded", time.Time{}, time.Time{}, 5},
+		{"empty range", day.Add(72 * time.Hour), day.Add(96 * time.Hour), 0},
+	} {
+		n, err := store.CountByDateRange(s.ctx, "ecb", tc.from, tc.to)
+		s.Require().NoError(err, tc.name)
+		s.Equal(tc.want, n, tc.name)
+	}
+
+	n, err := store.CountByDateRange(s.ctx, "other", day, day.Add(24*time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(1), n)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_AnalyzeAfterBulkDelete() {
+	store := NewArticleStore(s.db)
+	old := time.Now().Add(-365 * 24 * time.Hour).Truncate(time.Microsecond)
+
+	_, err := s.db.ExecContext(s.ctx, `
+		INSERT INTO articles (source_id, external_id, title, canonical_url, published_at, last_modified)
+		SELECT 'ecb', n, 'Article', 'https://example.com/article', $1, $1
+		FROM generate_series(1, 2000) AS n`, old)
+	s.Require().NoError(err)
+
+	deleted, err := store.DeleteOlderThan(s.ctx, "ecb", old.Add(time.Hour))
+	s.Require().NoError(err)
+	s.Equal(int64(2000), deleted)
+
+	s.NoError(store.Analyze(s.ctx, false))
+	s.NoError(store.Analyze(s.ctx, true))
+
+	// VACUUM fails inside a transaction, so Analyze refuses to run in one.
+	tm := NewTransactionManager(s.db)
+	err = tm.WithTransaction(s.ctx, func(txCtx context.Context) error {
+		return store.Analyze(txCtx, true)
+	})
+	s.ErrorContains(err, "must not run inside a transaction")
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Analyze_WithoutStatementTimeout() {
+	db := s.connectWithTimeout(time.Millisecond)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+	store := NewArticleStore(db)
+
+	s.NoError(store.Analyze(s.ctx, true))
+
+	// The connection goes back to the pool with its timeout.
+	var timeout string
+	s.Require().NoError(db.GetContext(s.ctx, &timeout, "SHOW statement_timeout"))
+	s.Equal("1ms", timeout)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_ProtectedColumns() {
+	store := NewArticleStore(s.db)
+	s.Require().NoError(store.Protect("title", "image_url"))
+	now := time.Now().Truncate(time.Microsecond)
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Original",
+		Summary:      utils.Ptr("Original summary"),
+		CanonicalURL: "https://example.com/article",
+		ImageURL:     utils.Ptr("https://example.com/original.jpg"),
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := store.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	s.Equal("Original", article.Title, "protected columns are set on insert")
+
+	// An editor curates the title and removes the image.
+	_, err = s.db.ExecContext(s.ctx, "UPDATE articles SET title = 'Curated', image_url = NULL WHERE id = $1", id)
+	s.Require().NoError(err)
+
+	update := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Upstream",
+		Summary:      utils.Ptr("Upstream summary"),
+		CanonicalURL: "https://example.com/article",
+		ImageURL:     utils.Ptr("https://example.com/upstream.jpg"),
+		PublishedAt:  now,
+		LastModified: now.Add(time.Minute),
+	}
+	_, err = store.Upsert(s.ctx, update)
+	s.Require().NoError(err)
+	s.Equal("Curated", update.Title, "the article gets the stored values")
+	s.Nil(update.ImageURL)
+
+	_, err = store.Replace(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Remapped",
+		Summary:      utils.Ptr("Remapped summary"),
+		CanonicalURL: "https://example.com/article",
+		ImageURL:     utils.Ptr("https://example.com/upstream.jpg"),
+		PublishedAt:  now,
+		LastModified: now.Add(time.Minute),
+	})
+	s.Require().NoError(err)
+
+	stored, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Curated", stored.Title)
+	s.Nil(stored.ImageURL)
+	s.Equal("Remapped summary", *stored.Summary, "unprotected columns are still updated")
+	s.True(stored.LastModified.Equal(now.Add(time.Minute)))
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_Protect_RejectsColumns() {
+	store := NewArticleStore(s.db)
+
+	s.ErrorContains(store.Protect("title", "last_modified"), `column "last_modified" can't be protected`)
+	s.ErrorContains(store.Protect("nonexistent"), `column "nonexistent" can't be protected`)
+	s.Empty(store.protected)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_UpdatedColumns() {
+	store := NewArticleStore(s.db)
+	s.Require().NoError(store.UpdateOnly("title", "body"))
+	now := time.Now().Truncate(time.Microsecond)
+	id, err := store.Upsert(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Original",
+		Summary:      utils.Ptr("Original summary"),
+		Body:         utils.Ptr("Original body"),
+		Author:       utils.Ptr("Original author"),
+		CanonicalURL: "https://example.com/original",
+		PublishedAt:  now,
+		LastModified: now,
+	})
+	s.Require().NoError(err)
+
+	update := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Upstream",
+		Summary:      utils.Ptr("Upstream summary"),
+		Body:         utils.Ptr("Upstream body"),
+		Author:       utils.Ptr("Upstream author"),
+		CanonicalURL: "https://example.com/upstream",
+		PublishedAt:  now,
+		LastModified: now.Add(time.Minute),
+	}
+	_, err = store.Upsert(s.ctx, update)
+	s.Require().NoError(err)
+	s.Equal("Original summary", *update.Summary, "the article gets the stored values of the other columns")
+	s.Equal("https://example.com/original", update.CanonicalURL)
+
+	stored, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Upstream", stored.Title)
+	s.Equal("Upstream body", *stored.Body)
+	s.Equal("Original summary", *stored.Summary)
+	s.Equal("Original author", *stored.Author)
+	s.Equal("https://example.com/original", stored.CanonicalURL)
+	s.True(stored.LastModified.Equal(now.Add(time.Minute)), "last_modified is always updated")
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_UpdatedColumns_WithProtected() {
+	store := NewArticleStore(s.db)
+	s.Require().NoError(store.Protect("title"))
+	s.Require().NoError(store.UpdateOnly("title", "summary"))
+	now := time.Now().Truncate(time.Microsecond)
+	id, err := store.Upsert(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Original",
+		Summary:      utils.Ptr("Original summary"),
+		CanonicalURL: "https://example.com/original",
+		PublishedAt:  now,
+		LastModified: now,
+	})
+	s.Require().NoError(err)
+
+	_, err = store.Replace(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Remapped",
+		Summary:      utils.Ptr("Remapped summary"),
+		CanonicalURL: "https://example.com/remapped",
+		PublishedAt:  now,
+		LastModified: now,
+	})
+	s.Require().NoError(err)
+
+	stored, err := store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Original", stored.Title, "protected wins over updated")
+	s.Equal("Remapped summary", *stored.Summary)
+	s.Equal("https://example.com/original", stored.CanonicalURL)
+
+	// Without columns, everything but the protected ones is updated again.
+	s.Require().NoError(store.UpdateOnly())
+	_, err = store.Replace(s.ctx, &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Remapped",
+		Summary:      utils.Ptr("Remapped summary"),
+		CanonicalURL: "https://example.com/remapped",
+		PublishedAt:  now,
+		LastModified: now,
+	})
+	s.Require().NoError(err)
+	stored, err = store.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Original", stored.Title)
+	s.Equal("https://example.com/remapped", stored.CanonicalURL)
+}
+
+func (s *PostgresIntegrationSuite) TestArticleStore_UpdateOnly_RejectsColumns() {
+	store := NewArticleStore(s.db)
+	s.Require().NoError(store.UpdateOnly("title", "last_modified"), "columns always updated may be listed")
+
+	s.ErrorContains(store.UpdateOnly("title", "published_at"), `column "published_at" isn't updated by an upsert`)
+	s.ErrorContains(store.UpdateOnly("nonexistent; DROP TABLE articles"), `isn't updated by an upsert`)
+	s.Equal([]string{"title", "last_modified"}, store.updatable)
+}
+
+func (s *PostgresIntegrationSuite) TestRawPayloadStore_SaveAndList() {
+	store := NewRawPayloadStore(s.db)
+
+	s.Require().NoError(store.Save(s.ctx, &domain.Article{SourceID: "ecb", ExternalID: 1, Raw: []byte(`{"id":1,"title":"old"}`)}))
+	s.Require().NoError(store.Save(s.ctx, &domain.Article{SourceID: "ecb", ExternalID: 2, Raw: []byte(`{"id":2}`)}))
+	s.Require().NoError(store.Save(s.ctx, &domain.Article{SourceID: "other", ExternalID: 3, Raw: []byte(`{"id":3}`)}))
+	s.Require().NoError(store.Save(s.ctx, &domain.Article{SourceID: "ecb", ExternalID: 1, Raw: []byte(`{"id":1,"title":"new"}`)}))
+
+	page, err := store.List(s.ctx, "ecb", 0, 1)
+	s.Require().NoError(err)
+	s.Require().Len(page, 1)
+	s.Equal(int64(1), page[0].ExternalID)
+	s.Equal("1", page[0].ExternalKey)
+	s.JSONEq(`{"id":1,"title":"new"}`, string(page[0].Payload))
+
+	rest, err := store.List(s.ctx, "ecb", page[0].ID, 10)
+	s.Require().NoError(err)
+	s.Require().Len(rest, 1)
+	s.Equal(int64(2), rest[0].ExternalID)
+}
+
+func (s *PostgresIntegrationSuite) TestFailedArticleStore_QuarantinesAfterMaxAttempts() {
+	store := NewFailedArticleStore(s.db)
+	article := &domain.Article{SourceID: "ecb", ExternalID: 1, Title: "Bad"}
+	cause := errors.New("check constraint violated")
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		quarantined, err := store.RecordFailure(s.ctx, article, cause, 3)
+		s.Require().NoError(err)
+		s.False(quarantined, "attempt %d", attempt)
+	}
+
+	failing, err := store.Failing(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Equal(map[string]bool{"1": false}, failing)
+
+	quarantined, err := store.RecordFailure(s.ctx, article, errors.New("still bad"), 3)
+	s.Require().NoError(err)
+	s.True(quarantined)
+
+	failing, err = store.Failing(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Equal(map[string]bool{"1": true}, failing)
+
+	listed, err := store.ListQuarantined(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Require().Len(listed, 1)
+	s.Equal(3, listed[0].Attempts)
+	s.Equal("still bad", listed[0].LastError)
+	s.NotNil(listed[0].QuarantinedAt)
+
+	var stored domain.Article
+	s.Require().NoError(json.Unmarshal(listed[0].Article, &stored))
+	s.Equal("Bad", stored.Title)
+}
+
+func (s *PostgresIntegrationSuite) TestFailedArticleStore_Clear() {
+	store := NewFailedArticleStore(s.db)
+	cause := errors.New("boom")
+
+	_, err := store.RecordFailure(s.ctx, &domain.Article{SourceID: "ecb", ExternalID: 1}, cause, 3)
+	s.Require().NoError(err)
+	s.Require().NoError(store.Clear(s.ctx, "ecb", "1"))
+
+	failing, err := store.Failing(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Empty(failing)
+}
+
+func (s *PostgresIntegrationSuite) TestFailedArticleStore_ClearQuarantine() {
+	store := NewFailedArticleStore(s.db)
+	cause := errors.New("boom")
+
+	for _, a := range []domain.Article{
+		{SourceID: "ecb", ExternalID: 1},
+		{SourceID: "ecb", ExternalID: 2},
+		{SourceID: "ecb", ExternalID: 3},
+		{SourceID: "other", ExternalID: 1},
+	} {
+		_, err := store.RecordFailure(s.ctx, &a, cause, 1)
+		s.Require().NoError(err)
+	}
+
+	released, err := store.ClearQuarantine(s.ctx, "ecb", []string{"2"})
+	s.Require().NoError(err)
+	s.Equal(int64(1), released)
+
+	failing, err := store.Failing(s.ctx, "ecb")
+	s.Require().NoError(err)
+	s.Equal(map[string]bool{"1": true, "3": true}, failing)
+
+	released, err = store.ClearQuarantine(s.ctx, "ecb", nil)
+	s.Require().NoError(err)
+	s.Equal(int64(2), released)
+
+	all, err := store.ListQuarantined(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Len(all, 1)
+	s.Equal("other", all[0].SourceID)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_UpsertBatch() {
+	store := NewTagStore(s.db)
+
+	tags := []domain.Tag{
+		{ID: 1, Label: "tag1"},
+		{ID: 2, Label: "tag2"},
+		{ID: 3, Label: "tag3"},
+	}
+
+	err := store.UpsertBatch(s.ctx, tags)
+	s.NoError(err)
+
+	var count int
+	err = s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM tags")
+	s.NoError(err)
+	s.Equal(3, count)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_UpsertBatch_UpdatesExisting() {
+	store := NewTagStore(s.db)
+
+	tags := []domain.Tag{
+		{ID: 1, Label: "old-label"},
+	}
+	err := store.UpsertBatch(s.ctx, tags)
+	s.NoError(err)
+
+	tags = []domain.Tag{
+		{ID: 1, Label: "new-label"},
+	}
+	err = store.UpsertBatch(s.ctx, tags)
+	s.NoError(err)
+
+	var label string
+	err = s.db.GetContext(s.ctx, &label, "SELECT label FROM tags WHERE id = $1", 1)
+	s.NoError(err)
+	s.Equal("new-label", label)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_Upstream_CrossSourceCollision() {
+	store := NewTagStore(s.db)
+	ecbCtx := domain.WithSourceID(s.ctx, "ecb")
+	otherCtx := domain.WithSourceID(s.ctx, "other")
+
+	// Keyed by upstream ID, another source's tag 5 overwrites ECB's.
+	s.Require().NoError(store.UpsertBatch(ecbCtx, []domain.Tag{{ID: 5, Label: "England"}}))
+	s.Require().NoError(store.UpsertBatch(otherCtx, []domain.Tag{{ID: 5, Label: "Football"}}))
+
+	var labels []string
+	s.Require().NoError(s.db.SelectContext(s.ctx, &labels, "SELECT label FROM tags"))
+	s.Equal([]string{"Football"}, labels)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_SourceLabel_CrossSourceCollision() {
+	store := NewTagStore(s.db)
+	s.Require().NoError(store.SetIdentity(TagIdentitySourceLabel))
+	ecbCtx := domain.WithSourceID(s.ctx, "ecb")
+	otherCtx := domain.WithSourceID(s.ctx, "other")
+
+	ecbTags := []domain.Tag{{ID: 5, Label: "England"}, {ID: 6, Label: "Ashes"}, {ID: 5, Label: "England"}}
+	s.Require().NoError(store.UpsertBatch(ecbCtx, ecbTags))
+	otherTags := []domain.Tag{{ID: 5, Label: "Football"}, {ID: 9, Label: "England"}}
+	s.Require().NoError(store.UpsertBatch(otherCtx, otherTags))
+
+	// Every (source, label) has its own tag, whatever the upstream IDs.
+	s.Negative(ecbTags[0].ID)
+	s.Equal(ecbTags[0].ID, ecbTags[2].ID)
+	s.NotEqual(ecbTags[0].ID, ecbTags[1].ID)
+	s.NotEqual(ecbTags[0].ID, otherTags[0].ID)
+	s.NotEqual(ecbTags[0].ID, otherTags[1].ID)
+
+	var count int
+	s.Require().NoError(s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM tags"))
+	s.Equal(4, count)
+
+	// Storing them again finds the same tags.
+	again := []domain.Tag{{ID: 5, Label: "England"}}
+	s.Require().NoError(store.UpsertBatch(ecbCtx, again))
+	s.Equal(ecbTags[0].ID, again[0].ID)
+
+	articleStore := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	articleID, err := articleStore.Upsert(s.ctx, &domain.Article{
+		SourceID:     "other",
+		ExternalID:   1,
+		Title:        "Test Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(store.LinkToArticle(s.ctx, articleID, []int64{otherTags[0].ID, otherTags[1].ID}))
+
+	linked, err := store.GetByArticleID(s.ctx, articleID)
+	s.Require().NoError(err)
+	s.ElementsMatch(otherTags, linked)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_SourceLabel_NeedsSourceID() {
+	store := NewTagStore(s.db)
+	s.Require().NoError(store.SetIdentity(TagIdentitySourceLabel))
+
+	err := store.UpsertBatch(s.ctx, []domain.Tag{{ID: 5, Label: "England"}})
+
+	s.ErrorContains(err, "need the source ID")
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_Label_SharedAcrossSources() {
+	store := NewTagStore(s.db)
+	s.Require().NoError(store.SetIdentity(TagIdentityLabel))
+
+	ecbTags := []domain.Tag{{ID: 5, Label: "England"}}
+	s.Require().NoError(store.UpsertBatch(domain.WithSourceID(s.ctx, "ecb"), ecbTags))
+	otherTags := []domain.Tag{{ID: 9, Label: "England"}, {ID: 5, Label: "Football"}}
+	s.Require().NoError(store.UpsertBatch(domain.WithSourceID(s.ctx, "other"), otherTags))
+
+	s.Equal(ecbTags[0].ID, otherTags[0].ID)
+	s.NotEqual(ecbTags[0].ID, otherTags[1].ID)
+
+	var count int
+	s.Require().NoError(s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM tags"))
+	s.Equal(2, count)
+}
+
+func (s *PostgresIntegrationSuite) TestTagStore_NormalizeLabels_DedupsVariants() {
+	store := NewTagStore(s.db)
+	s.Require().NoError(store.SetIdentity(TagIdentitySourceLabel))
+	store.NormalizeLabels(true)
+	ctx := domain.WithSourceID(s.ctx, "ecb")
+
+	tags := []domain.Tag{{ID: 1, Label: "Test Match"}, {ID: 2, Label: "test  match"}, {ID: 3, Label: "Ashes"}}
+	s.Require().NoError(store.UpsertBatch(ctx, tags))
+	later := []domain.Tag{{ID: 4, Label: " TEST MATCH "}}
+	s.Require().NoError(store.UpsertBatch(ctx, later))
+
+	s.Equal(tags[0].ID, tags[1].ID)
+	s.Equal(tags[0].ID, later[0].ID)
+	s.NotEqual(tags[0].ID, tags[2].ID)
+
+	// The tag keeps the label it was first stored with.
+	var labels []string
+	s.Require().NoError(s.db.SelectContext(s.ctx, &labels, "SELECT label FROM tags ORDER BY label"))
+	s.Equal([]string{"Ashes", "Test Match"}, labels)
+}
+
+func (s *PostgresIntegrationSuite) TestTagReconciler_NormalizedLabelsMatch() {
+	tagStore := NewTagStore(s.db)
+	s.Require().NoError(tagStore.SetIdentity(TagIdentitySourceLabel))
+	tagStore.NormalizeLabels(true)
+	articleStore := NewArticleStore(s.db)
+	articleStore.SetTagIdentity(TagIdentitySourceLabel)
+	articleStore.NormalizeTagLabels(true)
+	now := time.Now().Truncate(time.Microsecond)
+
+	tags := []domain.Tag{{ID: 1, Label: "Test Match"}, {ID: 2, Label: "TEST  MATCH"}}
+	articleID, err := articleStore.Upsert(s.ctx, &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   123,
+		Title:        "Test Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+		Tags:         tags,
+	})
+	s.Require().NoError(err)
+	ctx := domain.WithSourceID(s.ctx, "ecb")
+	s.Require().NoError(tagStore.UpsertBatch(ctx, tags))
+	s.Require().NoError(tagStore.LinkToArticle(ctx, articleID, []int64{tags[0].ID, tags[1].ID}))
 
-	tags = []domain.Tag{
-		{ID: 1, Label: "new-label"},
-	}
-	err = store.UpsertBatch(s.ctx, tags)
+	// Both recorded variants match the one linked tag.
+	mismatches, err := articleStore.ListTagMismatches(s.ctx, 0, 10)
 	s.NoError(err)
+	s.Empty(mismatches)
+}
 
-	var label string
-	err = s.db.GetContext(s.ctx, &label, "SELECT label FROM tags WHERE id = $1", 1)
-	s.NoError(err)
-	s.Equal("new-label", label)
+func (s *PostgresIntegrationSuite) TestTagStore_SetIdentity_RejectsUnknown() {
+	s.ErrorContains(NewTagStore(s.db).SetIdentity("name"), `unknown tag identity "name"`)
 }
 
 func (s *PostgresIntegrationSuite) TestTagStore_LinkToArticle() {
@@ -337,6 +1630,124 @@ func (s *PostgresIntegrationSuite) TestTagStore_LinkToArticle_ReplacesOld() {
 	s.Equal(int64(3), linkedTags[0].ID)
 }
 
+func (s *PostgresIntegrationSuite) TestArticleStore_ListTagMismatches() {
+	tagStore := NewTagStore(s.db)
+	articleStore := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	s.Require().NoError(tagStore.UpsertBatch(s.ctx, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}}))
+
+	newArticle := func(externalID int64, tags []domain.Tag) int64 {
+		id, err := articleStore.Upsert(s.ctx, &domain.Article{
+			SourceID:     "test-source",
+			ExternalID:   externalID,
+			Title:        "Test Article",
+			CanonicalURL: "https://example.com/article",
+			PublishedAt:  now,
+			LastModified: now,
+			Tags:         tags,
+		})
+		s.Require().NoError(err)
+		return id
+	}
+
+	linked := newArticle(1, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}})
+	s.Require().NoError(tagStore.LinkToArticle(s.ctx, linked, []int64{2, 1}))
+	unlinked := newArticle(2, []domain.Tag{{ID: 1, Label: "tag1"}})
+	partial := newArticle(3, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}})
+	s.Require().NoError(tagStore.LinkToArticle(s.ctx, partial, []int64{1}))
+	stale := newArticle(4, nil)
+	s.Require().NoError(tagStore.LinkToArticle(s.ctx, stale, []int64{2}))
+	newArticle(5, nil)
+
+	mismatches, err := articleStore.ListTagMismatches(s.ctx, 0, 10)
+	s.NoError(err)
+	s.Require().Len(mismatches, 3)
+	s.Equal(unlinked, mismatches[0].ID)
+	s.Equal(int64(2), mismatches[0].ExternalID)
+	s.Equal([]domain.Tag{{ID: 1, Label: "tag1"}}, mismatches[0].Tags)
+	s.Equal(partial, mismatches[1].ID)
+	s.Equal(stale, mismatches[2].ID)
+	s.Empty(mismatches[2].Tags)
+
+	page, err := articleStore.ListTagMismatches(s.ctx, unlinked, 1)
+	s.NoError(err)
+	s.Require().Len(page, 1)
+	s.Equal(partial, page[0].ID)
+}
+
+func (s *PostgresIntegrationSuite) TestTagReconciler_RelinksMismatches() {
+	tagStore := NewTagStore(s.db)
+	articleStore := NewArticleStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	// Saved with tags whose linking failed.
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   123,
+		Title:        "Test Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+		Tags:         []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}},
+	}
+	articleID, err := articleStore.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reconciler := service.NewTagReconciler(articleStore, tagStore, NewTransactionManager(s.db), logger)
+
+	fixed, err := reconciler.Reconcile(s.ctx)
+	s.NoError(err)
+	s.Equal(1, fixed)
+
+	linkedTags, err := tagStore.GetByArticleID(s.ctx, articleID)
+	s.NoError(err)
+	s.ElementsMatch(article.Tags, linkedTags)
+
+	mismatches, err := articleStore.ListTagMismatches(s.ctx, 0, 10)
+	s.NoError(err)
+	s.Empty(mismatches)
+}
+
+
+func (s *PostgresIntegrationSuite) TestTagReconciler_RelinksByLabel() {
+	tagStore := NewTagStore(s.db)
+	s.Require().NoError(tagStore.SetIdentity(TagIdentitySourceLabel))
+	articleStore := NewArticleStore(s.db)
+	articleStore.SetTagIdentity(TagIdentitySourceLabel)
+	now := time.Now().Truncate(time.Microsecond)
+
+	// Recorded with the upstream IDs, never linked.
+	articleID, err := articleStore.Upsert(s.ctx, &domain.Article{
+		SourceID:     "ecb",
+		ExternalID:   123,
+		Title:        "Test Article",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+		Tags:         []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}},
+	})
+	s.Require().NoError(err)
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reconciler := service.NewTagReconciler(articleStore, tagStore, NewTransactionManager(s.db), logger)
+
+	fixed, err := reconciler.Reconcile(s.ctx)
+	s.NoError(err)
+	s.Equal(1, fixed)
+
+	linked, err := tagStore.GetByArticleID(s.ctx, articleID)
+	s.NoError(err)
+	s.Require().Len(linked, 2)
+	for _, tag := range linked {
+		s.Negative(tag.ID)
+	}
+
+	// Linked tags have generated IDs but match the recorded ones by label.
+	mismatches, err := articleStore.ListTagMismatches(s.ctx, 0, 10)
+	s.NoError(err)
+	s.Empty(mismatches)
+}
 
 func (s *PostgresIntegrationSuite) TestSyncStateStore_GetNew() {
 	store := NewSyncStateStore(s.db)
@@ -394,6 +1805,326 @@ func (s *PostgresIntegrationSuite) TestSyncStateStore_UpdateExisting() {
 	s.Equal(int64(20), retrieved.TotalSynced)
 }
 
+func (s *PostgresIntegrationSuite) TestSyncStateStore_LastPublishedAt() {
+	store := NewSyncStateStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+	older, newer := now.Add(-2*time.Hour), now.Add(-time.Hour)
+
+	state, err := store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Nil(state.LastPublishedAt)
+
+	state.LastSyncedAt = now
+	state.LastPublishedAt = &older
+	s.Require().NoError(store.Update(s.ctx, state))
+	state, err = store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Require().NotNil(state.LastPublishedAt)
+	s.True(older.Equal(*state.LastPublishedAt))
+
+	// It advances...
+	state.LastPublishedAt = &newer
+	s.Require().NoError(store.Update(s.ctx, state))
+	state, err = store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.True(newer.Equal(*state.LastPublishedAt))
+
+	// ...but never goes backwards, nor is cleared.
+	state.LastPublishedAt = &older
+	s.Require().NoError(store.Update(s.ctx, state))
+	state.LastPublishedAt = nil
+	s.Require().NoError(store.Update(s.ctx, state))
+	state, err = store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Require().NotNil(state.LastPublishedAt)
+	s.True(newer.Equal(*state.LastPublishedAt))
+
+	// Touching the sync time leaves it alone.
+	s.Require().NoError(store.TouchLastSynced(s.ctx, "test-source", now.Add(time.Minute)))
+	states, err := store.List(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(states, 1)
+	s.True(newer.Equal(*states[0].LastPublishedAt))
+}
+
+func (s *PostgresIntegrationSuite) TestSyncStateStore_TouchLastSynced() {
+	store := NewSyncStateStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	s.Require().NoError(store.Update(s.ctx, &domain.SyncState{
+		SourceID:      "test-source",
+		LastSyncedAt:  now.Add(-time.Hour),
+		LastArticleID: 100,
+		TotalSynced:   10,
+	}))
+	before, err := store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+
+	s.Require().NoError(store.TouchLastSynced(s.ctx, "test-source", now))
+
+	after, err := store.Get(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.True(after.LastSyncedAt.Equal(now))
+	after.LastSyncedAt = before.LastSyncedAt
+	s.Equal(before, after, "only last_synced_at changes")
+
+	// A source without state gets one.
+	s.Require().NoError(store.TouchLastSynced(s.ctx, "new-source", now))
+	created, err := store.Get(s.ctx, "new-source")
+	s.Require().NoError(err)
+	s.True(created.LastSyncedAt.Equal(now))
+	s.Zero(created.TotalSynced)
+}
+
+func (s *PostgresIntegrationSuite) TestSyncStateStore_List() {
+	store := NewSyncStateStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	states, err := store.List(s.ctx)
+	s.NoError(err)
+	s.Empty(states)
+
+	err = store.Update(s.ctx, &domain.SyncState{
+		SourceID:      "test-source",
+		LastSyncedAt:  now,
+		LastArticleID: 42,
+		TotalSynced:   7,
+	})
+	s.NoError(err)
+
+	states, err = store.List(s.ctx)
+	s.NoError(err)
+	s.Require().Len(states, 1)
+	s.Equal("test-source", states[0].SourceID)
+	s.Equal(int64(42), states[0].LastArticleID)
+	s.Equal(int64(7), states[0].TotalSynced)
+}
+
+func (s *PostgresIntegrationSuite) TestSyncStateStore_List_MultipleSources() {
+	store := NewSyncStateStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	for i, sourceID := range []string{"gamma", "alpha", "beta"} {
+		err := store.Update(s.ctx, &domain.SyncState{
+			SourceID:      sourceID,
+			LastSyncedAt:  now.Add(time.Duration(i) * time.Minute),
+			LastArticleID: int64(i + 1),
+			TotalSynced:   int64((i + 1) * 10),
+		})
+		s.NoError(err)
+	}
+
+	states, err := store.List(s.ctx)
+	s.NoError(err)
+	s.Require().Len(states, 3)
+
+	s.Equal("alpha", states[0].SourceID)
+	s.Equal(int64(2), states[0].LastArticleID)
+	s.Equal(int64(20), states[0].TotalSynced)
+	s.WithinDuration(now.Add(time.Minute), states[0].LastSyncedAt, time.Second)
+
+	s.Equal("beta", states[1].SourceID)
+	s.Equal(int64(3), states[1].LastArticleID)
+	s.Equal(int64(30), states[1].TotalSynced)
+	s.WithinDuration(now.Add(2*time.Minute), states[1].LastSyncedAt, time.Second)
+
+	s.Equal("gamma", states[2].SourceID)
+	s.Equal(int64(1), states[2].LastArticleID)
+	s.Equal(int64(10), states[2].TotalSynced)
+	s.WithinDuration(now, states[2].LastSyncedAt, time.Second)
+}
+
+func (s *PostgresIntegrationSuite) TestSyncStateStore_Reset() {
+	store := NewSyncStateStore(s.db)
+	now := time.Now().Truncate(time.Microsecond)
+
+	for _, sourceID := range []string{"test-source", "other-source"} {
+		err := store.Update(s.ctx, &domain.SyncState{
+			SourceID:      sourceID,
+			LastSyncedAt:  now,
+			LastArticleID: 100,
+			TotalSynced:   10,
+		})
+		s.NoError(err)
+	}
+
+	err := store.Reset(s.ctx, "test-source")
+	s.NoError(err)
+
+	state, err := store.Get(s.ctx, "test-source")
+	s.NoError(err)
+	s.Equal("test-source", state.SourceID)
+	s.True(state.LastSyncedAt.IsZero())
+	s.Equal(int64(0), state.LastArticleID)
+	s.Equal(int64(0), state.TotalSynced)
+
+	other, err := store.Get(s.ctx, "other-source")
+	s.NoError(err)
+	s.Equal(int64(10), other.TotalSynced)
+}
+
+// connectWithTimeout opens another pool on the test database, with the
+// statement timeout set as the config sets it.
+func (s *PostgresIntegrationSuite) connectWithTimeout(timeout time.Duration) *sqlx.DB {
+	host, err := s.container.Host(s.ctx)
+	s.Require().NoError(err)
+	port, err := s.container.MappedPort(s.ctx, "5432/tcp")
+	s.Require().NoError(err)
+
+	cfg := config.DatabaseConfig{
+		Host:             host,
+		Port:             port.Int(),
+		User:             "test",
+		Password:         "test",
+		DBName:           "test_db",
+		SSLMode:          "disable",
+		StatementTimeout: timeout,
+	}
+	db, err := sqlx.Connect("postgres", cfg.DSN())
+	s.Require().NoError(err)
+	return db
+}
+
+func (s *PostgresIntegrationSuite) TestStatementTimeout() {
+	db := s.connectWithTimeout(100 * time.Millisecond)
+	defer db.Close()
+
+	start := time.Now()
+	_, err := db.ExecContext(s.ctx, "SELECT pg_sleep(5)")
+
+	var pqErr *pq.Error
+	s.Require().ErrorAs(err, &pqErr)
+	s.Equal(pq.ErrorCode("57014"), pqErr.Code) // query_canceled
+	s.Less(time.Since(start), 5*time.Second)
+}
+
+func (s *PostgresIntegrationSuite) TestPausedSourceStore() {
+	store := NewPausedSourceStore(s.db)
+
+	paused, err := store.IsPaused(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.False(paused)
+
+	// Pausing twice is a no-op.
+	s.Require().NoError(store.SetPaused(s.ctx, "test-source", true))
+	s.Require().NoError(store.SetPaused(s.ctx, "test-source", true))
+	paused, err = store.IsPaused(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.True(paused)
+
+	paused, err = store.IsPaused(s.ctx, "other-source")
+	s.Require().NoError(err)
+	s.False(paused)
+
+	s.Require().NoError(store.SetPaused(s.ctx, "test-source", false))
+	paused, err = store.IsPaused(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.False(paused)
+}
+
+func (s *PostgresIntegrationSuite) TestSequenceStore() {
+	store := NewSequenceStore(s.db)
+
+	for want := int64(1); want <= 3; want++ {
+		seq, err := store.Next(s.ctx, "test-source")
+		s.Require().NoError(err)
+		s.Equal(want, seq)
+	}
+
+	// Each source is numbered on its own.
+	seq, err := store.Next(s.ctx, "other-source")
+	s.Require().NoError(err)
+	s.Equal(int64(1), seq)
+
+	// A new store, as after a restart, continues where the last run left off.
+	seq, err = NewSequenceStore(s.db).Next(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Equal(int64(4), seq)
+}
+
+func (s *PostgresIntegrationSuite) TestSequenceStore_RolledBack() {
+	store := NewSequenceStore(s.db)
+	tm := NewTransactionManager(s.db)
+
+	seq, err := store.Next(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Equal(int64(1), seq)
+
+	// As a failed publish rolls back the number it took.
+	err = tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		seq, err := store.Next(ctx, "test-source")
+		s.Require().NoError(err)
+		s.Equal(int64(2), seq)
+		return errors.New("broker down")
+	})
+	s.Require().Error(err)
+
+	seq, err = store.Next(s.ctx, "test-source")
+	s.Require().NoError(err)
+	s.Equal(int64(2), seq, "no gap")
+}
+
+func (s *PostgresIntegrationSuite) TestSequenceStore_Concurrent() {
+	store := NewSequenceStore(s.db)
+
+	const callers = 20
+	seqs := make(chan int64, callers)
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seq, err := store.Next(s.ctx, "test-source")
+			s.NoError(err)
+			seqs <- seq
+		}()
+	}
+	wg.Wait()
+	close(seqs)
+
+	seen := make(map[int64]bool)
+	for seq := range seqs {
+		s.False(seen[seq], "sequence number %d handed out twice", seq)
+		seen[seq] = true
+	}
+	s.Len(seen, callers)
+}
+
+func (s *PostgresIntegrationSuite) TestSourceHealthStore() {
+	store := NewSourceHealthStore(s.db)
+	lastSuccess := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	s.Require().NoError(store.Save(s.ctx, &domain.SourceHealth{SourceID: "test-source", Runs: 1, AverageDuration: time.Second}))
+	s.Require().NoError(store.Save(s.ctx, &domain.SourceHealth{
+		SourceID:        "test-source",
+		LastSuccessAt:   &lastSuccess,
+		Runs:            2,
+		AverageDuration: 3 * time.Second,
+	}))
+	s.Require().NoError(store.Save(s.ctx, &domain.SourceHealth{
+		SourceID:            "another-source",
+		ConsecutiveFailures: 1,
+		LastError:           "timeout",
+		Runs:                1,
+		LastCheckAt:         &lastSuccess,
+		LastCheckError:      "connection refused",
+	}))
+
+	health, err := store.List(s.ctx)
+	s.Require().NoError(err)
+	s.Require().Len(health, 2)
+	s.Equal("another-source", health[0].SourceID)
+	s.Equal("timeout", health[0].LastError)
+	s.Nil(health[0].LastSuccessAt)
+	s.Equal("connection refused", health[0].LastCheckError)
+	s.Require().NotNil(health[0].LastCheckAt)
+	s.Equal("test-source", health[1].SourceID)
+	s.Equal(2, health[1].Runs)
+	s.Equal(3*time.Second, health[1].AverageDuration)
+	s.Require().NotNil(health[1].LastSuccessAt)
+	s.True(lastSuccess.Equal(*health[1].LastSuccessAt))
+}
+
 func (s *PostgresIntegrationSuite) TestTransaction_Commit() {
 	tm := NewTransactionManager(s.db)
 	articleStore := NewArticleStore(s.db)
@@ -452,4 +2183,157 @@ func (s *PostgresIntegrationSuite) TestTransaction_Rollback() {
 	err = s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM articles WHERE external_id = $1", 888)
 	s.NoError(err)
 	s.Equal(1, count)
+}
+
+func (s *PostgresIntegrationSuite) insertArticle(ctx context.Context, externalID int64) error {
+	now := time.Now().Truncate(time.Microsecond)
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, `
+		INSERT INTO articles (source_id, external_id, title, canonical_url, published_at, last_modified)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, "test-source", externalID, "Nested", "https://example.com", now, now)
+	return err
+}
+
+func (s *PostgresIntegrationSuite) countArticles(externalID int64) int {
+	var count int
+	err := s.db.GetContext(s.ctx, &count, "SELECT COUNT(*) FROM articles WHERE external_id = $1", externalID)
+	s.Require().NoError(err)
+	return count
+}
+
+func (s *PostgresIntegrationSuite) TestTransaction_Nested_CommitCommit() {
+	tm := NewTransactionManager(s.db)
+
+	err := tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		if err := s.insertArticle(ctx, 1001); err != nil {
+			return err
+		}
+		return tm.WithTransaction(ctx, func(ctx context.Context) error {
+			return s.insertArticle(ctx, 1002)
+		})
+	})
+	s.NoError(err)
+
+	s.Equal(1, s.countArticles(1001))
+	s.Equal(1, s.countArticles(1002))
+}
+
+func (s *PostgresIntegrationSuite) TestTransaction_Nested_InnerRollbackOuterCommit() {
+	tm := NewTransactionManager(s.db)
+
+	err := tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		if err := s.insertArticle(ctx, 1001); err != nil {
+			return err
+		}
+		innerErr := tm.WithTransaction(ctx, func(ctx context.Context) error {
+			if err := s.insertArticle(ctx, 1002); err != nil {
+				return err
+			}
+			return context.Canceled
+		})
+		s.ErrorIs(innerErr, context.Canceled)
+		return s.insertArticle(ctx, 1003)
+	})
+	s.NoError(err)
+
+	s.Equal(1, s.countArticles(1001))
+	s.Equal(0, s.countArticles(1002))
+	s.Equal(1, s.countArticles(1003))
+}
+
+func (s *PostgresIntegrationSuite) TestTransaction_Nested_OuterRollback() {
+	tm := NewTransactionManager(s.db)
+
+	err := tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		if err := s.insertArticle(ctx, 1001); err != nil {
+			return err
+		}
+		if err := tm.WithTransaction(ctx, func(ctx context.Context) error {
+			return s.insertArticle(ctx, 1002)
+		}); err != nil {
+			return err
+		}
+		return context.Canceled
+	})
+	s.ErrorIs(err, context.Canceled)
+
+	s.Equal(0, s.countArticles(1001))
+	s.Equal(0, s.countArticles(1002))
+}
+
+// linkedArticle stores an article linked to tags 1 and 2 and returns an
+// update of it, as a sync would save it.
+func (s *PostgresIntegrationSuite) linkedArticle(articles *ArticleStore, tags *TagStore) (int64, *domain.Article) {
+	now := time.Now().Truncate(time.Microsecond)
+	article := &domain.Article{
+		SourceID:     "test-source",
+		ExternalID:   1,
+		Title:        "Original",
+		CanonicalURL: "https://example.com/article",
+		PublishedAt:  now,
+		LastModified: now,
+	}
+	id, err := articles.Upsert(s.ctx, article)
+	s.Require().NoError(err)
+	s.Require().NoError(tags.UpsertBatch(s.ctx, []domain.Tag{{ID: 1, Label: "tag1"}, {ID: 2, Label: "tag2"}}))
+	s.Require().NoError(tags.LinkToArticle(s.ctx, id, []int64{1, 2}))
+
+	update := *article
+	update.Title = "Updated"
+	update.LastModified = now.Add(time.Minute)
+	return id, &update
+}
+
+func (s *PostgresIntegrationSuite) linkedTagIDs(articleID int64) []int64 {
+	var ids []int64
+	s.Require().NoError(s.db.SelectContext(s.ctx, &ids, "SELECT tag_id FROM article_tags WHERE article_id = $1 ORDER BY tag_id", articleID))
+	return ids
+}
+
+func (s *PostgresIntegrationSuite) TestTransaction_LinkFailureInSavepoint_KeepsLinksAndArticle() {
+	tm := NewTransactionManager(s.db)
+	articles := NewArticleStore(s.db)
+	tags := NewTagStore(s.db)
+	id, update := s.linkedArticle(articles, tags)
+
+	// As a sync tolerating tag errors saves an article: the tags in a
+	// savepoint of the article's transaction. Tag 999 doesn't exist, so the
+	// relink fails after deleting the old links.
+	err := tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		if _, err := articles.Upsert(ctx, update); err != nil {
+			return err
+		}
+		linkErr := tm.WithTransaction(ctx, func(ctx context.Context) error {
+			return tags.LinkToArticle(ctx, id, []int64{1, 999})
+		})
+		s.Error(linkErr)
+		return nil
+	})
+	s.Require().NoError(err)
+
+	s.Equal([]int64{1, 2}, s.linkedTagIDs(id), "the failed relink is rolled back")
+	stored, err := articles.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Updated", stored.Title, "the article is kept")
+}
+
+func (s *PostgresIntegrationSuite) TestTransaction_LinkFailure_RollsBackArticle() {
+	tm := NewTransactionManager(s.db)
+	articles := NewArticleStore(s.db)
+	tags := NewTagStore(s.db)
+	id, update := s.linkedArticle(articles, tags)
+
+	// As a sync not tolerating tag errors saves an article.
+	err := tm.WithTransaction(s.ctx, func(ctx context.Context) error {
+		if _, err := articles.Upsert(ctx, update); err != nil {
+			return err
+		}
+		return tags.LinkToArticle(ctx, id, []int64{1, 999})
+	})
+	s.Require().Error(err)
+
+	s.Equal([]int64{1, 2}, s.linkedTagIDs(id))
+	stored, err := articles.GetByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal("Original", stored.Title, "the whole article is rolled back")
 }
\ No newline at end of file
@@ -0,0 +1,168 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"news_fetcher/internal/domain"
+)
+
+// MigratorIntegrationSuite runs the migrations against an empty database,
+// unlike PostgresIntegrationSuite, whose database is created by them.
+type MigratorIntegrationSuite struct {
+	suite.Suite
+	ctx        context.Context
+	container  *postgres.PostgresContainer
+	db         *sqlx.DB
+	migrations []Migration
+}
+
+func (s *MigratorIntegrationSuite) SetupSuite() {
+	s.ctx = context.Background()
+
+	migrations, err := LoadMigrations(os.DirFS("../../../migrations"))
+	s.Require().NoError(err)
+	s.Require().Greater(len(migrations), 3)
+	s.migrations = migrations
+
+	container, err := postgres.Run(s.ctx,
+		"postgres:16-alpine",
+		postgres.WithDatabase("test_db"),
+		postgres.WithUsername("test"),
+		postgres.WithPassword("test"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second),
+		),
+	)
+	s.Require().NoError(err)
+	s.container = container
+
+	connStr, err := container.ConnectionString(s.ctx, "sslmode=disable")
+	s.Require().NoError(err)
+
+	db, err := sqlx.Connect("postgres", connStr)
+	s.Require().NoError(err)
+	s.db = db
+}
+
+func (s *MigratorIntegrationSuite) TearDownSuite() {
+	if s.db != nil {
+		s.db.Close()
+	}
+	if s.container != nil {
+		_ = s.container.Terminate(s.ctx)
+	}
+}
+
+// SetupTest starts every test from an empty database.
+func (s *MigratorIntegrationSuite) SetupTest() {
+	_, err := s.db.ExecContext(s.ctx, "DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+	s.Require().NoError(err)
+}
+
+func TestMigratorIntegrationSuite(t *testing.T) {
+	suite.Run(t, new(MigratorIntegrationSuite))
+}
+
+func (s *MigratorIntegrationSuite) TestUpAndStatus() {
+	full := NewMigrator(s.db, s.migrations)
+
+	statuses, err := full.Status(s.ctx)
+	s.Require().NoError(err)
+	s.Len(statuses, len(s.migrations))
+	for _, st := range statuses {
+		s.False(st.Applied, "migration %d", st.Version)
+	}
+
+	// Apply a subset, as an older build of the syncer would have.
+	applied, err := NewMigrator(s.db, s.migrations[:3]).Up(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(3, applied)
+
+	version, dirty, err := full.Version(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(s.migrations[2].Version, version)
+	s.False(dirty)
+
+	statuses, err = full.Status(s.ctx)
+	s.Require().NoError(err)
+	for i, st := range statuses {
+		s.Equal(i < 3, st.Applied, "migration %d", st.Version)
+	}
+
+	applied, err = full.Up(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(len(s.migrations)-3, applied)
+
+	applied, err = full.Up(s.ctx)
+	s.Require().NoError(err)
+	s.Zero(applied)
+
+	// The schema is usable: the latest columns exist.
+	_, err = NewArticleStore(s.db).List(s.ctx, domain.ArticleFilter{})
+	s.NoError(err)
+}
+
+func (s *MigratorIntegrationSuite) TestUp_FailedMigrationRollsBack() {
+	_, err := NewMigrator(s.db, s.migrations).Up(s.ctx)
+	s.Require().NoError(err)
+	before, _, err := NewMigrator(s.db, s.migrations).Version(s.ctx)
+	s.Require().NoError(err)
+
+	broken := append(append([]Migration{}, s.migrations...), Migration{
+		Version: before + 1,
+		Name:    "broken",
+		Up:      "CREATE TABLE migration_probe (id INT); SELECT * FROM missing_table;",
+	})
+	_, err = NewMigrator(s.db, broken).Up(s.ctx)
+	s.ErrorContains(err, "broken")
+
+	version, dirty, err := NewMigrator(s.db, broken).Version(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(before, version)
+	s.False(dirty)
+
+	var exists bool
+	s.Require().NoError(s.db.GetContext(s.ctx, &exists, "SELECT to_regclass('migration_probe') IS NOT NULL"))
+	s.False(exists)
+}
+
+func (s *MigratorIntegrationSuite) TestUp_WithoutStatementTimeout() {
+	connStr, err := s.container.ConnectionString(s.ctx, "sslmode=disable", "statement_timeout=100")
+	s.Require().NoError(err)
+	db, err := sqlx.Connect("postgres", connStr)
+	s.Require().NoError(err)
+	defer db.Close()
+
+	slow := Migration{Version: 1, Name: "slow", Up: "SELECT pg_sleep(0.5); CREATE TABLE migration_probe (id INT);"}
+	applied, err := NewMigrator(db, []Migration{slow}).Up(s.ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, applied)
+}
+
+func (s *MigratorIntegrationSuite) TestUp_RefusesDirtySchema() {
+	_, err := NewMigrator(s.db, s.migrations).Up(s.ctx)
+	s.Require().NoError(err)
+	_, err = s.db.ExecContext(s.ctx, "UPDATE schema_migrations SET dirty = TRUE")
+	s.Require().NoError(err)
+
+	_, err = NewMigrator(s.db, s.migrations).Up(s.ctx)
+	s.ErrorContains(err, "schema is dirty")
+
+	statuses, err := NewMigrator(s.db, s.migrations).Status(s.ctx)
+	s.Require().NoError(err)
+	s.True(statuses[len(statuses)-1].Dirty)
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadMigrationsTestSuite struct {
+	suite.Suite
+}
+
+func TestLoadMigrationsTestSuite(t *testing.T) {
+	suite.Run(t, new(LoadMigrationsTestSuite))
+}
+
+func (s *LoadMigrationsTestSuite) TestOrdersByVersion() {
+	fsys := fstest.MapFS{
+		"010_add_b.up.sql":   {Data: []byte("B")},
+		"010_add_b.down.sql": {Data: []byte("-B")},
+		"002_add_a.up.sql":   {Data: []byte("A")},
+		"README.md":          {Data: []byte("not a migration")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+
+	s.Require().NoError(err)
+	s.Equal([]Migration{
+		{Version: 2, Name: "add_a", Up: "A"},
+		{Version: 10, Name: "add_b", Up: "B", Down: "-B"},
+	}, migrations)
+}
+
+func (s *LoadMigrationsTestSuite) TestMissingUpFile() {
+	_, err := LoadMigrations(fstest.MapFS{"003_add_c.down.sql": {Data: []byte("-C")}})
+
+	s.ErrorContains(err, "migration 3_add_c: missing up file")
+}
+
+func (s *LoadMigrationsTestSuite) TestConflictingNames() {
+	_, err := LoadMigrations(fstest.MapFS{
+		"003_add_c.up.sql": {Data: []byte("C")},
+		"003_add_d.up.sql": {Data: []byte("D")},
+	})
+
+	s.ErrorContains(err, "conflicting names")
+}
+
+func (s *LoadMigrationsTestSuite) TestRepoMigrations() {
+	migrations, err := LoadMigrations(os.DirFS("../../../migrations"))
+
+	s.Require().NoError(err)
+	s.Require().NotEmpty(migrations)
+	for i, m := range migrations {
+		s.Equal(int64(i+1), m.Version, "migrations are numbered without gaps")
+		s.NotEmpty(m.Down, "migration %d has a down file", m.Version)
+	}
+}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PausedSourceStore persists which sources are paused.
+type PausedSourceStore struct {
+	db *sqlx.DB
+}
+
+func NewPausedSourceStore(db *sqlx.DB) *PausedSourceStore {
+	return &PausedSourceStore{db: db}
+}
+
+// SetPaused records that a source is paused, or removes the record.
+func (s *PausedSourceStore) SetPaused(ctx context.Context, sourceID string, paused bool) error {
+	query := "DELETE FROM paused_sources WHERE source_id = $1"
+	if paused {
+		query = `
+			INSERT INTO paused_sources (source_id)
+			VALUES ($1)
+			ON CONFLICT (source_id) DO NOTHING`
+	}
+
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, query, sourceID)
+	return err
+}
+
+// IsPaused reports whether a source is recorded as paused.
+func (s *PausedSourceStore) IsPaused(ctx context.Context, sourceID string) (bool, error) {
+	var paused bool
+	query := "SELECT EXISTS (SELECT 1 FROM paused_sources WHERE source_id = $1)"
+	if err := sqlx.GetContext(ctx, GetExecutor(ctx, s.db), &paused, query, sourceID); err != nil {
+		return false, err
+	}
+	return paused, nil
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"news_fetcher/internal/domain"
+)
+
+type RawPayloadStore struct {
+	db *sqlx.DB
+}
+
+func NewRawPayloadStore(db *sqlx.DB) *RawPayloadStore {
+	return &RawPayloadStore{db: db}
+}
+
+// Save stores the latest payload of an article, its Raw, replacing any
+// previous one.
+func (s *RawPayloadStore) Save(ctx context.Context, article *domain.Article) error {
+	query := `
+		INSERT INTO raw_payloads (source_id, external_id, external_key, payload, fetched_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (source_id, external_key) DO UPDATE SET
+			external_id = EXCLUDED.external_id,
+			payload = EXCLUDED.payload,
+			fetched_at = EXCLUDED.fetched_at`
+
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, query, article.SourceID, article.ExternalID, article.Key(), article.Raw)
+	return err
+}
+
+// List returns up to limit payloads of a source with an id greater than afterID, ordered by id.
+func (s *RawPayloadStore) List(ctx context.Context, sourceID string, afterID int64, limit int) ([]domain.RawPayload, error) {
+	query := `
+		SELECT id, source_id, external_id, external_key, payload, fetched_at
+		FROM raw_payloads
+		WHERE source_id = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3`
+
+	var payloads []domain.RawPayload
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &payloads, query, sourceID, afterID, limit); err != nil {
+		return nil, err
+	}
+	return payloads, nil
+}
@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SequenceStore persists the last publish sequence number of each source, so
+// numbering continues across restarts.
+type SequenceStore struct {
+	db *sqlx.DB
+}
+
+func NewSequenceStore(db *sqlx.DB) *SequenceStore {
+	return &SequenceStore{db: db}
+}
+
+// Next increments the sequence number of a source and returns it; the first
+// is 1. Concurrent callers get distinct numbers.
+func (s *SequenceStore) Next(ctx context.Context, sourceID string) (int64, error) {
+	query := `
+		INSERT INTO publish_sequences (source_id, last_sequence)
+		VALUES ($1, 1)
+		ON CONFLICT (source_id) DO UPDATE SET last_sequence = publish_sequences.last_sequence + 1
+		RETURNING last_sequence`
+
+	var seq int64
+	if err := sqlx.GetContext(ctx, GetExecutor(ctx, s.db), &seq, query, sourceID); err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
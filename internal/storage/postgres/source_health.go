@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+
+	"news_fetcher/internal/domain"
+)
+
+type SourceHealthStore struct {
+	db *sqlx.DB
+}
+
+func NewSourceHealthStore(db *sqlx.DB) *SourceHealthStore {
+	return &SourceHealthStore{db: db}
+}
+
+// Save stores the health of a source, replacing the previous one.
+func (s *SourceHealthStore) Save(ctx context.Context, health *domain.SourceHealth) error {
+	query := `
+		INSERT INTO source_health (
+			source_id, last_success_at, last_failure_at, consecutive_failures,
+			last_error, runs, average_duration_ns, last_check_at, last_check_error
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (source_id) DO UPDATE SET
+			last_success_at = EXCLUDED.last_success_at,
+			last_failure_at = EXCLUDED.last_failure_at,
+			consecutive_failures = EXCLUDED.consecutive_failures,
+			last_error = EXCLUDED.last_error,
+			runs = EXCLUDED.runs,
+			average_duration_ns = EXCLUDED.average_duration_ns,
+			last_check_at = EXCLUDED.last_check_at,
+			last_check_error = EXCLUDED.last_check_error`
+
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, query,
+		health.SourceID,
+		health.LastSuccessAt,
+		health.LastFailureAt,
+		health.ConsecutiveFailures,
+		health.LastError,
+		health.Runs,
+		int64(health.AverageDuration),
+		health.LastCheckAt,
+		health.LastCheckError,
+	)
+	return err
+}
+
+// List returns the health of every source, ordered by source.
+func (s *SourceHealthStore) List(ctx context.Context) ([]domain.SourceHealth, error) {
+	query := `
+		SELECT source_id, last_success_at, last_failure_at, consecutive_failures,
+			last_error, runs, average_duration_ns, last_check_at, last_check_error
+		FROM source_health
+		ORDER BY source_id`
+
+	var health []domain.SourceHealth
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &health, query); err != nil {
+		return nil, err
+	}
+	return health, nil
+}
@@ -21,11 +21,11 @@ func NewSyncStateStore(db *sqlx.DB) *SyncStateStore {
 func (s *SyncStateStore) Get(ctx context.Context, sourceID string) (*domain.SyncState, error) {
 	var state domain.SyncState
 	query := `
-		SELECT id, source_id, last_synced_at, last_article_id, total_synced
+		SELECT id, source_id, last_synced_at, last_article_id, total_synced, last_published_at
 		FROM sync_state
 		WHERE source_id = $1`
 
-	err := s.db.GetContext(ctx, &state, query, sourceID)
+	err := sqlx.GetContext(ctx, GetExecutor(ctx, s.db), &state, query, sourceID)
 	if err == sql.ErrNoRows {
 		// Return empty state for new sources
 		return &domain.SyncState{
@@ -40,20 +40,56 @@ func (s *SyncStateStore) Get(ctx context.Context, sourceID string) (*domain.Sync
 	return &state, nil
 }
 
+// Update stores the sync state of a source. LastPublishedAt never moves
+// backwards: an older one than stored leaves it unchanged.
 func (s *SyncStateStore) Update(ctx context.Context, state *domain.SyncState) error {
 	query := `
-		INSERT INTO sync_state (source_id, last_synced_at, last_article_id, total_synced)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO sync_state (source_id, last_synced_at, last_article_id, total_synced, last_published_at)
+		VALUES ($1, $2, $3, $4, $5)
 		ON CONFLICT (source_id) DO UPDATE SET
 			last_synced_at = EXCLUDED.last_synced_at,
 			last_article_id = EXCLUDED.last_article_id,
-			total_synced = EXCLUDED.total_synced`
+			total_synced = EXCLUDED.total_synced,
+			last_published_at = GREATEST(sync_state.last_published_at, EXCLUDED.last_published_at)`
 
-	_, err := s.db.ExecContext(ctx, query,
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, query,
 		state.SourceID,
 		state.LastSyncedAt,
 		state.LastArticleID,
 		state.TotalSynced,
+		state.LastPublishedAt,
 	)
 	return err
+}
+
+// TouchLastSynced sets only the last sync time of a source, e.g. after a sync
+// that changed nothing, creating its state if it has none.
+func (s *SyncStateStore) TouchLastSynced(ctx context.Context, sourceID string, t time.Time) error {
+	query := `
+		INSERT INTO sync_state (source_id, last_synced_at)
+		VALUES ($1, $2)
+		ON CONFLICT (source_id) DO UPDATE SET
+			last_synced_at = EXCLUDED.last_synced_at`
+
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, query, sourceID, t)
+	return err
+}
+
+func (s *SyncStateStore) List(ctx context.Context) ([]domain.SyncState, error) {
+	query := `
+		SELECT id, source_id, last_synced_at, last_article_id, total_synced, last_published_at
+		FROM sync_state
+		ORDER BY source_id`
+
+	var states []domain.SyncState
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &states, query); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// Reset deletes the sync state of a source, so the next Get returns a fresh state.
+func (s *SyncStateStore) Reset(ctx context.Context, sourceID string) error {
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, "DELETE FROM sync_state WHERE source_id = $1", sourceID)
+	return err
 }
\ No newline at end of file
@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/jmoiron/sqlx"
@@ -10,18 +12,72 @@ import (
 	"news_fetcher/internal/domain"
 )
 
+// Tag identities: what makes two tags the same one.
+const (
+	// TagIdentityUpstream keys tags by the ID the source assigned.
+	TagIdentityUpstream = "upstream"
+	// TagIdentitySourceLabel keys tags by source and label.
+	TagIdentitySourceLabel = "source_label"
+	// TagIdentityLabel keys tags by label, across sources.
+	TagIdentityLabel = "label"
+)
+
 type TagStore struct {
 	db *sqlx.DB
+	// identity is one of the TagIdentity constants; see SetIdentity.
+	identity string
+	// normalizeLabels dedups label-keyed tags on their normalized labels; see
+	// NormalizeLabels.
+	normalizeLabels bool
 }
 
 func NewTagStore(db *sqlx.DB) *TagStore {
-	return &TagStore{db: db}
+	return &TagStore{db: db, identity: TagIdentityUpstream}
+}
+
+// SetIdentity sets what makes two tags the same one. With the default,
+// TagIdentityUpstream, tags are stored under the IDs the source assigned,
+// which collide between sources. With TagIdentitySourceLabel or
+// TagIdentityLabel they are keyed by label within their source or across
+// sources, and stored under IDs generated for them.
+func (s *TagStore) SetIdentity(identity string) error {
+	switch identity {
+	case "":
+		identity = TagIdentityUpstream
+	case TagIdentityUpstream, TagIdentitySourceLabel, TagIdentityLabel:
+	default:
+		return fmt.Errorf("unknown tag identity %q", identity)
+	}
+	s.identity = identity
+	return nil
+}
+
+// NormalizeLabels sets whether tags keyed by label are keyed by their
+// normalized label (see domain.NormalizeTagLabel), so variants such as "Test"
+// and "TEST" map to one tag. The tag keeps the label it was first stored
+// with. It has no effect on tags keyed by the upstream ID.
+func (s *TagStore) NormalizeLabels(normalize bool) {
+	s.normalizeLabels = normalize
 }
 
+// labelKey returns the key a label-keyed tag is unique on.
+func (s *TagStore) labelKey(label string) string {
+	if s.normalizeLabels {
+		return domain.NormalizeTagLabel(label)
+	}
+	return label
+}
+
+// UpsertBatch stores the tags. If they are keyed by label, it sets the ID of
+// each to the one stored for its label; LinkToArticle takes those IDs. Keyed
+// by source and label, the source is the one carried by ctx.
 func (s *TagStore) UpsertBatch(ctx context.Context, tags []domain.Tag) error {
 	if len(tags) == 0 {
 		return nil
 	}
+	if s.identity != TagIdentityUpstream {
+		return s.upsertByLabel(ctx, tags)
+	}
 
 	var sb strings.Builder
 	sb.WriteString("INSERT INTO tags (id, label) VALUES ")
@@ -40,12 +96,62 @@ func (s *TagStore) UpsertBatch(ctx context.Context, tags []domain.Tag) error {
 	}
 	sb.WriteString(" ON CONFLICT (id) DO UPDATE SET label = EXCLUDED.label")
 
-	_, err := s.db.ExecContext(ctx, sb.String(), valueArgs...)
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx, sb.String(), valueArgs...)
 	return err
 }
 
+// upsertByLabel stores the tags keyed by label in their scope, creating the
+// missing ones, and sets their IDs to the stored ones.
+func (s *TagStore) upsertByLabel(ctx context.Context, tags []domain.Tag) error {
+	scope := ""
+	if s.identity == TagIdentitySourceLabel {
+		scope = domain.SourceIDFromContext(ctx)
+		if scope == "" {
+			return errors.New("tags keyed by source and label need the source ID in the context")
+		}
+	}
+
+	// A row can't be upserted twice in one statement.
+	var keys, labels []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		key := s.labelKey(tag.Label)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+			labels = append(labels, tag.Label)
+		}
+	}
+
+	// The no-op update makes RETURNING include the tags already stored.
+	query := `
+		INSERT INTO tags (id, label, label_key, label_scope)
+		SELECT nextval('tags_label_id_seq'), t.label, t.label_key, $1
+		FROM unnest($2::text[], $3::text[]) AS t(label_key, label)
+		ON CONFLICT (label_scope, label_key) WHERE label_scope IS NOT NULL
+		DO UPDATE SET label = tags.label
+		RETURNING id, label_key`
+
+	var stored []struct {
+		ID  int64  `db:"id"`
+		Key string `db:"label_key"`
+	}
+	if err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &stored, query, scope, pq.Array(keys), pq.Array(labels)); err != nil {
+		return err
+	}
+
+	ids := make(map[string]int64, len(stored))
+	for _, tag := range stored {
+		ids[tag.Key] = tag.ID
+	}
+	for i := range tags {
+		tags[i].ID = ids[s.labelKey(tags[i].Label)]
+	}
+	return nil
+}
+
 func (s *TagStore) LinkToArticle(ctx context.Context, articleID int64, tagIDs []int64) error {
-	_, err := s.db.ExecContext(ctx,
+	_, err := GetExecutor(ctx, s.db).ExecContext(ctx,
 		"DELETE FROM article_tags WHERE article_id = $1",
 		articleID,
 	)
@@ -73,7 +179,7 @@ func (s *TagStore) LinkToArticle(ctx context.Context, articleID int64, tagIDs []
 	}
 	sb.WriteString(" ON CONFLICT DO NOTHING")
 
-	_, err = s.db.ExecContext(ctx, sb.String(), valueArgs...)
+	_, err = GetExecutor(ctx, s.db).ExecContext(ctx, sb.String(), valueArgs...)
 	return err
 }
 
@@ -85,7 +191,7 @@ func (s *TagStore) GetByArticleID(ctx context.Context, articleID int64) ([]domai
 		WHERE at.article_id = $1`
 
 	var tags []domain.Tag
-	err := s.db.SelectContext(ctx, &tags, query, articleID)
+	err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &tags, query, articleID)
 	return tags, err
 }
 
@@ -96,7 +202,7 @@ func (s *TagStore) GetTagIDsByExternalIDs(ctx context.Context, ids []int64) ([]i
 
 	query := `SELECT id FROM tags WHERE id = ANY($1)`
 	var result []int64
-	err := s.db.SelectContext(ctx, &result, query, pq.Array(ids))
+	err := sqlx.SelectContext(ctx, GetExecutor(ctx, s.db), &result, query, pq.Array(ids))
 	return result, err
 }
 
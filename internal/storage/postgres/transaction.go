@@ -2,13 +2,17 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/jmoiron/sqlx"
 )
 
 type ctxKey string
 
-const txKey ctxKey = "tx"
+const (
+	txKey        ctxKey = "tx"
+	savepointKey ctxKey = "savepoint"
+)
 
 type TransactionManager struct {
 	db *sqlx.DB
@@ -18,7 +22,14 @@ func NewTransactionManager(db *sqlx.DB) *TransactionManager {
 	return &TransactionManager{db: db}
 }
 
+// WithTransaction runs fn inside a transaction. If ctx already carries a
+// transaction, fn runs inside a savepoint of it instead, so an inner failure
+// only rolls back the inner work and the outer transaction decides the outcome.
 func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx := GetTxFromContext(ctx); tx != nil {
+		return tm.withSavepoint(ctx, tx, fn)
+	}
+
 	tx, err := tm.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
@@ -34,6 +45,28 @@ func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx c
 	return tx.Commit()
 }
 
+func (tm *TransactionManager) withSavepoint(ctx context.Context, tx *sqlx.Tx, fn func(ctx context.Context) error) error {
+	depth, _ := ctx.Value(savepointKey).(int)
+	depth++
+	name := fmt.Sprintf("sp_%d", depth)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("create savepoint: %w", err)
+	}
+
+	spCtx := context.WithValue(ctx, savepointKey, depth)
+
+	if err := fn(spCtx); err != nil {
+		_, _ = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("release savepoint: %w", err)
+	}
+	return nil
+}
+
 func GetTxFromContext(ctx context.Context) *sqlx.Tx {
 	tx, _ := ctx.Value(txKey).(*sqlx.Tx)
 	return tx
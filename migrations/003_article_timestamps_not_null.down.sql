@@ -0,0 +1,2 @@
+ALTER TABLE articles ALTER COLUMN updated_at DROP NOT NULL;
+ALTER TABLE articles ALTER COLUMN created_at DROP NOT NULL;
\ No newline at end of file
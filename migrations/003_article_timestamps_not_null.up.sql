@@ -0,0 +1,6 @@
+-- Backfill and enforce article timestamps
+UPDATE articles SET created_at = NOW() WHERE created_at IS NULL;
+UPDATE articles SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE articles ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE articles ALTER COLUMN updated_at SET NOT NULL;
\ No newline at end of file
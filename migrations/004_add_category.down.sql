@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_articles_category;
+ALTER TABLE articles DROP COLUMN IF EXISTS category;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Add article category
+ALTER TABLE articles ADD COLUMN category VARCHAR(50) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_articles_category ON articles(category);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS media;
\ No newline at end of file
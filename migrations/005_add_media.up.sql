@@ -0,0 +1,2 @@
+-- Add article media renditions
+ALTER TABLE articles ADD COLUMN media JSONB NOT NULL DEFAULT '[]';
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS raw_payloads;
\ No newline at end of file
@@ -0,0 +1,9 @@
+-- Store the raw upstream payload of each article so it can be re-transformed
+CREATE TABLE IF NOT EXISTS raw_payloads (
+    id          BIGSERIAL PRIMARY KEY,
+    source_id   VARCHAR(50) NOT NULL,
+    external_id BIGINT NOT NULL,
+    payload     JSONB NOT NULL,
+    fetched_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT raw_payloads_source_external_unique UNIQUE (source_id, external_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS language;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Add article language
+ALTER TABLE articles ADD COLUMN language VARCHAR(35) NOT NULL DEFAULT '';
\ No newline at end of file
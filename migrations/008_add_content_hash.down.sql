@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS content_hash;
\ No newline at end of file
@@ -0,0 +1,2 @@
+-- Hash of the article content, to skip updates that change nothing
+ALTER TABLE articles ADD COLUMN content_hash VARCHAR(64) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_articles_source_external ON articles(source_id, external_id);
\ No newline at end of file
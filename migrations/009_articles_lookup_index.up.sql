@@ -0,0 +1,13 @@
+-- The upsert's ON CONFLICT (source_id, external_id) and the existing-articles
+-- lookup both rely on this unique index. 002 adds it; make sure it exists.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM pg_constraint WHERE conname = 'articles_source_external_unique'
+    ) THEN
+        ALTER TABLE articles ADD CONSTRAINT articles_source_external_unique UNIQUE (source_id, external_id);
+    END IF;
+END $$;
+
+-- Duplicates the unique index above
+DROP INDEX IF EXISTS idx_articles_source_external;
\ No newline at end of file
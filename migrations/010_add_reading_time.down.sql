@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS reading_time;
\ No newline at end of file
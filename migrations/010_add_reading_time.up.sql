@@ -0,0 +1,2 @@
+-- Estimated reading time in minutes
+ALTER TABLE articles ADD COLUMN reading_time INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS failed_articles;
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Articles whose save keeps failing. Once quarantined_at is set, syncs skip
+-- the article until the row is cleared.
+CREATE TABLE IF NOT EXISTS failed_articles (
+    source_id       VARCHAR(50) NOT NULL,
+    external_id     BIGINT NOT NULL,
+    attempts        INTEGER NOT NULL,
+    last_error      TEXT NOT NULL,
+    article         JSONB NOT NULL,
+    first_failed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_failed_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    quarantined_at  TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (source_id, external_id)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS tags;
\ No newline at end of file
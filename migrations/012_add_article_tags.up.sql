@@ -0,0 +1,13 @@
+-- The tags each article should be linked to, so article_tags can be
+-- reconciled when linking failed
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS tags JSONB NOT NULL DEFAULT '[]';
+
+UPDATE articles a
+SET tags = linked.tags
+FROM (
+    SELECT at.article_id, jsonb_agg(jsonb_build_object('id', t.id, 'label', t.label) ORDER BY t.id) AS tags
+    FROM article_tags at
+    INNER JOIN tags t ON t.id = at.tag_id
+    GROUP BY at.article_id
+) linked
+WHERE linked.article_id = a.id;
\ No newline at end of file
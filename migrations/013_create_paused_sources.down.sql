@@ -0,0 +1 @@
+DROP TABLE IF EXISTS paused_sources;
\ No newline at end of file
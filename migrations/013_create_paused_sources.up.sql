@@ -0,0 +1,6 @@
+-- Sources paused at runtime through the admin API. A paused source isn't
+-- synced until it is resumed, also across restarts.
+CREATE TABLE IF NOT EXISTS paused_sources (
+    source_id VARCHAR(50) PRIMARY KEY,
+    paused_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS source_health;
\ No newline at end of file
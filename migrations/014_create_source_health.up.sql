@@ -0,0 +1,10 @@
+-- Aggregated sync outcome per source, kept across restarts.
+CREATE TABLE IF NOT EXISTS source_health (
+    source_id            VARCHAR(50) PRIMARY KEY,
+    last_success_at      TIMESTAMP WITH TIME ZONE,
+    last_failure_at      TIMESTAMP WITH TIME ZONE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    last_error           TEXT NOT NULL DEFAULT '',
+    runs                 INTEGER NOT NULL DEFAULT 0,
+    average_duration_ns  BIGINT NOT NULL DEFAULT 0
+);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE articles DROP COLUMN IF EXISTS publish_pending;
\ No newline at end of file
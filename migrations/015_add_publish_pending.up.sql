@@ -0,0 +1,3 @@
+-- Set on articles whose update was stored but not yet published because it
+-- was modified too recently (sync.quiet_period). Later syncs publish it.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS publish_pending BOOLEAN NOT NULL DEFAULT FALSE;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- Editorial status of an article, independent of upstream. Syncs create
+-- articles as published and never change the status of an archived one.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'published', 'archived'));
\ No newline at end of file
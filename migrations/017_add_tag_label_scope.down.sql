@@ -0,0 +1,5 @@
+DROP SEQUENCE IF EXISTS tags_label_id_seq;
+DROP INDEX IF EXISTS idx_tags_label_scope;
+DELETE FROM tags WHERE label_scope IS NOT NULL;
+ALTER TABLE tags DROP COLUMN IF EXISTS label_scope;
+ALTER TABLE tags ADD CONSTRAINT tags_label_key UNIQUE (label);
\ No newline at end of file
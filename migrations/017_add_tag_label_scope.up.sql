@@ -0,0 +1,11 @@
+-- Tags keyed by label instead of the upstream id (sync.tag_identity) record
+-- the scope their label is unique in: the source ID, or '' for labels shared
+-- by every source. Upstream-keyed tags have no scope, and their labels need
+-- no longer be unique, as sources may reuse them.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS label_scope VARCHAR(50);
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_label_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_label_scope ON tags(label_scope, label) WHERE label_scope IS NOT NULL;
+
+-- Label-keyed tags get their id from this sequence. It counts down from -1,
+-- away from the positive ids sources assign.
+CREATE SEQUENCE IF NOT EXISTS tags_label_id_seq INCREMENT BY -1 MAXVALUE -1 START WITH -1;
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tags_label_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_label_scope ON tags(label_scope, label) WHERE label_scope IS NOT NULL;
+ALTER TABLE tags DROP COLUMN IF EXISTS label_key;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- Label-keyed tags are unique on their label_key: the label itself or, with
+-- sync.normalize_tag_labels, its normalized form, so that variants of a label
+-- share one tag. label keeps the label the tag was first stored with.
+ALTER TABLE tags ADD COLUMN IF NOT EXISTS label_key VARCHAR(255);
+UPDATE tags SET label_key = label WHERE label_scope IS NOT NULL AND label_key IS NULL;
+DROP INDEX IF EXISTS idx_tags_label_scope;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_label_key ON tags(label_scope, label_key) WHERE label_scope IS NOT NULL;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE source_health DROP COLUMN IF EXISTS last_check_error;
+ALTER TABLE source_health DROP COLUMN IF EXISTS last_check_at;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- The outcome of the last health check of each source.
+ALTER TABLE source_health ADD COLUMN IF NOT EXISTS last_check_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE source_health ADD COLUMN IF NOT EXISTS last_check_error TEXT NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE sync_state DROP COLUMN IF EXISTS last_published_at;
\ No newline at end of file
@@ -0,0 +1,5 @@
+-- The newest published_at of the articles synced per source, for freshness
+-- monitoring.
+ALTER TABLE sync_state ADD COLUMN IF NOT EXISTS last_published_at TIMESTAMP WITH TIME ZONE;
+UPDATE sync_state s
+SET last_published_at = (SELECT MAX(a.published_at) FROM articles a WHERE a.source_id = s.source_id);
\ No newline at end of file
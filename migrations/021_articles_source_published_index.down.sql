@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_articles_source_published;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Serves counts and deletes of a source's articles by publication date
+-- (CountByDateRange, DeleteOlderThan) and listings filtered by source.
+CREATE INDEX IF NOT EXISTS idx_articles_source_published ON articles(source_id, published_at);
\ No newline at end of file
@@ -0,0 +1,13 @@
+-- Prior versions of articles, recorded on update when sync.keep_revisions is
+-- set.
+CREATE TABLE IF NOT EXISTS article_revisions (
+    id            BIGSERIAL PRIMARY KEY,
+    article_id    BIGINT NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    title         TEXT NOT NULL,
+    summary       TEXT,
+    body          TEXT,
+    last_modified TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_article_revisions_article_id ON article_revisions(article_id);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS publish_sequences;
\ No newline at end of file
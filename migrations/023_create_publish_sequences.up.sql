@@ -0,0 +1,6 @@
+-- The last sequence number published messages of each source were stamped
+-- with, when publisher.sequence_numbers is set.
+CREATE TABLE IF NOT EXISTS publish_sequences (
+    source_id     VARCHAR(50) PRIMARY KEY,
+    last_sequence BIGINT NOT NULL
+);
\ No newline at end of file
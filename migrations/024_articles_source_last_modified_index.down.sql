@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_articles_source_last_modified;
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Serves the existing-articles lookup of incremental syncs, which is bounded
+-- by last_modified.
+CREATE INDEX IF NOT EXISTS idx_articles_source_last_modified ON articles(source_id, last_modified);
\ No newline at end of file
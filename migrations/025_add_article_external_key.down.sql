@@ -0,0 +1,4 @@
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_source_external_key_unique;
+DROP TRIGGER IF EXISTS set_articles_external_key ON articles;
+DROP FUNCTION IF EXISTS set_article_external_key();
+ALTER TABLE articles DROP COLUMN IF EXISTS external_key;
\ No newline at end of file
@@ -0,0 +1,24 @@
+-- The key that identifies an article within its source. Sources with numeric
+-- IDs use them as the key, which is also what inserts without a key get.
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS external_key TEXT;
+UPDATE articles SET external_key = external_id::text WHERE external_key IS NULL;
+ALTER TABLE articles ALTER COLUMN external_key SET NOT NULL;
+
+CREATE OR REPLACE FUNCTION set_article_external_key()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.external_key IS NULL THEN
+        NEW.external_key = NEW.external_id::text;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS set_articles_external_key ON articles;
+CREATE TRIGGER set_articles_external_key
+    BEFORE INSERT ON articles
+    FOR EACH ROW
+    EXECUTE FUNCTION set_article_external_key();
+
+-- The upsert's ON CONFLICT (source_id, external_key) relies on this.
+ALTER TABLE articles ADD CONSTRAINT articles_source_external_key_unique UNIQUE (source_id, external_key);
\ No newline at end of file
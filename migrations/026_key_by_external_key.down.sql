@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS set_failed_articles_external_key ON failed_articles;
+DROP TRIGGER IF EXISTS set_raw_payloads_external_key ON raw_payloads;
+
+ALTER TABLE failed_articles DROP CONSTRAINT IF EXISTS failed_articles_pkey;
+ALTER TABLE failed_articles ADD PRIMARY KEY (source_id, external_id);
+ALTER TABLE failed_articles DROP COLUMN IF EXISTS external_key;
+
+ALTER TABLE raw_payloads DROP CONSTRAINT IF EXISTS raw_payloads_source_external_key_unique;
+ALTER TABLE raw_payloads ADD CONSTRAINT raw_payloads_source_external_unique UNIQUE (source_id, external_id);
+ALTER TABLE raw_payloads DROP COLUMN IF EXISTS external_key;
+
+DROP INDEX IF EXISTS idx_articles_source_external;
+ALTER TABLE articles ADD CONSTRAINT articles_source_external_unique UNIQUE (source_id, external_id);
\ No newline at end of file
@@ -0,0 +1,36 @@
+-- Articles are identified by (source_id, external_key). A source without
+-- numeric IDs derives external_id from the key, and two keys can derive the
+-- same one, so external_id is no longer unique within a source; the raw
+-- payloads and failures of an article are keyed the same way.
+ALTER TABLE articles DROP CONSTRAINT IF EXISTS articles_source_external_unique;
+CREATE INDEX IF NOT EXISTS idx_articles_source_external ON articles(source_id, external_id);
+
+ALTER TABLE raw_payloads ADD COLUMN IF NOT EXISTS external_key TEXT;
+UPDATE raw_payloads r SET external_key = a.external_key
+FROM articles a
+WHERE r.external_key IS NULL AND a.source_id = r.source_id AND a.external_id = r.external_id;
+UPDATE raw_payloads SET external_key = external_id::text WHERE external_key IS NULL;
+ALTER TABLE raw_payloads ALTER COLUMN external_key SET NOT NULL;
+ALTER TABLE raw_payloads DROP CONSTRAINT IF EXISTS raw_payloads_source_external_unique;
+ALTER TABLE raw_payloads ADD CONSTRAINT raw_payloads_source_external_key_unique UNIQUE (source_id, external_key);
+
+ALTER TABLE failed_articles ADD COLUMN IF NOT EXISTS external_key TEXT;
+UPDATE failed_articles
+SET external_key = COALESCE(NULLIF(article->>'external_key', ''), external_id::text)
+WHERE external_key IS NULL;
+ALTER TABLE failed_articles ALTER COLUMN external_key SET NOT NULL;
+ALTER TABLE failed_articles DROP CONSTRAINT IF EXISTS failed_articles_pkey;
+ALTER TABLE failed_articles ADD PRIMARY KEY (source_id, external_key);
+
+-- Inserts without a key get the external ID, as they do on articles (025).
+DROP TRIGGER IF EXISTS set_raw_payloads_external_key ON raw_payloads;
+CREATE TRIGGER set_raw_payloads_external_key
+    BEFORE INSERT ON raw_payloads
+    FOR EACH ROW
+    EXECUTE FUNCTION set_article_external_key();
+
+DROP TRIGGER IF EXISTS set_failed_articles_external_key ON failed_articles;
+CREATE TRIGGER set_failed_articles_external_key
+    BEFORE INSERT ON failed_articles
+    FOR EACH ROW
+    EXECUTE FUNCTION set_article_external_key();
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- Serves the lookup of updates still to be published, run by every sync; few
+-- articles are pending at any time.
+CREATE INDEX IF NOT EXISTS idx_articles_source_publish_pending ON articles(source_id) WHERE publish_pending;
\ No newline at end of file
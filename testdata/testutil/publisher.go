@@ -0,0 +1,87 @@
+package testutil
+
+import (
+	"context"
+	"sync"
+
+	"news_fetcher/internal/domain"
+)
+
+// Published is one recorded Publish call.
+type Published struct {
+	Article domain.Article
+	IsNew   bool
+}
+
+// Publisher is an in-memory publisher for tests. It records every Publish
+// call in order, with a copy of the article as it was when published, so
+// tests can assert the whole sequence of events of a run.
+type Publisher struct {
+	mu        sync.Mutex
+	published []Published
+	closed    bool
+}
+
+func NewPublisher() *Publisher {
+	return &Publisher{}
+}
+
+func (p *Publisher) Publish(ctx context.Context, article *domain.Article, isNew bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = append(p.published, Published{Article: *article, IsNew: isNew})
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	return nil
+}
+
+// Published returns the recorded calls in the order they were made.
+func (p *Publisher) Published() []Published {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Published(nil), p.published...)
+}
+
+// ExternalIDs returns the external IDs of the published articles, in order.
+func (p *Publisher) ExternalIDs() []int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]int64, len(p.published))
+	for i, pub := range p.published {
+		ids[i] = pub.Article.ExternalID
+	}
+	return ids
+}
+
+// Len returns the number of recorded calls.
+func (p *Publisher) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.published)
+}
+
+// Reset forgets the recorded calls.
+func (p *Publisher) Reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.published = nil
+}
+
+// Closed reports whether Close was called.
+func (p *Publisher) Closed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.closed
+}